@@ -0,0 +1,685 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const apiPrefix = "/api/v1"
+
+// GetIndex returns the available dates per brand
+func (c *Client) GetIndex(ctx context.Context) (*IndexData, error) {
+	var out IndexData
+	if err := c.get(ctx, apiPrefix+"/index", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// segmentValues returns the ?segment= query of a segment filter: comma-separated
+// segment codes (C-SUV), sizes (C) or body types (SUV); empty means all
+func segmentValues(segment string) url.Values {
+	q := url.Values{}
+	if segment != "" {
+		q.Set("segment", segment)
+	}
+	return q
+}
+
+// GetLatest returns the latest price list of every brand
+func (c *Client) GetLatest(ctx context.Context) (*LatestData, error) {
+	return c.GetLatestInSegment(ctx, "")
+}
+
+// GetLatestInSegment returns the latest price list of every brand restricted
+// to segments, e.g. "C-SUV" or "SUV,LCV"
+func (c *Client) GetLatestInSegment(ctx context.Context, segment string) (*LatestData, error) {
+	var out LatestData
+	if err := c.get(ctx, apiPrefix+"/latest", segmentValues(segment), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetVehicles returns the price list of a brand on a date (YYYY-MM-DD)
+func (c *Client) GetVehicles(ctx context.Context, brand, date string) (*StoredData, error) {
+	return c.GetVehiclesInSegment(ctx, brand, date, "")
+}
+
+// GetVehiclesInSegment returns the rows of a brand's price list on a date
+// that fall in the given segments
+func (c *Client) GetVehiclesInSegment(ctx context.Context, brand, date, segment string) (*StoredData, error) {
+	q := segmentValues(segment)
+	q.Set("brand", brand)
+	q.Set("date", date)
+
+	var out StoredData
+	if err := c.get(ctx, apiPrefix+"/vehicles", q, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetFilledVehicles returns a brand's forward-filled price list on a calendar
+// day; rows not observed that day are marked filled
+func (c *Client) GetFilledVehicles(ctx context.Context, brand, date string) (*DailyDocument, error) {
+	q := url.Values{}
+	q.Set("brand", brand)
+	q.Set("date", date)
+	q.Set("fill", "forward")
+
+	var out DailyDocument
+	if err := c.get(ctx, apiPrefix+"/vehicles", q, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// TrendQuery identifies the vehicle whose price history is requested, either
+// by VariantID or by Brand, Model, Trim and Engine
+type TrendQuery struct {
+	VariantID string
+	Brand     string
+	Model     string
+	Trim      string
+	Engine    string
+	Days      int  // optional look-back window
+	Fill      bool // carry prices forward over days without a snapshot
+	Exact     bool // match the labels exactly instead of following renames
+}
+
+func (q TrendQuery) values() url.Values {
+	v := url.Values{}
+	if q.VariantID != "" {
+		v.Set("variantId", q.VariantID)
+	} else {
+		v.Set("brand", q.Brand)
+		v.Set("model", q.Model)
+		v.Set("trim", q.Trim)
+		v.Set("engine", q.Engine)
+	}
+	if q.Exact {
+		v.Set("lineage", "false")
+	}
+	if q.Days > 0 {
+		v.Set("days", strconv.Itoa(q.Days))
+	}
+	if q.Fill {
+		v.Set("fill", "forward")
+	}
+	return v
+}
+
+// GetTrend returns the price history of a single vehicle
+func (c *Client) GetTrend(ctx context.Context, q TrendQuery) ([]TrendPoint, error) {
+	var out TrendResponse
+	if err := c.get(ctx, apiPrefix+"/trend", q.values(), &out); err != nil {
+		return nil, err
+	}
+	return out.Points, nil
+}
+
+// GetVariantTrend returns the price history of a single vehicle together with
+// its variant id and the labels it was listed under
+func (c *Client) GetVariantTrend(ctx context.Context, q TrendQuery) (*TrendResponse, error) {
+	var out TrendResponse
+	if err := c.get(ctx, apiPrefix+"/trend", q.values(), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ForecastQuery selects a price forecast. VehicleID is required; zero values
+// of the rest use the server defaults (30 days at 80% intervals).
+type ForecastQuery struct {
+	VehicleID string
+	Horizon   int // days
+	Level     int // 80, 90 or 95
+}
+
+func (q ForecastQuery) values() url.Values {
+	v := url.Values{}
+	v.Set("vehicleId", q.VehicleID)
+	if q.Horizon > 0 {
+		v.Set("horizon", strconv.Itoa(q.Horizon))
+	}
+	if q.Level > 0 {
+		v.Set("level", strconv.Itoa(q.Level))
+	}
+	return v
+}
+
+// GetForecast returns a vehicle's price forecast from each model with its
+// backtest error
+func (c *Client) GetForecast(ctx context.Context, q ForecastQuery) (*ForecastResponse, error) {
+	var out ForecastResponse
+	if err := c.get(ctx, apiPrefix+"/forecast", q.values(), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CoverageQuery selects the coverage calendar window. Zero values use the
+// server defaults (90 days ending at the newest snapshot, all brands).
+type CoverageQuery struct {
+	Days  int
+	To    string // YYYY-MM-DD
+	Brand string
+}
+
+func (q CoverageQuery) values() url.Values {
+	v := url.Values{}
+	if q.Days > 0 {
+		v.Set("days", strconv.Itoa(q.Days))
+	}
+	if q.To != "" {
+		v.Set("to", q.To)
+	}
+	if q.Brand != "" {
+		v.Set("brand", q.Brand)
+	}
+	return v
+}
+
+// GetCoverage returns per-brand calendars of present and missing snapshot dates
+func (c *Client) GetCoverage(ctx context.Context, q CoverageQuery) (*CoverageData, error) {
+	var out CoverageData
+	if err := c.get(ctx, apiPrefix+"/coverage", q.values(), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// StalenessQuery selects the staleness window. Zero values use the server
+// defaults (30 days up to the newest snapshot, all brands).
+type StalenessQuery struct {
+	Days  int
+	To    string // YYYY-MM-DD
+	Brand string
+}
+
+func (q StalenessQuery) values() url.Values {
+	v := url.Values{}
+	if q.Days > 0 {
+		v.Set("days", strconv.Itoa(q.Days))
+	}
+	if q.To != "" {
+		v.Set("to", q.To)
+	}
+	if q.Brand != "" {
+		v.Set("brand", q.Brand)
+	}
+	return v
+}
+
+// GetStaleness returns when each brand's and model's prices last changed,
+// looking back at most 400 days, and which sources look frozen
+func (c *Client) GetStaleness(ctx context.Context, q StalenessQuery) (*StalenessData, error) {
+	var out StalenessData
+	if err := c.get(ctx, apiPrefix+"/coverage/staleness", q.values(), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RankingsQuery selects a value ranking. Metric is required (tlPerHP,
+// tlPerKWh, tlPerKm or tlPerLiter); zero values of the rest use the server
+// defaults: ascending, the newest data, the whole market and 50 entries.
+type RankingsQuery struct {
+	Metric  string
+	Order   string // asc or desc
+	Date    string // YYYY-MM-DD
+	Brand   string
+	Fuel    string // canonical fuel, e.g. electric
+	Segment string
+	Limit   int
+}
+
+func (q RankingsQuery) values() url.Values {
+	v := url.Values{}
+	for key, value := range map[string]string{
+		"metric": q.Metric, "order": q.Order, "date": q.Date, "brand": q.Brand, "fuel": q.Fuel, "segment": q.Segment,
+	} {
+		if value != "" {
+			v.Set(key, value)
+		}
+	}
+	if q.Limit > 0 {
+		v.Set("limit", strconv.Itoa(q.Limit))
+	}
+	return v
+}
+
+// GetRankings ranks the market by a value-for-money metric
+func (c *Client) GetRankings(ctx context.Context, q RankingsQuery) (*RankingsResponse, error) {
+	var out RankingsResponse
+	if err := c.get(ctx, apiPrefix+"/rankings", q.values(), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetStats returns the latest precomputed statistics
+func (c *Client) GetStats(ctx context.Context) (*StatsData, error) {
+	var out StatsData
+	if err := c.get(ctx, apiPrefix+"/stats", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetEvents returns the latest price change events
+func (c *Client) GetEvents(ctx context.Context) (*EventsData, error) {
+	return c.GetEventsInSegment(ctx, "")
+}
+
+// GetEventsInSegment returns the latest price change events of
+// vehicles in the given segments
+func (c *Client) GetEventsInSegment(ctx context.Context, segment string) (*EventsData, error) {
+	var out EventsData
+	if err := c.get(ctx, apiPrefix+"/intel/events", segmentValues(segment), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetArchitecture returns the latest trim ladders and cross-brand comparison
+func (c *Client) GetArchitecture(ctx context.Context) (*ArchitectureData, error) {
+	return c.GetArchitectureInSegment(ctx, "")
+}
+
+// GetArchitectureInSegment returns the latest trim ladders of
+// vehicles in the given segments
+func (c *Client) GetArchitectureInSegment(ctx context.Context, segment string) (*ArchitectureData, error) {
+	var out ArchitectureData
+	if err := c.get(ctx, apiPrefix+"/intel/architecture", segmentValues(segment), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ModelArchitectureQuery selects the dates of a model's ladder. Zero values
+// use the newest data and skip the comparison.
+type ModelArchitectureQuery struct {
+	Date    string // YYYY-MM-DD
+	Compare string // YYYY-MM-DD
+}
+
+func (q ModelArchitectureQuery) values() url.Values {
+	v := url.Values{}
+	if q.Date != "" {
+		v.Set("date", q.Date)
+	}
+	if q.Compare != "" {
+		v.Set("compare", q.Compare)
+	}
+	return v
+}
+
+// GetModelArchitecture computes a model's trim ladder, optionally with how it
+// changed since another date
+func (c *Client) GetModelArchitecture(ctx context.Context, brand, model string, q ModelArchitectureQuery) (*ModelArchitecture, error) {
+	var out ModelArchitecture
+	path := apiPrefix + "/intel/architecture/" + url.PathEscape(brand) + "/" + url.PathEscape(model)
+	if err := c.get(ctx, path, q.values(), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetGaps returns the latest market gap heatmap
+func (c *Client) GetGaps(ctx context.Context) (*GapsData, error) {
+	var out GapsData
+	if err := c.get(ctx, apiPrefix+"/intel/gaps", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GapAnalysisQuery configures a custom gap heatmap. Zero values use the
+// server defaults: the newest data and the generator's segment × fuel ×
+// transmission cells and price ranges.
+type GapAnalysisQuery struct {
+	Date       string    // YYYY-MM-DD
+	Dimensions []string  // e.g. segment, driveType, seating, evRange
+	PriceEdges []float64 // ascending band edges in TL
+	RangeEdges []float64 // ascending EV range bucket edges in km
+	Segment    string    // restricts the market, e.g. "SUV"
+}
+
+func (q GapAnalysisQuery) values() url.Values {
+	v := url.Values{}
+	if q.Date != "" {
+		v.Set("date", q.Date)
+	}
+	if len(q.Dimensions) > 0 {
+		v.Set("dimensions", strings.Join(q.Dimensions, ","))
+	}
+	for key, edges := range map[string][]float64{"priceEdges": q.PriceEdges, "rangeEdges": q.RangeEdges} {
+		if len(edges) == 0 {
+			continue
+		}
+		parts := make([]string, len(edges))
+		for i, e := range edges {
+			parts[i] = strconv.FormatFloat(e, 'f', -1, 64)
+		}
+		v.Set(key, strings.Join(parts, ","))
+	}
+	if q.Segment != "" {
+		v.Set("segment", q.Segment)
+	}
+	return v
+}
+
+// GetGapAnalysis computes a gap heatmap over custom dimensions and price bands
+func (c *Client) GetGapAnalysis(ctx context.Context, q GapAnalysisQuery) (*GapAnalysis, error) {
+	var out GapAnalysis
+	if err := c.get(ctx, apiPrefix+"/intel/gaps/analyze", q.values(), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetPromos returns the latest price drops and promotions
+func (c *Client) GetPromos(ctx context.Context) (*PromosData, error) {
+	return c.GetPromosInSegment(ctx, "")
+}
+
+// GetPromosInSegment returns the latest price drops and promotions of
+// vehicles in the given segments
+func (c *Client) GetPromosInSegment(ctx context.Context, segment string) (*PromosData, error) {
+	var out PromosData
+	if err := c.get(ctx, apiPrefix+"/intel/promos", segmentValues(segment), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetLifecycle returns the latest model lifecycle data
+func (c *Client) GetLifecycle(ctx context.Context) (*LifecycleData, error) {
+	return c.GetLifecycleInSegment(ctx, "")
+}
+
+// GetLifecycleInSegment returns the latest model lifecycle data of
+// vehicles in the given segments
+func (c *Client) GetLifecycleInSegment(ctx context.Context, segment string) (*LifecycleData, error) {
+	var out LifecycleData
+	if err := c.get(ctx, apiPrefix+"/intel/lifecycle", segmentValues(segment), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ErrorQuery filters the collector error log. Zero values use the server
+// defaults (the last 7 days, first page of 100).
+type ErrorQuery struct {
+	Category string
+	Severity string
+	Source   string
+	Brand    string
+	From     string // YYYY-MM-DD
+	To       string // YYYY-MM-DD
+	Page     int
+	PageSize int
+}
+
+func (q ErrorQuery) values() url.Values {
+	v := url.Values{}
+	for key, value := range map[string]string{
+		"category": q.Category, "severity": q.Severity, "source": q.Source,
+		"brand": q.Brand, "from": q.From, "to": q.To,
+	} {
+		if value != "" {
+			v.Set(key, value)
+		}
+	}
+	if q.Page > 0 {
+		v.Set("page", strconv.Itoa(q.Page))
+	}
+	if q.PageSize > 0 {
+		v.Set("pageSize", strconv.Itoa(q.PageSize))
+	}
+	return v
+}
+
+// GetErrors returns a page of the collector error log with counts over time
+func (c *Client) GetErrors(ctx context.Context, q ErrorQuery) (*ErrorLogResponse, error) {
+	var out ErrorLogResponse
+	if err := c.get(ctx, apiPrefix+"/errors", q.values(), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AnomalyQuery filters anomaly guard verdicts. Zero values match everything.
+type AnomalyQuery struct {
+	Brand   string
+	Verdict string // "flagged" or "rejected"
+	From    string // YYYY-MM-DD
+	To      string // YYYY-MM-DD
+	Limit   int
+}
+
+func (q AnomalyQuery) values() url.Values {
+	v := url.Values{}
+	for key, value := range map[string]string{"brand": q.Brand, "verdict": q.Verdict, "from": q.From, "to": q.To} {
+		if value != "" {
+			v.Set(key, value)
+		}
+	}
+	if q.Limit > 0 {
+		v.Set("limit", strconv.Itoa(q.Limit))
+	}
+	return v
+}
+
+// GetAnomalies returns the day-over-day anomaly verdicts of imported snapshots
+func (c *Client) GetAnomalies(ctx context.Context, q AnomalyQuery) (*AnomaliesData, error) {
+	var out AnomaliesData
+	if err := c.get(ctx, apiPrefix+"/anomalies", q.values(), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ModelYearChangesQuery filters model-year transitions. From and To select
+// by the date the new year first appeared; zero values match everything.
+type ModelYearChangesQuery struct {
+	Brand   string
+	From    string // YYYY-MM-DD
+	To      string // YYYY-MM-DD
+	Segment string // e.g. "C-SUV" or "SUV,LCV"
+}
+
+func (q ModelYearChangesQuery) values() url.Values {
+	v := url.Values{}
+	if q.Brand != "" {
+		v.Set("brand", q.Brand)
+	}
+	if q.From != "" {
+		v.Set("from", q.From)
+	}
+	if q.To != "" {
+		v.Set("to", q.To)
+	}
+	if q.Segment != "" {
+		v.Set("segment", q.Segment)
+	}
+	return v
+}
+
+// GetModelYearChanges returns model-year transitions detected over the
+// snapshot history by cmd/modelyears, newest first
+func (c *Client) GetModelYearChanges(ctx context.Context, q ModelYearChangesQuery) (*ModelYearChangesData, error) {
+	var out ModelYearChangesData
+	if err := c.get(ctx, apiPrefix+"/intel/lifecycle/transitions", q.values(), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ReactionsQuery scopes a competitor-reaction analysis. VehicleID and Segment
+// are alternatives; zero values use the server defaults (the whole market over
+// the 180 days before To, a 14-day window and 0.5% moves). From and To may be
+// at most 730 days apart.
+type ReactionsQuery struct {
+	VehicleID string
+	Segment   string  // e.g. "C-SUV" or "SUV,LCV"
+	Window    int     // days
+	MinMove   float64 // percent
+	From      string  // YYYY-MM-DD
+	To        string  // YYYY-MM-DD
+}
+
+func (q ReactionsQuery) values() url.Values {
+	v := url.Values{}
+	if q.VehicleID != "" {
+		v.Set("vehicleId", q.VehicleID)
+	}
+	if q.Segment != "" {
+		v.Set("segment", q.Segment)
+	}
+	if q.Window > 0 {
+		v.Set("window", strconv.Itoa(q.Window))
+	}
+	if q.MinMove > 0 {
+		v.Set("minMove", strconv.FormatFloat(q.MinMove, 'f', -1, 64))
+	}
+	if q.From != "" {
+		v.Set("from", q.From)
+	}
+	if q.To != "" {
+		v.Set("to", q.To)
+	}
+	return v
+}
+
+// GetReactions returns the competitor reaction matrix for a vehicle or segment
+func (c *Client) GetReactions(ctx context.Context, q ReactionsQuery) (*ReactionAnalysis, error) {
+	var out ReactionAnalysis
+	if err := c.get(ctx, apiPrefix+"/intel/reactions", q.values(), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CampaignQuery filters campaign episodes. Zero values match everything;
+// Active nil returns running and ended campaigns.
+type CampaignQuery struct {
+	Brand  string
+	Kind   string // listed or dip
+	Active *bool
+	Limit  int
+}
+
+func (q CampaignQuery) values() url.Values {
+	v := url.Values{}
+	if q.Brand != "" {
+		v.Set("brand", q.Brand)
+	}
+	if q.Kind != "" {
+		v.Set("kind", q.Kind)
+	}
+	if q.Active != nil {
+		v.Set("active", strconv.FormatBool(*q.Active))
+	}
+	if q.Limit > 0 {
+		v.Set("limit", strconv.Itoa(q.Limit))
+	}
+	return v
+}
+
+// GetCampaigns returns detected campaign episodes with per-brand statistics
+func (c *Client) GetCampaigns(ctx context.Context, q CampaignQuery) (*CampaignsData, error) {
+	var out CampaignsData
+	if err := c.get(ctx, apiPrefix+"/campaigns", q.values(), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CollectionHealthQuery selects the collection health window. Zero values use
+// the server defaults (the last 30 days, all brands).
+type CollectionHealthQuery struct {
+	Days  int
+	To    string // YYYY-MM-DD
+	Brand string
+}
+
+func (q CollectionHealthQuery) values() url.Values {
+	v := url.Values{}
+	if q.Days > 0 {
+		v.Set("days", strconv.Itoa(q.Days))
+	}
+	if q.To != "" {
+		v.Set("to", q.To)
+	}
+	if q.Brand != "" {
+		v.Set("brand", q.Brand)
+	}
+	return v
+}
+
+// GetCollectionHealth returns per-brand collector health over a window of days
+func (c *Client) GetCollectionHealth(ctx context.Context, q CollectionHealthQuery) (*CollectionHealthData, error) {
+	var out CollectionHealthData
+	if err := c.get(ctx, apiPrefix+"/health/collection", q.values(), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetInsights returns the latest deal scores and outliers
+func (c *Client) GetInsights(ctx context.Context) (*InsightsData, error) {
+	return c.GetInsightsInSegment(ctx, "")
+}
+
+// GetInsightsInSegment returns the latest deal scores and outliers of
+// vehicles in the given segments
+func (c *Client) GetInsightsInSegment(ctx context.Context, segment string) (*InsightsData, error) {
+	var out InsightsData
+	if err := c.get(ctx, apiPrefix+"/insights", segmentValues(segment), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ScoreQuery configures an on-demand scoring run. Zero values use the server
+// defaults: the newest data, segment_fuel peers and price-only scores.
+type ScoreQuery struct {
+	VehicleID string // variant id; selects one vehicle and its peer group
+	PeerGroup string // segment, segment_fuel or price_band
+	Date      string // YYYY-MM-DD
+	Weights   ScoreWeights
+	Segment   string // restricts the market lists, e.g. "C-SUV"
+}
+
+func (q ScoreQuery) values() url.Values {
+	v := url.Values{}
+	for key, value := range map[string]string{
+		"vehicleId": q.VehicleID, "peerGroup": q.PeerGroup, "date": q.Date, "segment": q.Segment,
+	} {
+		if value != "" {
+			v.Set(key, value)
+		}
+	}
+	for key, w := range map[string]float64{
+		"campaignWeight": q.Weights.CampaignDiscount, "hpWeight": q.Weights.TLPerHP, "rangeWeight": q.Weights.TLPerKm,
+	} {
+		if w > 0 {
+			v.Set(key, strconv.FormatFloat(w, 'f', -1, 64))
+		}
+	}
+	return v
+}
+
+// GetScore recomputes deal scores with the given peer group and weights
+func (c *Client) GetScore(ctx context.Context, q ScoreQuery) (*ScoreResponse, error) {
+	var out ScoreResponse
+	if err := c.get(ctx, apiPrefix+"/insights/score", q.values(), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,340 @@
+// Package client is a Go SDK for the price-list HTTP API.
+//
+//	c := client.New("https://api.example.com")
+//	latest, err := c.GetLatest(ctx)
+//
+// Responses decode into the same model types the server uses (re-exported in
+// this package), requests honor context cancellation, transient failures are
+// retried with exponential backoff and GET responses are cached by ETag.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRetries     = 3
+	defaultBackoff     = 200 * time.Millisecond
+	defaultMaxBackoff  = 5 * time.Second
+	defaultCacheSize   = 256
+	defaultHTTPTimeout = 30 * time.Second
+)
+
+// Client calls the price-list API. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	userAgent  string
+	headers    http.Header
+	retries    int
+	backoff    time.Duration
+	maxBackoff time.Duration
+
+	cache *etagCache
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the underlying http.Client (transport, timeouts, proxies)
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		if hc != nil {
+			c.httpClient = hc
+		}
+	}
+}
+
+// WithRetries sets how many times a failed request is retried (0 disables retries)
+// and the initial backoff, which doubles after every attempt
+func WithRetries(retries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		if retries >= 0 {
+			c.retries = retries
+		}
+		if backoff > 0 {
+			c.backoff = backoff
+		}
+	}
+}
+
+// WithCacheSize sets the number of ETag-cached responses kept in memory (0 disables caching)
+func WithCacheSize(size int) Option {
+	return func(c *Client) {
+		if size <= 0 {
+			c.cache = nil
+			return
+		}
+		c.cache = newETagCache(size)
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request
+func WithUserAgent(ua string) Option {
+	return func(c *Client) {
+		c.userAgent = ua
+	}
+}
+
+// WithAPIKey authenticates every request with the given API key
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.headers.Set("X-API-Key", key)
+	}
+}
+
+// WithHeader adds a header sent with every request
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		c.headers.Add(key, value)
+	}
+}
+
+// New creates a client for the API served at baseURL (e.g. https://host, without /api/v1)
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: defaultHTTPTimeout},
+		userAgent:  "price-list-go-client",
+		headers:    make(http.Header),
+		retries:    defaultRetries,
+		backoff:    defaultBackoff,
+		maxBackoff: defaultMaxBackoff,
+		cache:      newETagCache(defaultCacheSize),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// APIError is returned for non-2xx responses
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("price-list api: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("price-list api: %d %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound reports whether err is an APIError with status 404
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// get performs a GET request against path (relative to the base URL) and decodes
+// the JSON response into out
+func (c *Client) get(ctx context.Context, path string, query url.Values, out any) error {
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	body, err := c.fetch(ctx, u)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("price-list api: decoding %s: %w", path, err)
+	}
+	return nil
+}
+
+// fetch returns the response body for u, retrying transient failures and
+// revalidating cached responses with If-None-Match
+func (c *Client) fetch(ctx context.Context, u string) ([]byte, error) {
+	var lastErr error
+	for attempt := 0; attempt <= c.retries; attempt++ {
+		if attempt > 0 {
+			if err := sleep(ctx, c.retryDelay(attempt, lastErr)); err != nil {
+				return nil, err
+			}
+		}
+
+		body, retry, err := c.do(ctx, u, true)
+		if err == nil {
+			return body, nil
+		}
+		if !retry || ctx.Err() != nil {
+			return nil, err
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// do performs a single attempt. retry reports whether the failure is transient.
+// Without conditional the request carries no validators and bypasses the cache.
+func (c *Client) do(ctx context.Context, u string, conditional bool) (body []byte, retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	for key, values := range c.headers {
+		req.Header[key] = values
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+
+	var cached cachedResponse
+	var hasCached bool
+	if conditional {
+		if cached, hasCached = c.cache.get(u); hasCached {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+	} else {
+		req.Header.Del("If-None-Match")
+		req.Header.Del("If-Modified-Since")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && conditional {
+		if hasCached {
+			return cached.body, false, nil
+		}
+		// Nothing cached to reuse (a validator set with WithHeader, or an
+		// entry evicted meanwhile): ask once more for the full response
+		io.Copy(io.Discard, resp.Body)
+		return c.do(ctx, u, false)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, true, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		var payload struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(data, &payload) == nil {
+			apiErr.Message = payload.Error
+		}
+		return nil, retryable(resp.StatusCode), &retryAfterError{APIError: apiErr, after: parseRetryAfter(resp.Header.Get("Retry-After"))}
+	}
+
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		c.cache.put(u, cachedResponse{etag: etag, body: data})
+	}
+	return data, false, nil
+}
+
+// retryAfterError carries the server's Retry-After hint alongside the APIError
+type retryAfterError struct {
+	*APIError
+	after time.Duration
+}
+
+func (e *retryAfterError) Unwrap() error { return e.APIError }
+
+func retryable(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryDelay returns the wait before the given attempt, preferring the server's Retry-After
+func (c *Client) retryDelay(attempt int, lastErr error) time.Duration {
+	var raErr *retryAfterError
+	if errors.As(lastErr, &raErr) && raErr.after > 0 {
+		return min(raErr.after, c.maxBackoff)
+	}
+	delay := c.backoff << (attempt - 1)
+	if delay <= 0 || delay > c.maxBackoff {
+		delay = c.maxBackoff
+	}
+	return delay
+}
+
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type cachedResponse struct {
+	etag string
+	body []byte
+}
+
+// etagCache keeps the most recent validated responses keyed by URL.
+// A nil cache is valid and stores nothing.
+type etagCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]cachedResponse
+	order   []string
+}
+
+func newETagCache(size int) *etagCache {
+	return &etagCache{size: size, entries: make(map[string]cachedResponse)}
+}
+
+func (c *etagCache) get(key string) (cachedResponse, bool) {
+	if c == nil {
+		return cachedResponse{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	return entry, ok
+}
+
+func (c *etagCache) put(key string, entry cachedResponse) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok {
+		c.order = append(c.order, key)
+		// Evict the oldest entries once the cache is full
+		for len(c.order) > c.size {
+			delete(c.entries, c.order[0])
+			c.order = c.order[1:]
+		}
+	}
+	c.entries[key] = entry
+}
@@ -0,0 +1,178 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type payload struct {
+	Value string `json:"value"`
+}
+
+// server answers with the handler and counts the requests it receives
+func server(t *testing.T, handler func(w http.ResponseWriter, r *http.Request, n int)) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler(w, r, int(calls.Add(1)))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestRetriesTransientErrors(t *testing.T) {
+	srv, calls := server(t, func(w http.ResponseWriter, r *http.Request, n int) {
+		if n < 3 {
+			http.Error(w, `{"error":"unavailable"}`, http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"value":"ok"}`))
+	})
+	c := New(srv.URL, WithRetries(3, time.Millisecond))
+
+	var out payload
+	if err := c.get(context.Background(), "/x", nil, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Value != "ok" || calls.Load() != 3 {
+		t.Errorf("got %q after %d calls, want ok after 3", out.Value, calls.Load())
+	}
+}
+
+func TestGivesUpAfterRetries(t *testing.T) {
+	srv, calls := server(t, func(w http.ResponseWriter, r *http.Request, n int) {
+		http.Error(w, `{"error":"boom"}`, http.StatusInternalServerError)
+	})
+	c := New(srv.URL, WithRetries(2, time.Millisecond))
+
+	var apiErr *APIError
+	err := c.get(context.Background(), "/x", nil, &payload{})
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError || apiErr.Message != "boom" {
+		t.Fatalf("got %v, want APIError 500 boom", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("got %d calls, want 3", calls.Load())
+	}
+}
+
+func TestDoesNotRetryClientErrors(t *testing.T) {
+	srv, calls := server(t, func(w http.ResponseWriter, r *http.Request, n int) {
+		http.Error(w, `{"error":"not found"}`, http.StatusNotFound)
+	})
+	c := New(srv.URL, WithRetries(3, time.Millisecond))
+
+	if err := c.get(context.Background(), "/x", nil, &payload{}); !IsNotFound(err) {
+		t.Fatalf("got %v, want 404", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("got %d calls, want 1", calls.Load())
+	}
+}
+
+func TestHonorsRetryAfter(t *testing.T) {
+	srv, _ := server(t, func(w http.ResponseWriter, r *http.Request, n int) {
+		if n == 1 {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, `{"error":"Rate limit exceeded"}`, http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"value":"ok"}`))
+	})
+	c := New(srv.URL, WithRetries(1, time.Millisecond))
+
+	start := time.Now()
+	if err := c.get(context.Background(), "/x", nil, &payload{}); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Errorf("retried after %v, want the 1s Retry-After", elapsed)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	c := New("http://example.com", WithRetries(3, 100*time.Millisecond))
+	throttled := &retryAfterError{APIError: &APIError{StatusCode: http.StatusTooManyRequests}, after: 2 * time.Second}
+	tooLong := &retryAfterError{APIError: &APIError{StatusCode: http.StatusTooManyRequests}, after: time.Hour}
+
+	for _, tc := range []struct {
+		attempt int
+		err     error
+		want    time.Duration
+	}{
+		{1, errors.New("reset"), 100 * time.Millisecond},
+		{3, errors.New("reset"), 400 * time.Millisecond},
+		{10, errors.New("reset"), defaultMaxBackoff},
+		{1, throttled, 2 * time.Second},
+		{1, tooLong, defaultMaxBackoff},
+	} {
+		if got := c.retryDelay(tc.attempt, tc.err); got != tc.want {
+			t.Errorf("retryDelay(%d, %v) = %v, want %v", tc.attempt, tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if got := parseRetryAfter("3"); got != 3*time.Second {
+		t.Errorf("seconds: got %v", got)
+	}
+	date := time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat)
+	if got := parseRetryAfter(date); got < 8*time.Second || got > 10*time.Second {
+		t.Errorf("HTTP date: got %v", got)
+	}
+	for _, v := range []string{"", "0", "-1", "soon"} {
+		if got := parseRetryAfter(v); got != 0 {
+			t.Errorf("parseRetryAfter(%q) = %v, want 0", v, got)
+		}
+	}
+}
+
+func TestRevalidatesWithETag(t *testing.T) {
+	srv, calls := server(t, func(w http.ResponseWriter, r *http.Request, n int) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"value":"fresh"}`))
+	})
+	c := New(srv.URL)
+
+	for i := 0; i < 2; i++ {
+		var out payload
+		if err := c.get(context.Background(), "/x", nil, &out); err != nil {
+			t.Fatal(err)
+		}
+		if out.Value != "fresh" {
+			t.Errorf("request %d: got %q, want the cached body", i+1, out.Value)
+		}
+	}
+	if calls.Load() != 2 {
+		t.Errorf("got %d calls, want 2", calls.Load())
+	}
+}
+
+func TestRefetchesNotModifiedWithoutCache(t *testing.T) {
+	srv, calls := server(t, func(w http.ResponseWriter, r *http.Request, n int) {
+		if r.Header.Get("If-None-Match") != "" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v2"`)
+		w.Write([]byte(`{"value":"full"}`))
+	})
+	// The validator comes from the caller, so the client has no body to reuse
+	c := New(srv.URL, WithHeader("If-None-Match", `"v1"`), WithCacheSize(0))
+
+	var out payload
+	if err := c.get(context.Background(), "/x", nil, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Value != "full" || calls.Load() != 2 {
+		t.Errorf("got %q after %d calls, want full after 2", out.Value, calls.Load())
+	}
+}
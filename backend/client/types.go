@@ -0,0 +1,89 @@
+package client
+
+import "github.com/spehlivan/price-list/backend/internal/models"
+
+// Response types are aliases of the server's models so they stay in sync
+// with the API and can be used by code outside this module.
+type (
+	PriceListRow      = models.PriceListRow
+	OptionalEquipment = models.OptionalEquipment
+	StoredData        = models.StoredData
+	IndexData         = models.IndexData
+	BrandIndexData    = models.BrandIndexData
+	LatestData        = models.LatestData
+	LatestBrandData   = models.LatestBrandData
+	TrendPoint        = models.TrendPoint
+	TrendResponse     = models.TrendResponse
+	VariantName       = models.VariantName
+	Segment           = models.Segment
+	ValueMetrics      = models.ValueMetrics
+	RankedVehicle     = models.RankedVehicle
+	RankingsResponse  = models.RankingsResponse
+
+	StatsData            = models.StatsData
+	OverallStats         = models.OverallStats
+	BrandStats           = models.BrandStats
+	FuelStats            = models.FuelStats
+	TransmissionStats    = models.TransmissionStats
+	FuelConsumption      = models.FuelConsumption
+	FuelConsumptionStats = models.FuelConsumptionStats
+
+	EventsData          = models.EventsData
+	PriceEvent          = models.PriceEvent
+	ArchitectureData    = models.ArchitectureData
+	TrimLadder          = models.TrimLadder
+	ModelArchitecture   = models.ModelArchitecture
+	LadderChange        = models.LadderChange
+	TrimStepChange      = models.TrimStepChange
+	GapsData            = models.GapsData
+	GapCell             = models.GapCell
+	GapAnalysis         = models.GapAnalysis
+	GapAnalysisCell     = models.GapAnalysisCell
+	PromosData          = models.PromosData
+	PriceDrop           = models.PriceDrop
+	LifecycleData       = models.LifecycleData
+	ModelYearTransition = models.ModelYearTransition
+	ErrorLogResponse    = models.ErrorLogResponse
+	LoggedError         = models.LoggedError
+	ErrorCount          = models.ErrorCount
+	InsightsData        = models.InsightsData
+	VehicleWithScore    = models.VehicleWithScore
+	ScoreResponse       = models.ScoreResponse
+	ScoreWeights        = models.ScoreWeights
+	ScoreComponents     = models.ScoreComponents
+
+	AnomaliesData      = models.AnomaliesData
+	CampaignsData      = models.CampaignsData
+	Campaign           = models.Campaign
+	BrandCampaignStats = models.BrandCampaignStats
+	SnapshotVerdict    = models.SnapshotVerdict
+	SnapshotComparison = models.SnapshotComparison
+
+	ModelYearChangesData = models.ModelYearChangesData
+	ModelYearChange      = models.ModelYearChange
+	ReactionAnalysis     = models.ReactionAnalysis
+	ReactionCell         = models.ReactionCell
+	MoveReaction         = models.MoveReaction
+	PriceMove            = models.PriceMove
+
+	ForecastResponse = models.ForecastResponse
+	ForecastModel    = models.ForecastModel
+	ForecastPoint    = models.ForecastPoint
+	ForecastBacktest = models.ForecastBacktest
+
+	CollectionHealthData  = models.CollectionHealthData
+	BrandCollectionHealth = models.BrandCollectionHealth
+	LatencyPercentiles    = models.LatencyPercentiles
+
+	CoverageData  = models.CoverageData
+	BrandCoverage = models.BrandCoverage
+	CoverageDay   = models.CoverageDay
+	CoverageGap   = models.CoverageGap
+
+	StalenessData  = models.StalenessData
+	BrandStaleness = models.BrandStaleness
+	ModelStaleness = models.ModelStaleness
+
+	DailyDocument = models.DailyDocument
+	DailyRow      = models.DailyRow
+)
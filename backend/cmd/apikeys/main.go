@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spehlivan/price-list/backend/config"
+	"github.com/spehlivan/price-list/backend/internal/auth"
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"github.com/spehlivan/price-list/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const usageText = `Usage: apikeys <command> [flags]
+
+Commands:
+  create -name NAME [-scopes vehicles:read,intel:read] [-quota N]
+                      issue a new key (the plaintext key is printed once)
+  revoke KEY_ID       revoke a key
+  list                list keys with today's usage
+  usage [-key KEY_ID] [-days N]
+                      print daily request counters
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usageText)
+		os.Exit(2)
+	}
+
+	cfg := config.Load()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(options.Client().ApplyURI(cfg.MongoURI))
+	if err != nil {
+		log.Fatalf("Failed to connect to MongoDB: %v", err)
+	}
+	defer client.Disconnect(context.Background())
+
+	if err := client.Ping(ctx, nil); err != nil {
+		log.Fatalf("Failed to ping MongoDB: %v", err)
+	}
+
+	repo := repository.NewAPIKeyRepository(client.Database(cfg.Database))
+	if err := repo.EnsureIndexes(ctx); err != nil {
+		log.Printf("Warning: Failed to ensure API key indexes: %v", err)
+	}
+
+	args := os.Args[2:]
+	switch os.Args[1] {
+	case "create":
+		err = createKey(ctx, repo, args)
+	case "revoke":
+		err = revokeKey(ctx, repo, args)
+	case "list":
+		err = listKeys(ctx, repo)
+	case "usage":
+		err = printUsage(ctx, repo, args)
+	default:
+		fmt.Fprint(os.Stderr, usageText)
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatalf("%s: %v", os.Args[1], err)
+	}
+}
+
+func createKey(ctx context.Context, repo *repository.APIKeyRepository, args []string) error {
+	fs := flag.NewFlagSet("create", flag.ExitOnError)
+	name := fs.String("name", "", "owner of the key, e.g. the partner dealer")
+	scopes := fs.String("scopes", models.ScopeReadVehicles+","+models.ScopeReadIntel, "comma-separated scopes")
+	quota := fs.Int("quota", 10000, "daily request quota (0 = unlimited)")
+	fs.Parse(args)
+
+	if *name == "" {
+		return errors.New("-name is required")
+	}
+	if *quota < 0 {
+		return errors.New("-quota must not be negative")
+	}
+
+	var scopeList []string
+	for _, s := range strings.Split(*scopes, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		if !slices.Contains(models.AllScopes, s) {
+			return fmt.Errorf("unknown scope %q (valid: %s)", s, strings.Join(models.AllScopes, ", "))
+		}
+		scopeList = append(scopeList, s)
+	}
+	if len(scopeList) == 0 {
+		return errors.New("at least one scope is required")
+	}
+
+	plaintext, keyID, hash, err := auth.GenerateKey()
+	if err != nil {
+		return err
+	}
+	key := &models.APIKey{
+		KeyID:      keyID,
+		Name:       *name,
+		Hash:       hash,
+		Scopes:     scopeList,
+		DailyQuota: *quota,
+		CreatedAt:  time.Now().UTC(),
+	}
+	if err := repo.Create(ctx, key); err != nil {
+		return err
+	}
+
+	fmt.Printf("Created key %s for %q (scopes: %s, daily quota: %d)\n", keyID, *name, strings.Join(scopeList, ","), *quota)
+	fmt.Println("Store it now, it cannot be shown again:")
+	fmt.Println(plaintext)
+	return nil
+}
+
+func revokeKey(ctx context.Context, repo *repository.APIKeyRepository, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: apikeys revoke KEY_ID")
+	}
+	if err := repo.Revoke(ctx, args[0]); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return fmt.Errorf("no active key with id %s", args[0])
+		}
+		return err
+	}
+	fmt.Printf("Revoked key %s\n", args[0])
+	return nil
+}
+
+func listKeys(ctx context.Context, repo *repository.APIKeyRepository) error {
+	keys, err := repo.List(ctx)
+	if err != nil {
+		return err
+	}
+	today := time.Now().UTC().Format("2006-01-02")
+	usage, err := repo.GetUsage(ctx, "", today, today)
+	if err != nil {
+		return err
+	}
+	usageByKey := make(map[string]int64, len(usage))
+	for _, u := range usage {
+		usageByKey[u.KeyID] = u.Count
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY ID\tNAME\tSCOPES\tQUOTA\tTODAY\tCREATED\tSTATUS")
+	for _, k := range keys {
+		status := "active"
+		if k.Revoked() {
+			status = "revoked " + k.RevokedAt.Format("2006-01-02")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%s\t%s\n",
+			k.KeyID, k.Name, strings.Join(k.Scopes, ","), k.DailyQuota,
+			usageByKey[k.KeyID], k.CreatedAt.Format("2006-01-02"), status)
+	}
+	return w.Flush()
+}
+
+func printUsage(ctx context.Context, repo *repository.APIKeyRepository, args []string) error {
+	fs := flag.NewFlagSet("usage", flag.ExitOnError)
+	keyID := fs.String("key", "", "restrict to a single key id")
+	days := fs.Int("days", 7, "number of days to show")
+	fs.Parse(args)
+
+	now := time.Now().UTC()
+	from := now.AddDate(0, 0, -(*days - 1)).Format("2006-01-02")
+	usage, err := repo.GetUsage(ctx, *keyID, from, now.Format("2006-01-02"))
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "DATE\tKEY ID\tREQUESTS")
+	for _, u := range usage {
+		fmt.Fprintf(w, "%s\t%s\t%d\n", u.Date, u.KeyID, u.Count)
+	}
+	return w.Flush()
+}
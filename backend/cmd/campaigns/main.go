@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spehlivan/price-list/backend/config"
+	"github.com/spehlivan/price-list/backend/internal/campaign"
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"github.com/spehlivan/price-list/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const usageText = `Usage: campaigns [flags]
+
+Rebuilds the campaigns collection behind /api/v1/campaigns: the campaign
+episodes of every variant, detected over each brand's full snapshot history
+from campaign prices below the list price and from temporary price dips that
+recover. Run it after each import; episodes are replaced per brand.
+
+Flags:
+`
+
+// brandResult summarizes the detection run of one brand
+type brandResult struct {
+	brandID string
+	listed  int
+	dips    int
+	active  int
+	removed int64
+}
+
+func main() {
+	cfg := config.Load()
+
+	brandList := flag.String("brand", "", "only rebuild these brand ids (comma-separated)")
+	dryRun := flag.Bool("dry-run", false, "detect episodes without writing anything")
+	opTimeout := flag.Duration("timeout", 60*time.Second, "timeout per brand")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usageText)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(options.Client().ApplyURI(cfg.MongoURI))
+	if err != nil {
+		log.Fatalf("Failed to connect to MongoDB: %v", err)
+	}
+	defer client.Disconnect(context.Background())
+
+	if err := client.Ping(ctx, nil); err != nil {
+		log.Fatalf("Failed to ping MongoDB: %v", err)
+	}
+
+	db := client.Database(cfg.Database)
+	vehicles := repository.NewVehicleRepository(db)
+	repo := repository.NewCampaignRepository(db)
+	if !*dryRun {
+		if err := repo.EnsureIndexes(ctx); err != nil {
+			log.Printf("Warning: Failed to ensure campaign indexes: %v", err)
+		}
+	}
+
+	brandIDs, err := vehicles.BrandIDs(ctx, *brandList)
+	if err != nil {
+		log.Fatalf("Failed to list brands: %v", err)
+	}
+
+	detectedAt := time.Now().UTC()
+	var results []brandResult
+	failed := false
+	for _, brandID := range brandIDs {
+		res, err := rebuildBrand(vehicles, repo, brandID, detectedAt, *dryRun, *opTimeout)
+		if err != nil {
+			log.Printf("%s: %v", brandID, err)
+			failed = true
+			continue
+		}
+		results = append(results, res)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "BRAND\tLISTED\tDIPS\tACTIVE\tREMOVED")
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\n", r.brandID, r.listed, r.dips, r.active, r.removed)
+	}
+	w.Flush()
+
+	if failed {
+		log.Fatalf("Campaign detection completed with failures")
+	}
+	if *dryRun {
+		log.Println("Dry run: nothing was written")
+	}
+}
+
+// rebuildBrand detects one brand's episodes over its whole history and
+// replaces the stored ones
+func rebuildBrand(vehicles *repository.VehicleRepository, repo *repository.CampaignRepository, brandID string, detectedAt time.Time, dryRun bool, timeout time.Duration) (brandResult, error) {
+	res := brandResult{brandID: brandID}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	snapshots, err := vehicles.GetHistory(ctx, brandID, "", "")
+	if err != nil {
+		return res, err
+	}
+
+	campaigns := campaign.Detect(snapshots, detectedAt)
+	for _, c := range campaigns {
+		if c.Kind == models.CampaignKindDip {
+			res.dips++
+		} else {
+			res.listed++
+		}
+		if c.Active {
+			res.active++
+		}
+	}
+	if dryRun {
+		return res, nil
+	}
+
+	res.removed, err = repo.ReplaceBrand(ctx, brandID, campaigns)
+	return res, err
+}
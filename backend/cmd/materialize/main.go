@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spehlivan/price-list/backend/config"
+	"github.com/spehlivan/price-list/backend/internal/daily"
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"github.com/spehlivan/price-list/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const usageText = `Usage: materialize [flags]
+
+Rebuilds vehicles_daily, the forward-filled daily series behind ?fill=forward:
+one document per brand and calendar day up to the newest snapshot, where a
+day without a snapshot carries the previous snapshot's variants forward,
+marked filled. Run it after migrate.
+
+Flags:
+`
+
+const batchSize = 200
+
+// brandResult summarizes the rebuild of one brand
+type brandResult struct {
+	brandID    string
+	days       int
+	filledDays int
+	filledRows int
+	removed    int64
+}
+
+func main() {
+	cfg := config.Load()
+
+	since := flag.String("since", "", "only rebuild days on or after YYYY-MM-DD")
+	brandList := flag.String("brand", "", "only rebuild these brand ids (comma-separated)")
+	maxCarry := flag.Int("max-carry", 0, "days a snapshot is carried forward over days without one (0 = every calendar day)")
+	dryRun := flag.Bool("dry-run", false, "compute the series without writing anything")
+	opTimeout := flag.Duration("timeout", 60*time.Second, "timeout per brand")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usageText)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *since != "" {
+		if _, err := time.Parse("2006-01-02", *since); err != nil {
+			log.Fatalf("Invalid --since %q: expected YYYY-MM-DD", *since)
+		}
+	}
+	if *maxCarry < 0 {
+		log.Fatalf("Invalid --max-carry %d: must not be negative", *maxCarry)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(options.Client().ApplyURI(cfg.MongoURI))
+	if err != nil {
+		log.Fatalf("Failed to connect to MongoDB: %v", err)
+	}
+	defer client.Disconnect(context.Background())
+
+	if err := client.Ping(ctx, nil); err != nil {
+		log.Fatalf("Failed to ping MongoDB: %v", err)
+	}
+
+	db := client.Database(cfg.Database)
+	vehicles := db.Collection("vehicles")
+	dailyCol := db.Collection("vehicles_daily")
+
+	// Every brand is filled up to the newest snapshot of any brand, so a
+	// brand whose collection stopped keeps its carried values
+	var newest struct {
+		Date string `bson:"date"`
+	}
+	findOpts := options.FindOne().SetSort(bson.D{{Key: "date", Value: -1}}).SetProjection(bson.D{{Key: "date", Value: 1}})
+	if err := vehicles.FindOne(ctx, bson.D{}, findOpts).Decode(&newest); err != nil {
+		log.Fatalf("Failed to find the newest snapshot: %v", err)
+	}
+
+	brandIDs, err := repository.NewVehicleRepository(db).BrandIDs(ctx, *brandList)
+	if err != nil {
+		log.Fatalf("Failed to list brands: %v", err)
+	}
+
+	builtAt := time.Now().UTC()
+	var results []brandResult
+	failed := false
+	for _, brandID := range brandIDs {
+		res, err := rebuildBrand(vehicles, dailyCol, brandID, *since, newest.Date, *maxCarry, builtAt, *dryRun, *opTimeout)
+		if err != nil {
+			log.Printf("%s: %v", brandID, err)
+			failed = true
+			continue
+		}
+		results = append(results, res)
+	}
+
+	if !*dryRun {
+		ensureIndexes(dailyCol)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "BRAND\tDAYS\tFILLED DAYS\tFILLED ROWS\tREMOVED")
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\n", r.brandID, r.days, r.filledDays, r.filledRows, r.removed)
+	}
+	w.Flush()
+
+	if failed {
+		log.Fatalf("Materialization completed with failures")
+	}
+	if *dryRun {
+		log.Println("Dry run: nothing was written")
+		return
+	}
+	log.Printf("vehicles_daily: built through %s", newest.Date)
+}
+
+// rebuildBrand recomputes one brand's daily documents from since (or its first
+// snapshot) through to, and removes daily documents that no longer apply
+func rebuildBrand(vehicles, dailyCol *mongo.Collection, brandID, since, to string, maxCarry int, builtAt time.Time, dryRun bool, timeout time.Duration) (brandResult, error) {
+	res := brandResult{brandID: brandID}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// Values carried into the first rebuilt day come from the newest snapshot
+	// before it
+	filter := bson.D{{Key: "brandId", Value: brandID}}
+	if since != "" {
+		var seed struct {
+			Date string `bson:"date"`
+		}
+		before := bson.D{{Key: "brandId", Value: brandID}, {Key: "date", Value: bson.D{{Key: "$lt", Value: since}}}}
+		opts := options.FindOne().SetSort(bson.D{{Key: "date", Value: -1}}).SetProjection(bson.D{{Key: "date", Value: 1}})
+		err := vehicles.FindOne(ctx, before, opts).Decode(&seed)
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			return res, err
+		}
+		seedFrom := since
+		if seed.Date != "" {
+			seedFrom = seed.Date
+		}
+		filter = append(filter, bson.E{Key: "date", Value: bson.D{{Key: "$gte", Value: seedFrom}}})
+	}
+	cursor, err := vehicles.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "date", Value: 1}}))
+	if err != nil {
+		return res, err
+	}
+	var snapshots []models.VehicleDocument
+	if err := cursor.All(ctx, &snapshots); err != nil {
+		return res, err
+	}
+	if len(snapshots) == 0 {
+		return res, nil
+	}
+
+	from := since
+	if from == "" || from < snapshots[0].Date {
+		from = snapshots[0].Date
+	}
+	docs := daily.ForwardFill(snapshots, from, to, maxCarry, builtAt)
+
+	writes := make([]mongo.WriteModel, 0, len(docs))
+	dates := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		res.days++
+		if doc.Filled {
+			res.filledDays++
+		}
+		res.filledRows += doc.FilledRows
+		dates = append(dates, doc.Date)
+		filter := bson.D{{Key: "brandId", Value: doc.BrandID}, {Key: "date", Value: doc.Date}}
+		writes = append(writes, mongo.NewReplaceOneModel().SetFilter(filter).SetReplacement(doc).SetUpsert(true))
+	}
+	if dryRun {
+		return res, nil
+	}
+
+	for start := 0; start < len(writes); start += batchSize {
+		end := min(start+batchSize, len(writes))
+		if _, err := dailyCol.BulkWrite(ctx, writes[start:end], options.BulkWrite().SetOrdered(false)); err != nil {
+			return res, err
+		}
+	}
+
+	// Days that are no longer produced, e.g. after lowering --max-carry
+	stale := bson.D{
+		{Key: "brandId", Value: brandID},
+		{Key: "date", Value: bson.D{{Key: "$gte", Value: from}, {Key: "$nin", Value: dates}}},
+	}
+	deleted, err := dailyCol.DeleteMany(ctx, stale)
+	if err != nil {
+		return res, err
+	}
+	res.removed = deleted.DeletedCount
+	return res, nil
+}
+
+func ensureIndexes(dailyCol *mongo.Collection) {
+	_, err := dailyCol.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "brandId", Value: 1}, {Key: "date", Value: -1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "builtAt", Value: -1}}},
+	})
+	if err != nil {
+		log.Printf("Warning: vehicles_daily indexes: %v", err)
+	}
+}
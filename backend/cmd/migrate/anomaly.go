@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"github.com/spehlivan/price-list/backend/internal/validation"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Anomaly guard modes (--anomaly)
+const (
+	anomalyReject = "reject" // quarantine implausible snapshots
+	anomalyFlag   = "flag"   // import them but record a verdict
+	anomalyOff    = "off"
+)
+
+// anomalyGuard compares each new brand snapshot with the previous accepted one
+type anomalyGuard struct {
+	mode       string
+	thresholds validation.AnomalyThresholds
+	vehicles   *mongo.Collection
+	verdicts   *mongo.Collection
+	timeout    time.Duration
+}
+
+func (g *anomalyGuard) enabled() bool {
+	return g != nil && g.mode != anomalyOff
+}
+
+// baseline tracks the previous accepted snapshot of one brand while its files
+// are processed in date order. Unchanged files are only read from disk when a
+// later file needs them for comparison.
+type baseline struct {
+	doc      *models.VehicleDocument // last snapshot known to be stored
+	file     *vehicleFile            // accepted file whose document is not loaded yet
+	pending  *models.VehicleDocument // newest accepted snapshot of the unwritten batch
+	queried  bool                    // MongoDB was asked for a snapshot before the first file
+	rejected *models.VehicleDocument // last rejected snapshot since the baseline
+}
+
+// accept makes a stored snapshot the baseline of the following dates
+func (b *baseline) accept(doc *models.VehicleDocument) {
+	b.doc, b.file, b.pending, b.rejected = doc, nil, nil, nil
+}
+
+// stage makes an accepted snapshot the baseline of the rest of its batch. It
+// only becomes the stored baseline through commit once the batch is written.
+func (b *baseline) stage(doc *models.VehicleDocument) {
+	b.pending, b.rejected = doc, nil
+}
+
+// commit makes the newest snapshot a flush wrote the baseline, unless a later
+// unchanged file already took its place. A failed write (nil) falls back to
+// the last stored snapshot.
+func (b *baseline) commit(doc *models.VehicleDocument) {
+	b.pending = nil
+	if doc == nil || (b.doc != nil && b.doc.Date >= doc.Date) || (b.file != nil && b.file.Date >= doc.Date) {
+		return
+	}
+	b.doc, b.file = doc, nil
+}
+
+// skip makes an unchanged, previously accepted file the baseline
+func (b *baseline) skip(file vehicleFile) {
+	b.doc, b.file, b.pending, b.rejected = nil, &file, nil, nil
+}
+
+// previous returns the snapshot to compare file against, or nil for a brand's
+// first snapshot
+func (g *anomalyGuard) previous(b *baseline, file vehicleFile) (*models.VehicleDocument, error) {
+	switch {
+	case b.pending != nil:
+		return b.pending, nil
+	case b.doc != nil:
+		return b.doc, nil
+	case b.file != nil:
+		data, err := os.ReadFile(b.file.Path)
+		if err != nil {
+			return nil, err
+		}
+		var doc models.VehicleDocument
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+		doc.Date = b.file.Date
+		b.doc, b.file = &doc, nil
+		return b.doc, nil
+	case b.queried:
+		return nil, nil
+	}
+
+	b.queried = true
+	ctx, cancel := context.WithTimeout(context.Background(), g.timeout)
+	defer cancel()
+
+	filter := bson.D{
+		{Key: "brandId", Value: file.BrandID},
+		{Key: "date", Value: bson.D{{Key: "$lt", Value: file.Date}}},
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "date", Value: -1}})
+	var doc models.VehicleDocument
+	if err := g.vehicles.FindOne(ctx, filter, opts).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	b.doc = &doc
+	return b.doc, nil
+}
+
+// check compares a valid snapshot with the brand's baseline. It returns nil
+// when the snapshot is plausible or there is nothing to compare against.
+//
+// A change that persists is a real change, not a glitch: a snapshot that
+// deviates from the baseline but matches the previously rejected snapshot is
+// only flagged, and becomes the new baseline once imported.
+func (g *anomalyGuard) check(b *baseline, p *preparedVehicle) (*models.SnapshotVerdict, error) {
+	if !g.enabled() || p.doc == nil {
+		return nil, nil
+	}
+	prev, err := g.previous(b, p.file)
+	if err != nil || prev == nil {
+		return nil, err
+	}
+
+	cmp := validation.CompareSnapshots(prev, p.doc, g.thresholds)
+	if len(cmp.Violations) == 0 {
+		return nil, nil
+	}
+	verdict := models.VerdictFlagged
+	switch {
+	case b.rejected != nil && len(validation.CompareSnapshots(b.rejected, p.doc, g.thresholds).Violations) == 0:
+		cmp.Violations = append(cmp.Violations, fmt.Sprintf("change persisted since %s, accepted as the new baseline", b.rejected.Date))
+	case g.mode == anomalyReject:
+		verdict = models.VerdictRejected
+		b.rejected = p.doc
+	}
+	return &models.SnapshotVerdict{
+		BrandID:    p.file.BrandID,
+		Date:       p.file.Date,
+		Path:       p.file.RelPath,
+		Verdict:    verdict,
+		Comparison: cmp,
+		CheckedAt:  time.Now().UTC(),
+	}, nil
+}
+
+// record stores a verdict, replacing an earlier one for the same file
+func (g *anomalyGuard) record(v *models.SnapshotVerdict) error {
+	ctx, cancel := context.WithTimeout(context.Background(), g.timeout)
+	defer cancel()
+	_, err := g.verdicts.ReplaceOne(ctx, bson.D{{Key: "path", Value: v.Path}}, v, options.Replace().SetUpsert(true))
+	return err
+}
+
+// clear removes stale verdicts of files that are now imported without anomalies
+func (g *anomalyGuard) clear(ctx context.Context, paths []string) error {
+	if !g.enabled() || len(paths) == 0 {
+		return nil
+	}
+	_, err := g.verdicts.DeleteMany(ctx, bson.D{{Key: "path", Value: bson.D{{Key: "$in", Value: paths}}}})
+	return err
+}
+
+// anomalyReasons prefixes the violations of a rejected snapshot for the quarantine
+func anomalyReasons(v *models.SnapshotVerdict) []string {
+	reasons := make([]string, len(v.Comparison.Violations))
+	for i, violation := range v.Comparison.Violations {
+		reasons[i] = fmt.Sprintf("anomaly: %s", violation)
+	}
+	return reasons
+}
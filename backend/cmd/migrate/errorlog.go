@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const errorLogPath = "errors.json"
+
+// importErrorLog upserts every entry of errors.json into error_log, keyed by
+// the logger's error id. The pipeline clears the file on each run, so the
+// history lives in MongoDB and expires after retentionDays (0 keeps it).
+func importErrorLog(db *mongo.Database, m *manifest, dataDir string, retentionDays int, timeout time.Duration, report *importReport) {
+	data, err := os.ReadFile(filepath.Join(dataDir, errorLogPath))
+	if errors.Is(err, os.ErrNotExist) {
+		log.Printf("error_log: %s not found, skipping", errorLogPath)
+		return
+	}
+	if err != nil {
+		report.fail(errorLogPath, "", stageRead, err)
+		return
+	}
+
+	hash := hashContent(data)
+	if m.status(errorLogPath, hash) == statusUnchanged {
+		log.Printf("error_log: unchanged, skipping")
+		return
+	}
+
+	var file struct {
+		GeneratedAt string               `json:"generatedAt"`
+		Errors      []models.LoggedError `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		report.fail(errorLogPath, "", stageParse, err)
+		return
+	}
+
+	fallback := time.Now().UTC()
+	if t, err := time.Parse(time.RFC3339, file.GeneratedAt); err == nil {
+		fallback = t.UTC()
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(file.Errors))
+	for _, entry := range file.Errors {
+		entry.LoggedAt = fallback
+		if t, err := time.Parse(time.RFC3339, entry.Timestamp); err == nil {
+			entry.LoggedAt = t.UTC()
+		}
+		if retentionDays > 0 {
+			expiresAt := entry.LoggedAt.AddDate(0, 0, retentionDays)
+			entry.ExpiresAt = &expiresAt
+		}
+		if entry.ID == "" {
+			// Older logs have no id; derive a stable one so re-imports do not duplicate
+			entry.ID = "err_" + hashContent([]byte(entry.Timestamp + "|" + entry.Source + "|" + entry.Code + "|" + entry.Message))[:16]
+		}
+		writes = append(writes, mongo.NewReplaceOneModel().
+			SetFilter(bson.D{{Key: "id", Value: entry.ID}}).
+			SetReplacement(entry).
+			SetUpsert(true))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if len(writes) > 0 {
+		if _, err := db.Collection("error_log").BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+			report.fail(errorLogPath, "", stageWrite, err)
+			return
+		}
+	}
+	log.Printf("error_log: imported %d errors", len(writes))
+
+	entry := manifestEntry{Path: errorLogPath, Collection: "error_log", Hash: hash, Size: int64(len(data))}
+	if err := m.record(ctx, entry); err != nil {
+		report.fail(errorLogPath, "", stageManifest, err)
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const healthReportPath = "health-report.json"
+
+// importHealthReport upserts the collector's health report as one
+// collection_health document per brand and date. The collector overwrites the
+// file on every run (and per runner), so history accumulates in MongoDB only.
+func importHealthReport(db *mongo.Database, m *manifest, dataDir string, timeout time.Duration, report *importReport) {
+	data, err := os.ReadFile(filepath.Join(dataDir, healthReportPath))
+	if errors.Is(err, os.ErrNotExist) {
+		log.Printf("collection_health: %s not found, skipping", healthReportPath)
+		return
+	}
+	if err != nil {
+		report.fail(healthReportPath, "", stageRead, err)
+		return
+	}
+
+	hash := hashContent(data)
+	if m.status(healthReportPath, hash) == statusUnchanged {
+		log.Printf("collection_health: unchanged, skipping")
+		return
+	}
+
+	var health models.CollectionHealthReport
+	if err := json.Unmarshal(data, &health); err != nil {
+		report.fail(healthReportPath, "", stageParse, err)
+		return
+	}
+	// scripts/healthCheck.ts writes a data health summary to the same path
+	if health.Date == "" || len(health.Details) == 0 {
+		log.Printf("collection_health: %s is not a collector report, skipping", healthReportPath)
+		return
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(health.Details))
+	for _, entry := range health.Details {
+		entry.Date = health.Date
+		entry.GeneratedAt = health.GeneratedAt
+		filter := bson.D{{Key: "date", Value: entry.Date}, {Key: "brand", Value: entry.Brand}}
+		writes = append(writes, mongo.NewReplaceOneModel().SetFilter(filter).SetReplacement(entry).SetUpsert(true))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if _, err := db.Collection("collection_health").BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+		report.fail(healthReportPath, "", stageWrite, err)
+		return
+	}
+	log.Printf("collection_health: imported %d brands for %s", len(writes), health.Date)
+
+	entry := manifestEntry{Path: healthReportPath, Collection: "collection_health", Hash: hash, Size: int64(len(data))}
+	if err := m.record(ctx, entry); err != nil {
+		report.fail(healthReportPath, "", stageManifest, err)
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -11,31 +12,76 @@ import (
 	"time"
 
 	"github.com/spehlivan/price-list/backend/config"
+	"github.com/spehlivan/price-list/backend/internal/validation"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
+const usageText = `Usage: migrate [flags] [data-dir]
+
+Imports the data directory (default ../data) into MongoDB. Files whose content
+hash matches the import manifest are skipped. Each new brand snapshot is
+compared with the brand's previous accepted snapshot; implausible day-over-day
+changes are rejected into the quarantine or flagged, depending on --anomaly.
+
+Flags:
+`
+
+// filters restricts which vehicle snapshots are considered
+type filters struct {
+	since  string          // YYYY-MM-DD, inclusive
+	brands map[string]bool // empty means all brands
+}
+
+func (f filters) includesBrand(brandID string) bool {
+	return len(f.brands) == 0 || f.brands[brandID]
+}
+
 func main() {
 	cfg := config.Load()
 
 	// Data directory path (relative to backend/)
-	dataDir := "../data"
-	for i := 1; i < len(os.Args); i++ {
-		arg := os.Args[i]
-		if arg == "-data" || arg == "--data" {
-			if i+1 < len(os.Args) {
-				dataDir = os.Args[i+1]
-			}
-			break
-		} else if !strings.HasPrefix(arg, "-") {
-			dataDir = arg
-			break
+	dataDir := flag.String("data", "../data", "data directory")
+	since := flag.String("since", "", "only consider snapshots dated on or after YYYY-MM-DD")
+	brandList := flag.String("brand", "", "only consider these brand ids (comma-separated)")
+	dryRun := flag.Bool("dry-run", false, "print the import plan without writing anything")
+	workers := flag.Int("workers", 4, "brands imported concurrently")
+	batchSize := flag.Int("batch", 200, "documents per bulk write")
+	opTimeout := flag.Duration("timeout", 30*time.Second, "timeout per database operation")
+	reportPath := flag.String("report", "", "write the import report as JSON to this file")
+	anomalyMode := flag.String("anomaly", anomalyReject, "anomaly guard mode: reject, flag or off")
+	maxRowDrop := flag.Float64("max-row-drop", validation.DefaultAnomalyThresholds.MaxRowCountDrop, "largest plausible day-over-day row count drop (fraction, 0 disables)")
+	maxPriceChange := flag.Float64("max-price-change", validation.DefaultAnomalyThresholds.MaxMedianPriceChange, "largest plausible day-over-day median price change (fraction, 0 disables)")
+	maxVanished := flag.Float64("max-vanished", validation.DefaultAnomalyThresholds.MaxVanishedShare, "largest plausible share of variants vanishing overnight (fraction, 0 disables)")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usageText)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() > 0 {
+		*dataDir = flag.Arg(0)
+	}
+
+	f := filters{since: *since, brands: make(map[string]bool)}
+	if f.since != "" {
+		if _, err := time.Parse("2006-01-02", f.since); err != nil {
+			log.Fatalf("Invalid --since %q: expected YYYY-MM-DD", f.since)
 		}
 	}
+	for _, b := range strings.Split(*brandList, ",") {
+		if b = strings.TrimSpace(b); b != "" {
+			f.brands[b] = true
+		}
+	}
+	switch *anomalyMode {
+	case anomalyReject, anomalyFlag, anomalyOff:
+	default:
+		log.Fatalf("Invalid --anomaly %q: expected reject, flag or off", *anomalyMode)
+	}
 
 	// Resolve absolute path
-	absDataDir, err := filepath.Abs(dataDir)
+	absDataDir, err := filepath.Abs(*dataDir)
 	if err != nil {
 		log.Fatalf("Failed to resolve data directory: %v", err)
 	}
@@ -58,139 +104,116 @@ func main() {
 
 	db := client.Database(cfg.Database)
 
-	// 1. Import vehicle data (data/YYYY/MM/brandId/DD.json)
-	importVehicles(db, absDataDir)
-
-	// 2. Import stats (data/stats/precomputed.json)
-	importSingleFile(db, "stats", filepath.Join(absDataDir, "stats", "precomputed.json"))
-
-	// 3. Import intel data
-	intelFiles := map[string]string{
-		"intel_events":       filepath.Join(absDataDir, "intel", "events.json"),
-		"intel_architecture": filepath.Join(absDataDir, "intel", "architecture.json"),
-		"intel_gaps":         filepath.Join(absDataDir, "intel", "gaps.json"),
-		"intel_promos":       filepath.Join(absDataDir, "intel", "promos.json"),
-		"intel_lifecycle":    filepath.Join(absDataDir, "intel", "lifecycle.json"),
-	}
-	for collection, filePath := range intelFiles {
-		importSingleFile(db, collection, filePath)
+	m, err := loadManifest(ctx, db)
+	if err != nil {
+		log.Fatalf("Failed to load import manifest: %v", err)
 	}
 
-	// 4. Import errors
-	importSingleFile(db, "errors", filepath.Join(absDataDir, "errors.json"))
-
-	// 5. Import insights
-	importSingleFile(db, "insights", filepath.Join(absDataDir, "insights", "latest.json"))
+	// 1. Plan vehicle imports (data/YYYY/MM/brandId/DD.json)
+	files, err := planVehicles(absDataDir, m, f)
+	if err != nil {
+		log.Fatalf("Failed to scan vehicle data: %v", err)
+	}
 
-	// 6. Create indexes
-	createIndexes(db)
+	guard := &anomalyGuard{
+		mode: *anomalyMode,
+		thresholds: validation.AnomalyThresholds{
+			MaxRowCountDrop:      *maxRowDrop,
+			MaxMedianPriceChange: *maxPriceChange,
+			MaxVanishedShare:     *maxVanished,
+		},
+		vehicles: db.Collection("vehicles"),
+		verdicts: db.Collection("import_verdicts"),
+		timeout:  *opTimeout,
+	}
 
-	log.Println("Migration completed!")
-}
+	// Single-document files, keyed by collection
+	singleFiles := []struct {
+		collection string
+		relPath    string
+	}{
+		// 2. Stats
+		{"stats", "stats/precomputed.json"},
+		// 3. Intel data
+		{"intel_events", "intel/events.json"},
+		{"intel_architecture", "intel/architecture.json"},
+		{"intel_gaps", "intel/gaps.json"},
+		{"intel_promos", "intel/promos.json"},
+		{"intel_lifecycle", "intel/lifecycle.json"},
+		// 4. Insights
+		{"insights", "insights/latest.json"},
+	}
 
-func importVehicles(db *mongo.Database, dataDir string) {
-	collection := db.Collection("vehicles")
-	imported := 0
-	skipped := 0
+	if *dryRun {
+		plan := dryRunVehicles(files, m, guard)
+		for _, sf := range singleFiles {
+			fmt.Printf("  %-10s %s -> %s\n", singleFileStatus(m, absDataDir, sf.relPath), sf.relPath, sf.collection)
+		}
+		fmt.Printf("  %-10s %s -> %s\n", singleFileStatus(m, absDataDir, errorLogPath), errorLogPath, "error_log")
+		fmt.Printf("  %-10s %s -> %s\n", singleFileStatus(m, absDataDir, healthReportPath), healthReportPath, "collection_health")
+		fmt.Println()
+		plan.print()
+		log.Println("Dry run: nothing was written")
+		return
+	}
 
-	// Walk through data/YYYY/MM/brandId/DD.json
-	yearsDir := dataDir
-	years, err := os.ReadDir(yearsDir)
+	// Variant identities follow the manual alias table (see cmd/variants),
+	// canonical attributes the vocabulary table (see /admin/vocabulary)
+	variants, err := loadVariants(ctx, db)
 	if err != nil {
-		log.Printf("Warning: Could not read data directory: %v", err)
-		return
+		log.Fatalf("Failed to load variant aliases: %v", err)
+	}
+	vocabulary, err := loadVocabulary(ctx, db)
+	if err != nil {
+		log.Fatalf("Failed to load vocabulary: %v", err)
 	}
 
-	for _, yearEntry := range years {
-		if !yearEntry.IsDir() {
-			continue
-		}
-		year := yearEntry.Name()
-		// Only process year directories (e.g., "2026")
-		if len(year) != 4 {
-			continue
-		}
+	report := newImportReport(false)
+	start := time.Now()
+	importVehicles(db, m, files, guard, variants, vocabulary, importOptions{
+		workers:   *workers,
+		batchSize: max(*batchSize, 1),
+		timeout:   *opTimeout,
+	}, report)
+	log.Printf("Vehicles: processed %d files in %s", len(files), time.Since(start).Round(time.Millisecond))
+
+	for _, sf := range singleFiles {
+		importSingleFile(db, m, sf.collection, absDataDir, sf.relPath, *opTimeout, report)
+	}
 
-		yearPath := filepath.Join(yearsDir, year)
-		months, err := os.ReadDir(yearPath)
-		if err != nil {
-			continue
-		}
+	// 5. Collector errors, one document per error
+	importErrorLog(db, m, absDataDir, cfg.ErrorRetentionDays, *opTimeout, report)
+
+	// 6. Collector health, one document per brand and date
+	importHealthReport(db, m, absDataDir, *opTimeout, report)
 
-		for _, monthEntry := range months {
-			if !monthEntry.IsDir() {
-				continue
-			}
-			month := monthEntry.Name()
-			monthPath := filepath.Join(yearPath, month)
-
-			brands, err := os.ReadDir(monthPath)
-			if err != nil {
-				continue
-			}
-
-			for _, brandEntry := range brands {
-				if !brandEntry.IsDir() {
-					continue
-				}
-				brandID := brandEntry.Name()
-				brandPath := filepath.Join(monthPath, brandID)
-
-				days, err := os.ReadDir(brandPath)
-				if err != nil {
-					continue
-				}
-
-				for _, dayEntry := range days {
-					if dayEntry.IsDir() || !strings.HasSuffix(dayEntry.Name(), ".json") {
-						continue
-					}
-
-					day := strings.TrimSuffix(dayEntry.Name(), ".json")
-					date := fmt.Sprintf("%s-%s-%s", year, month, day)
-					filePath := filepath.Join(brandPath, dayEntry.Name())
-
-					// Read and parse JSON
-					data, err := os.ReadFile(filePath)
-					if err != nil {
-						log.Printf("  Error reading %s: %v", filePath, err)
-						continue
-					}
-
-					var doc bson.M
-					if err := json.Unmarshal(data, &doc); err != nil {
-						log.Printf("  Error parsing %s: %v", filePath, err)
-						continue
-					}
-
-					// Add date field
-					doc["date"] = date
-					if _, ok := doc["brandId"]; !ok {
-						doc["brandId"] = brandID
-					}
-
-					// Upsert: replace if already exists for this brand+date
-					filter := bson.D{
-						{Key: "brandId", Value: brandID},
-						{Key: "date", Value: date},
-					}
-					opts := options.Replace().SetUpsert(true)
-					_, err = collection.ReplaceOne(context.Background(), filter, doc, opts)
-					if err != nil {
-						log.Printf("  Error inserting %s/%s: %v", brandID, date, err)
-						skipped++
-						continue
-					}
-					imported++
-				}
-			}
+	// 7. Create indexes
+	createIndexes(db)
+
+	report.FinishedAt = time.Now().UTC()
+	report.print()
+	if *reportPath != "" {
+		if err := report.writeJSON(*reportPath); err != nil {
+			log.Printf("Failed to write report: %v", err)
 		}
 	}
+	if report.hasFailures() {
+		log.Fatalf("Migration completed with failures")
+	}
+	log.Println("Migration completed!")
+}
 
-	log.Printf("Vehicles: imported %d documents, skipped %d", imported, skipped)
+// singleFileStatus classifies a single-document file for the dry-run plan
+func singleFileStatus(m *manifest, dataDir, relPath string) string {
+	data, err := os.ReadFile(filepath.Join(dataDir, relPath))
+	if err != nil {
+		return "missing"
+	}
+	return m.status(relPath, hashContent(data)).String()
 }
 
-func importSingleFile(db *mongo.Database, collectionName, filePath string) {
+func importSingleFile(db *mongo.Database, m *manifest, collectionName, dataDir, relPath string, timeout time.Duration, report *importReport) {
+	filePath := filepath.Join(dataDir, relPath)
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
 		log.Printf("%s: file not found at %s, skipping", collectionName, filePath)
 		return
@@ -198,17 +221,25 @@ func importSingleFile(db *mongo.Database, collectionName, filePath string) {
 
 	data, err := os.ReadFile(filePath)
 	if err != nil {
-		log.Printf("%s: error reading file: %v", collectionName, err)
+		report.fail(relPath, "", stageRead, err)
+		return
+	}
+
+	hash := hashContent(data)
+	if m.status(relPath, hash) == statusUnchanged {
+		log.Printf("%s: unchanged, skipping", collectionName)
 		return
 	}
 
 	var doc bson.M
 	if err := json.Unmarshal(data, &doc); err != nil {
-		log.Printf("%s: error parsing JSON: %v", collectionName, err)
+		report.fail(relPath, "", stageParse, err)
 		return
 	}
 
 	collection := db.Collection(collectionName)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
 
 	// Use date or generatedAt as unique key
 	date, _ := doc["date"].(string)
@@ -219,24 +250,24 @@ func importSingleFile(db *mongo.Database, collectionName, filePath string) {
 		filter = bson.D{{Key: "date", Value: date}}
 	} else if generatedAt != "" {
 		filter = bson.D{{Key: "generatedAt", Value: generatedAt}}
-	} else {
-		// No unique key, just insert
-		_, err = collection.InsertOne(context.Background(), doc)
-		if err != nil {
-			log.Printf("%s: error inserting: %v", collectionName, err)
-		} else {
-			log.Printf("%s: inserted 1 document", collectionName)
-		}
-		return
 	}
 
-	opts := options.Replace().SetUpsert(true)
-	_, err = collection.ReplaceOne(context.Background(), filter, doc, opts)
+	if filter == nil {
+		// No unique key, just insert
+		_, err = collection.InsertOne(ctx, doc)
+	} else {
+		_, err = collection.ReplaceOne(ctx, filter, doc, options.Replace().SetUpsert(true))
+	}
 	if err != nil {
-		log.Printf("%s: error upserting: %v", collectionName, err)
+		report.fail(relPath, "", stageWrite, err)
 		return
 	}
-	log.Printf("%s: upserted 1 document", collectionName)
+	log.Printf("%s: imported 1 document", collectionName)
+
+	entry := manifestEntry{Path: relPath, Collection: collectionName, Hash: hash, Size: int64(len(data))}
+	if err := m.record(ctx, entry); err != nil {
+		report.fail(relPath, "", stageManifest, err)
+	}
 }
 
 func createIndexes(db *mongo.Database) {
@@ -267,5 +298,52 @@ func createIndexes(db *mongo.Database) {
 		}
 	}
 
+	// quarantine: one entry per rejected data file
+	_, err = db.Collection("quarantine").Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "path", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		log.Printf("Warning: quarantine index: %v", err)
+	}
+
+	// import_verdicts: one anomaly verdict per data file
+	_, err = db.Collection("import_verdicts").Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "path", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		log.Printf("Warning: import_verdicts index: %v", err)
+	}
+
+	// error_log: one entry per collector error, expired by the TTL index
+	_, err = db.Collection("error_log").Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "loggedAt", Value: -1}}},
+		{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	if err != nil {
+		log.Printf("Warning: error_log indexes: %v", err)
+	}
+
+	// collection_health: one entry per brand and date
+	_, err = db.Collection("collection_health").Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "date", Value: -1}, {Key: "brand", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		log.Printf("Warning: collection_health index: %v", err)
+	}
+
+	// import_manifest: one entry per data file; importedAt is probed by the
+	// API's response cache
+	_, err = db.Collection("import_manifest").Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "path", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "importedAt", Value: -1}}},
+	})
+	if err != nil {
+		log.Printf("Warning: import_manifest index: %v", err)
+	}
+
 	log.Println("Indexes created")
 }
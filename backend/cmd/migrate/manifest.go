@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// fileStatus classifies a data file against the import manifest
+type fileStatus int
+
+const (
+	statusAdded     fileStatus = iota // never imported
+	statusChanged                     // imported before with different content
+	statusUnchanged                   // imported before with identical content
+)
+
+func (s fileStatus) String() string {
+	switch s {
+	case statusAdded:
+		return "added"
+	case statusChanged:
+		return "changed"
+	default:
+		return "unchanged"
+	}
+}
+
+// manifestEntry records the content hash of an imported file (import_manifest collection)
+type manifestEntry struct {
+	Path       string    `bson:"path"` // relative to the data directory
+	Collection string    `bson:"collection"`
+	Hash       string    `bson:"hash"`
+	Size       int64     `bson:"size"`
+	ImportedAt time.Time `bson:"importedAt"`
+}
+
+// manifest is the in-memory view of import_manifest used to skip unchanged files.
+// It is safe for concurrent use by import workers.
+type manifest struct {
+	collection *mongo.Collection
+
+	mu    sync.RWMutex
+	files map[string]manifestEntry
+}
+
+func loadManifest(ctx context.Context, db *mongo.Database) (*manifest, error) {
+	m := &manifest{
+		collection: db.Collection("import_manifest"),
+		files:      make(map[string]manifestEntry),
+	}
+
+	opts := options.Find().SetProjection(bson.D{{Key: "path", Value: 1}, {Key: "collection", Value: 1}, {Key: "hash", Value: 1}})
+	cursor, err := m.collection.Find(ctx, bson.D{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var entry manifestEntry
+		if err := cursor.Decode(&entry); err != nil {
+			return nil, err
+		}
+		m.files[entry.Path] = entry
+	}
+	return m, cursor.Err()
+}
+
+// status compares a file's hash with the one recorded at its last import
+func (m *manifest) status(path, hash string) fileStatus {
+	m.mu.RLock()
+	previous, ok := m.files[path]
+	m.mu.RUnlock()
+	switch {
+	case !ok:
+		return statusAdded
+	case previous.Hash != hash:
+		return statusChanged
+	default:
+		return statusUnchanged
+	}
+}
+
+// record stores the hash of a successfully imported file
+func (m *manifest) record(ctx context.Context, entry manifestEntry) error {
+	return m.recordMany(ctx, []manifestEntry{entry})
+}
+
+// recordMany stores the hashes of a batch of imported files in one bulk write
+func (m *manifest) recordMany(ctx context.Context, entries []manifestEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	writes := make([]mongo.WriteModel, 0, len(entries))
+	for i := range entries {
+		entries[i].ImportedAt = now
+		writes = append(writes, mongo.NewReplaceOneModel().
+			SetFilter(bson.D{{Key: "path", Value: entries[i].Path}}).
+			SetReplacement(entries[i]).
+			SetUpsert(true))
+	}
+	if _, err := m.collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	for _, entry := range entries {
+		m.files[entry.Path] = entry
+	}
+	m.mu.Unlock()
+	return nil
+}
+
+// accepted reports whether a file was last imported into the vehicles
+// collection rather than quarantined
+func (m *manifest) accepted(path string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.files[path].Collection == "vehicles"
+}
+
+func hashContent(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spehlivan/price-list/backend/internal/models"
+)
+
+// Import stages a failure can occur in
+const (
+	stageRead     = "read"
+	stageParse    = "parse"
+	stageWrite    = "write"
+	stageManifest = "manifest"
+)
+
+// brandSummary counts files per status for one brand
+type brandSummary struct {
+	Added       int `json:"added"`
+	Changed     int `json:"changed"`
+	Unchanged   int `json:"unchanged"`
+	Quarantined int `json:"quarantined"`
+	Flagged     int `json:"flagged"` // imported despite an anomaly verdict
+	Failed      int `json:"failed"`
+}
+
+// importFailure describes one file that could not be imported
+type importFailure struct {
+	Path  string `json:"path"`
+	Brand string `json:"brand,omitempty"`
+	Stage string `json:"stage"`
+	Error string `json:"error"`
+}
+
+// importReport collects the outcome of a migration run. Safe for concurrent use.
+type importReport struct {
+	mu sync.Mutex
+
+	StartedAt  time.Time                `json:"startedAt"`
+	FinishedAt time.Time                `json:"finishedAt"`
+	DryRun     bool                     `json:"dryRun"`
+	Brands     map[string]*brandSummary `json:"brands"`
+	Failures   []importFailure          `json:"failures"`
+	Quarantine []quarantineNote         `json:"quarantine"`
+	Anomalies  []models.SnapshotVerdict `json:"anomalies"`
+}
+
+// quarantineNote lists a quarantined file and why it was rejected
+type quarantineNote struct {
+	Path    string   `json:"path"`
+	Brand   string   `json:"brand"`
+	Reasons []string `json:"reasons"`
+}
+
+func newImportReport(dryRun bool) *importReport {
+	return &importReport{
+		StartedAt:  time.Now().UTC(),
+		DryRun:     dryRun,
+		Brands:     make(map[string]*brandSummary),
+		Failures:   []importFailure{},
+		Quarantine: []quarantineNote{},
+		Anomalies:  []models.SnapshotVerdict{},
+	}
+}
+
+// brand returns the summary of a brand, creating it if needed
+func (r *importReport) brand(brandID string) *brandSummary {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.Brands[brandID]
+	if !ok {
+		s = &brandSummary{}
+		r.Brands[brandID] = s
+	}
+	return s
+}
+
+// count adds a processed file to its brand's summary
+func (r *importReport) count(brandID string, status fileStatus) {
+	s := r.brand(brandID)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch status {
+	case statusAdded:
+		s.Added++
+	case statusChanged:
+		s.Changed++
+	default:
+		s.Unchanged++
+	}
+}
+
+// fail records a failed file
+func (r *importReport) fail(path, brandID, stage string, err error) {
+	if brandID != "" {
+		s := r.brand(brandID)
+		r.mu.Lock()
+		s.Failed++
+		r.mu.Unlock()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Failures = append(r.Failures, importFailure{Path: path, Brand: brandID, Stage: stage, Error: err.Error()})
+}
+
+// quarantine records a file rejected by validation
+func (r *importReport) quarantine(path, brandID string, reasons []string) {
+	s := r.brand(brandID)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s.Quarantined++
+	r.Quarantine = append(r.Quarantine, quarantineNote{Path: path, Brand: brandID, Reasons: reasons})
+}
+
+// anomaly records an anomaly guard verdict. Rejected files are also counted
+// as quarantined by the caller.
+func (r *importReport) anomaly(v *models.SnapshotVerdict) {
+	s := r.brand(v.BrandID)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if v.Verdict == models.VerdictFlagged {
+		s.Flagged++
+	}
+	r.Anomalies = append(r.Anomalies, *v)
+}
+
+// print writes the per-brand table and the failures to stdout
+func (r *importReport) print() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	brandIDs := make([]string, 0, len(r.Brands))
+	for id := range r.Brands {
+		brandIDs = append(brandIDs, id)
+	}
+	sort.Strings(brandIDs)
+
+	var total brandSummary
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "BRAND\tADDED\tCHANGED\tUNCHANGED\tQUARANTINED\tFLAGGED\tFAILED")
+	for _, id := range brandIDs {
+		s := r.Brands[id]
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\t%d\n", id, s.Added, s.Changed, s.Unchanged, s.Quarantined, s.Flagged, s.Failed)
+		total.Added += s.Added
+		total.Changed += s.Changed
+		total.Unchanged += s.Unchanged
+		total.Quarantined += s.Quarantined
+		total.Flagged += s.Flagged
+		total.Failed += s.Failed
+	}
+	fmt.Fprintf(w, "TOTAL\t%d\t%d\t%d\t%d\t%d\t%d\n", total.Added, total.Changed, total.Unchanged, total.Quarantined, total.Flagged, total.Failed)
+	w.Flush()
+
+	flagged := 0
+	for _, v := range r.Anomalies {
+		if v.Verdict == models.VerdictFlagged {
+			flagged++
+		}
+	}
+	if flagged > 0 {
+		sort.Slice(r.Anomalies, func(i, j int) bool { return r.Anomalies[i].Path < r.Anomalies[j].Path })
+		fmt.Printf("\n%d file(s) imported with anomalies:\n", flagged)
+		for _, v := range r.Anomalies {
+			if v.Verdict != models.VerdictFlagged {
+				continue
+			}
+			fmt.Printf("  %s\n", v.Path)
+			for _, violation := range v.Comparison.Violations {
+				fmt.Printf("    - %s\n", violation)
+			}
+		}
+	}
+
+	if len(r.Quarantine) > 0 {
+		sort.Slice(r.Quarantine, func(i, j int) bool { return r.Quarantine[i].Path < r.Quarantine[j].Path })
+		fmt.Printf("\n%d file(s) quarantined:\n", len(r.Quarantine))
+		for _, q := range r.Quarantine {
+			fmt.Printf("  %s\n", q.Path)
+			for _, reason := range q.Reasons {
+				fmt.Printf("    - %s\n", reason)
+			}
+		}
+	}
+
+	if len(r.Failures) == 0 {
+		return
+	}
+	sort.Slice(r.Failures, func(i, j int) bool { return r.Failures[i].Path < r.Failures[j].Path })
+	fmt.Printf("\n%d failure(s):\n", len(r.Failures))
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PATH\tSTAGE\tERROR")
+	for _, f := range r.Failures {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", f.Path, f.Stage, f.Error)
+	}
+	w.Flush()
+}
+
+// writeJSON saves the report to path
+func (r *importReport) writeJSON(path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// hasFailures reports whether any file failed to import
+func (r *importReport) hasFailures() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.Failures) > 0
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"github.com/spehlivan/price-list/backend/internal/variant"
+	"github.com/spehlivan/price-list/backend/internal/vocab"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// loadVariants builds the variant resolver from the manual alias table
+// maintained by cmd/variants
+func loadVariants(ctx context.Context, db *mongo.Database) (*variant.Resolver, error) {
+	cursor, err := db.Collection("variant_aliases").Find(ctx, bson.D{})
+	if err != nil {
+		return nil, err
+	}
+	var aliases []models.VariantAlias
+	if err := cursor.All(ctx, &aliases); err != nil {
+		return nil, err
+	}
+	return variant.NewResolver(aliases), nil
+}
+
+// loadVocabulary builds the fuel, transmission and drive type table from the
+// built-in defaults and the admin overrides
+func loadVocabulary(ctx context.Context, db *mongo.Database) (*vocab.Table, error) {
+	cursor, err := db.Collection("vocabulary").Find(ctx, bson.D{})
+	if err != nil {
+		return nil, err
+	}
+	var overrides []models.VocabularyEntry
+	if err := cursor.All(ctx, &overrides); err != nil {
+		return nil, err
+	}
+	return vocab.New(overrides), nil
+}
+
+// stampRows writes the variant identity (variantKey, variantId) and the
+// canonical attributes into every raw row of a validated snapshot. The typed
+// rows are parsed from the same file, so they line up with the raw ones index
+// by index.
+func stampRows(p *preparedVehicle, resolver *variant.Resolver, vocabulary *vocab.Table) {
+	rows, ok := p.raw["rows"].([]interface{})
+	if !ok || p.doc == nil || len(rows) != len(p.doc.Rows) {
+		return
+	}
+	for i, raw := range rows {
+		row, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		key := variant.RowKey(p.doc.Rows[i])
+		id := resolver.ID(p.file.BrandID, key)
+		canonical := vocabulary.Row(p.doc.Rows[i])
+		row["variantKey"] = key
+		row["variantId"] = id
+		row["canonical"] = canonical
+		p.doc.Rows[i].VariantKey = key
+		p.doc.Rows[i].VariantID = id
+		p.doc.Rows[i].Canonical = &canonical
+	}
+}
@@ -0,0 +1,464 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"github.com/spehlivan/price-list/backend/internal/validation"
+	"github.com/spehlivan/price-list/backend/internal/variant"
+	"github.com/spehlivan/price-list/backend/internal/vocab"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// vehicleFile is one brand snapshot (data/YYYY/MM/brandId/DD.json)
+type vehicleFile struct {
+	BrandID string
+	Date    string
+	Path    string // absolute
+	RelPath string // relative to the data directory, the manifest key
+	Hash    string
+	Size    int64
+	Status  fileStatus
+}
+
+// planVehicles walks the data directory and classifies every snapshot that
+// passes the filters against the manifest
+func planVehicles(dataDir string, m *manifest, f filters) ([]vehicleFile, error) {
+	years, err := os.ReadDir(dataDir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read data directory: %w", err)
+	}
+
+	var files []vehicleFile
+	for _, yearEntry := range years {
+		if !yearEntry.IsDir() {
+			continue
+		}
+		year := yearEntry.Name()
+		// Only process year directories (e.g., "2026")
+		if len(year) != 4 {
+			continue
+		}
+
+		yearPath := filepath.Join(dataDir, year)
+		months, err := os.ReadDir(yearPath)
+		if err != nil {
+			continue
+		}
+
+		for _, monthEntry := range months {
+			if !monthEntry.IsDir() {
+				continue
+			}
+			month := monthEntry.Name()
+			// Skip whole months before --since
+			if f.since != "" && year+"-"+month < f.since[:7] {
+				continue
+			}
+			monthPath := filepath.Join(yearPath, month)
+
+			brands, err := os.ReadDir(monthPath)
+			if err != nil {
+				continue
+			}
+
+			for _, brandEntry := range brands {
+				if !brandEntry.IsDir() {
+					continue
+				}
+				brandID := brandEntry.Name()
+				if !f.includesBrand(brandID) {
+					continue
+				}
+				brandPath := filepath.Join(monthPath, brandID)
+
+				days, err := os.ReadDir(brandPath)
+				if err != nil {
+					continue
+				}
+
+				for _, dayEntry := range days {
+					if dayEntry.IsDir() || !strings.HasSuffix(dayEntry.Name(), ".json") {
+						continue
+					}
+
+					day := strings.TrimSuffix(dayEntry.Name(), ".json")
+					date := fmt.Sprintf("%s-%s-%s", year, month, day)
+					if f.since != "" && date < f.since {
+						continue
+					}
+
+					filePath := filepath.Join(brandPath, dayEntry.Name())
+					data, err := os.ReadFile(filePath)
+					if err != nil {
+						return nil, fmt.Errorf("reading %s: %w", filePath, err)
+					}
+
+					relPath := filepath.ToSlash(filepath.Join(year, month, brandID, dayEntry.Name()))
+					hash := hashContent(data)
+					files = append(files, vehicleFile{
+						BrandID: brandID,
+						Date:    date,
+						Path:    filePath,
+						RelPath: relPath,
+						Hash:    hash,
+						Size:    int64(len(data)),
+						Status:  m.status(relPath, hash),
+					})
+				}
+			}
+		}
+	}
+	return files, nil
+}
+
+// importOptions tunes the vehicle importer
+type importOptions struct {
+	workers   int           // brands imported concurrently
+	batchSize int           // documents per BulkWrite
+	timeout   time.Duration // per database operation
+}
+
+// vehicleImporter writes vehicle snapshots and quarantines invalid ones
+type vehicleImporter struct {
+	vehicles   *mongo.Collection
+	quarantine *mongo.Collection
+	manifest   *manifest
+	guard      *anomalyGuard
+	variants   *variant.Resolver
+	vocabulary *vocab.Table
+	opts       importOptions
+	report     *importReport
+}
+
+// preparedVehicle is a parsed snapshot ready to be written or quarantined
+type preparedVehicle struct {
+	file    vehicleFile
+	raw     bson.M
+	doc     *models.VehicleDocument // typed view, nil when the schema does not match
+	reasons []string                // contract violations; non-empty means quarantine
+	verdict *models.SnapshotVerdict // anomaly guard verdict, nil when plausible
+}
+
+// quarantinedFile is the document stored in the quarantine collection
+type quarantinedFile struct {
+	models.QuarantineEntry `bson:",inline"`
+	Document               bson.M `bson:"document,omitempty"`
+}
+
+// importVehicles upserts every added or changed snapshot with batched bulk
+// writes, one brand per worker, and records imported files in the manifest.
+// Snapshots violating the data contract, or rejected by the anomaly guard, go
+// to the quarantine collection. Accepted rows are stamped with their variant
+// identity and canonical fuel, transmission and drive type.
+func importVehicles(db *mongo.Database, m *manifest, files []vehicleFile, guard *anomalyGuard, variants *variant.Resolver, vocabulary *vocab.Table, opts importOptions, report *importReport) {
+	imp := &vehicleImporter{
+		vehicles:   db.Collection("vehicles"),
+		quarantine: db.Collection("quarantine"),
+		manifest:   m,
+		guard:      guard,
+		variants:   variants,
+		vocabulary: vocabulary,
+		opts:       opts,
+		report:     report,
+	}
+
+	byBrand := make(map[string][]vehicleFile)
+	var brandIDs []string
+	for _, file := range files {
+		if _, ok := byBrand[file.BrandID]; !ok {
+			brandIDs = append(brandIDs, file.BrandID)
+		}
+		byBrand[file.BrandID] = append(byBrand[file.BrandID], file)
+	}
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < max(opts.workers, 1); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for brandID := range jobs {
+				imp.importBrand(byBrand[brandID])
+			}
+		}()
+	}
+	for _, brandID := range brandIDs {
+		jobs <- brandID
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// importBrand imports the snapshots of one brand in batches. Files arrive in
+// date order, so each one is checked against the last accepted snapshot.
+func (imp *vehicleImporter) importBrand(files []vehicleFile) {
+	batch := make([]*preparedVehicle, 0, imp.opts.batchSize)
+	var base baseline
+	for _, file := range files {
+		if file.Status == statusUnchanged {
+			if imp.manifest.accepted(file.RelPath) {
+				base.skip(file)
+			}
+			imp.report.count(file.BrandID, file.Status)
+			continue
+		}
+
+		prepared, stage, err := prepareVehicle(file)
+		if err != nil {
+			imp.report.fail(file.RelPath, file.BrandID, stage, err)
+			continue
+		}
+		if len(prepared.reasons) > 0 {
+			imp.quarantineFile(prepared)
+			continue
+		}
+
+		verdict, err := imp.guard.check(&base, prepared)
+		if err != nil {
+			imp.report.fail(file.RelPath, file.BrandID, stageRead, fmt.Errorf("anomaly baseline: %w", err))
+			continue
+		}
+		if verdict != nil {
+			prepared.verdict = verdict
+			if err := imp.guard.record(verdict); err != nil {
+				imp.report.fail(file.RelPath, file.BrandID, stageWrite, err)
+				continue
+			}
+			imp.report.anomaly(verdict)
+			if verdict.Verdict == models.VerdictRejected {
+				prepared.reasons = anomalyReasons(verdict)
+				imp.quarantineFile(prepared)
+				continue
+			}
+		}
+		base.stage(prepared.doc)
+		stampRows(prepared, imp.variants, imp.vocabulary)
+
+		batch = append(batch, prepared)
+		if len(batch) >= imp.opts.batchSize {
+			base.commit(imp.flush(batch))
+			batch = batch[:0]
+		}
+	}
+	base.commit(imp.flush(batch))
+}
+
+// flush bulk-writes a batch, then records the successful files in the
+// manifest. It returns the newest snapshot written, nil when none was.
+func (imp *vehicleImporter) flush(batch []*preparedVehicle) *models.VehicleDocument {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	writes := make([]mongo.WriteModel, len(batch))
+	for i, p := range batch {
+		// Upsert: replace if already exists for this brand+date
+		filter := bson.D{
+			{Key: "brandId", Value: p.file.BrandID},
+			{Key: "date", Value: p.file.Date},
+		}
+		writes[i] = mongo.NewReplaceOneModel().SetFilter(filter).SetReplacement(p.raw).SetUpsert(true)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), imp.opts.timeout)
+	defer cancel()
+
+	// Unordered: one bad document must not block the rest of the batch
+	failed := make(map[int]error)
+	_, err := imp.vehicles.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		var bulkErr mongo.BulkWriteException
+		if errors.As(err, &bulkErr) && len(bulkErr.WriteErrors) > 0 {
+			for _, we := range bulkErr.WriteErrors {
+				failed[we.Index] = we
+			}
+		} else {
+			for i := range batch {
+				failed[i] = err
+			}
+		}
+	}
+
+	entries := make([]manifestEntry, 0, len(batch))
+	var written []vehicleFile
+	var paths, plausible []string
+	var newest *models.VehicleDocument
+	for i, p := range batch {
+		if werr, ok := failed[i]; ok {
+			imp.report.fail(p.file.RelPath, p.file.BrandID, stageWrite, werr)
+			continue
+		}
+		newest = p.doc
+		entries = append(entries, manifestEntry{Path: p.file.RelPath, Collection: "vehicles", Hash: p.file.Hash, Size: p.file.Size})
+		written = append(written, p.file)
+		paths = append(paths, p.file.RelPath)
+		if p.verdict == nil {
+			plausible = append(plausible, p.file.RelPath)
+		}
+	}
+
+	writeCtx, writeCancel := context.WithTimeout(context.Background(), imp.opts.timeout)
+	defer writeCancel()
+
+	// A corrected file replaces its quarantined predecessor
+	if len(paths) > 0 {
+		if _, err := imp.quarantine.DeleteMany(writeCtx, bson.D{{Key: "path", Value: bson.D{{Key: "$in", Value: paths}}}}); err != nil {
+			log.Printf("  Warning: could not clear quarantine for %d files: %v", len(paths), err)
+		}
+	}
+	if err := imp.guard.clear(writeCtx, plausible); err != nil {
+		log.Printf("  Warning: could not clear anomaly verdicts for %d files: %v", len(plausible), err)
+	}
+
+	if err := imp.manifest.recordMany(writeCtx, entries); err != nil {
+		// The documents are imported; they will be re-imported on the next run
+		for _, file := range written {
+			imp.report.fail(file.RelPath, file.BrandID, stageManifest, err)
+		}
+		return newest
+	}
+	for _, file := range written {
+		imp.report.count(file.BrandID, file.Status)
+	}
+	return newest
+}
+
+// quarantineFile stores an invalid snapshot with its reasons instead of importing it.
+// The file is recorded in the manifest so it is not re-checked until it changes.
+// Anomaly rejections depend on the neighbouring dates and the guard settings,
+// so they are re-checked on every run instead.
+func (imp *vehicleImporter) quarantineFile(p *preparedVehicle) {
+	ctx, cancel := context.WithTimeout(context.Background(), imp.opts.timeout)
+	defer cancel()
+
+	doc := quarantinedFile{
+		QuarantineEntry: models.QuarantineEntry{
+			Path:          p.file.RelPath,
+			BrandID:       p.file.BrandID,
+			Date:          p.file.Date,
+			Hash:          p.file.Hash,
+			Reasons:       p.reasons,
+			QuarantinedAt: time.Now().UTC(),
+		},
+		Document: p.raw,
+	}
+	opts := options.Replace().SetUpsert(true)
+	if _, err := imp.quarantine.ReplaceOne(ctx, bson.D{{Key: "path", Value: p.file.RelPath}}, doc, opts); err != nil {
+		imp.report.fail(p.file.RelPath, p.file.BrandID, stageWrite, err)
+		return
+	}
+
+	if p.verdict != nil {
+		imp.report.quarantine(p.file.RelPath, p.file.BrandID, p.reasons)
+		return
+	}
+	entry := manifestEntry{Path: p.file.RelPath, Collection: "quarantine", Hash: p.file.Hash, Size: p.file.Size}
+	if err := imp.manifest.record(ctx, entry); err != nil {
+		imp.report.fail(p.file.RelPath, p.file.BrandID, stageManifest, err)
+		return
+	}
+	imp.report.quarantine(p.file.RelPath, p.file.BrandID, p.reasons)
+}
+
+// prepareVehicle reads and parses a snapshot and checks it against the data
+// contract. On failure it returns the stage that failed.
+func prepareVehicle(file vehicleFile) (*preparedVehicle, string, error) {
+	data, err := os.ReadFile(file.Path)
+	if err != nil {
+		return nil, stageRead, err
+	}
+
+	var raw bson.M
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, stageParse, err
+	}
+
+	// Add date field
+	raw["date"] = file.Date
+	if _, ok := raw["brandId"]; !ok {
+		raw["brandId"] = file.BrandID
+	}
+
+	prepared := &preparedVehicle{file: file, raw: raw}
+
+	// The raw document is stored as-is to keep unknown fields; the typed
+	// decode is only used to validate it
+	var doc models.VehicleDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		prepared.reasons = []string{"schema: " + err.Error()}
+		return prepared, "", nil
+	}
+	doc.Date = file.Date
+	prepared.doc = &doc
+	prepared.reasons = validation.Snapshot(&doc, file.BrandID, file.Date)
+	return prepared, "", nil
+}
+
+// dryRunVehicles prints the files a real run would import or quarantine and
+// returns the planned counts per brand without writing anything
+func dryRunVehicles(files []vehicleFile, m *manifest, guard *anomalyGuard) *importReport {
+	report := newImportReport(true)
+	fmt.Println("Planned vehicle imports:")
+	pending := 0
+	bases := make(map[string]*baseline)
+	for _, file := range files {
+		base, ok := bases[file.BrandID]
+		if !ok {
+			base = &baseline{}
+			bases[file.BrandID] = base
+		}
+		if file.Status == statusUnchanged {
+			if m.accepted(file.RelPath) {
+				base.skip(file)
+			}
+			report.count(file.BrandID, file.Status)
+			continue
+		}
+		pending++
+
+		prepared, stage, err := prepareVehicle(file)
+		if err == nil && len(prepared.reasons) == 0 {
+			prepared.verdict, err = guard.check(base, prepared)
+			if err != nil {
+				stage, err = stageRead, fmt.Errorf("anomaly baseline: %w", err)
+			}
+		}
+		switch {
+		case err != nil:
+			fmt.Printf("  %-10s %s (%s: %v)\n", "fail", file.RelPath, stage, err)
+			report.fail(file.RelPath, file.BrandID, stage, err)
+		case len(prepared.reasons) > 0:
+			fmt.Printf("  %-10s %s\n", "quarantine", file.RelPath)
+			report.quarantine(file.RelPath, file.BrandID, prepared.reasons)
+		case prepared.verdict != nil && prepared.verdict.Verdict == models.VerdictRejected:
+			fmt.Printf("  %-10s %s\n", "reject", file.RelPath)
+			report.anomaly(prepared.verdict)
+			report.quarantine(file.RelPath, file.BrandID, anomalyReasons(prepared.verdict))
+		default:
+			label := file.Status.String()
+			if prepared.verdict != nil {
+				label = "flag"
+				report.anomaly(prepared.verdict)
+			}
+			fmt.Printf("  %-10s %s\n", label, file.RelPath)
+			report.count(file.BrandID, file.Status)
+			base.accept(prepared.doc)
+		}
+	}
+	if pending == 0 {
+		fmt.Println("  (nothing to import)")
+	}
+	return report
+}
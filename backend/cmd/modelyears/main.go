@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spehlivan/price-list/backend/config"
+	"github.com/spehlivan/price-list/backend/internal/modelyear"
+	"github.com/spehlivan/price-list/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const usageText = `Usage: modelyears [flags]
+
+Rebuilds the model_year_changes collection behind
+/api/v1/intel/lifecycle/transitions: every model's switch from one model year
+to the next, detected over each brand's full snapshot history with the entry
+price step and the old year's run-out discounts. Run it after each import;
+transitions are replaced per brand.
+
+Flags:
+`
+
+// brandResult summarizes the detection run of one brand
+type brandResult struct {
+	brandID     string
+	transitions int
+	ongoing     int
+	removed     int64
+}
+
+func main() {
+	cfg := config.Load()
+
+	brandList := flag.String("brand", "", "only rebuild these brand ids (comma-separated)")
+	dryRun := flag.Bool("dry-run", false, "detect transitions without writing anything")
+	opTimeout := flag.Duration("timeout", 60*time.Second, "timeout per brand")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usageText)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(options.Client().ApplyURI(cfg.MongoURI))
+	if err != nil {
+		log.Fatalf("Failed to connect to MongoDB: %v", err)
+	}
+	defer client.Disconnect(context.Background())
+
+	if err := client.Ping(ctx, nil); err != nil {
+		log.Fatalf("Failed to ping MongoDB: %v", err)
+	}
+
+	db := client.Database(cfg.Database)
+	vehicles := repository.NewVehicleRepository(db)
+	repo := repository.NewModelYearRepository(db)
+	if !*dryRun {
+		if err := repo.EnsureIndexes(ctx); err != nil {
+			log.Printf("Warning: Failed to ensure model-year indexes: %v", err)
+		}
+	}
+
+	brandIDs, err := vehicles.BrandIDs(ctx, *brandList)
+	if err != nil {
+		log.Fatalf("Failed to list brands: %v", err)
+	}
+
+	var results []brandResult
+	failed := false
+	for _, brandID := range brandIDs {
+		res, err := rebuildBrand(vehicles, repo, brandID, *dryRun, *opTimeout)
+		if err != nil {
+			log.Printf("%s: %v", brandID, err)
+			failed = true
+			continue
+		}
+		results = append(results, res)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "BRAND\tTRANSITIONS\tONGOING\tREMOVED")
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\n", r.brandID, r.transitions, r.ongoing, r.removed)
+	}
+	w.Flush()
+
+	if failed {
+		log.Fatalf("Model-year detection completed with failures")
+	}
+	if *dryRun {
+		log.Println("Dry run: nothing was written")
+	}
+}
+
+// rebuildBrand detects one brand's transitions over its whole history and
+// replaces the stored ones
+func rebuildBrand(vehicles *repository.VehicleRepository, repo *repository.ModelYearRepository, brandID string, dryRun bool, timeout time.Duration) (brandResult, error) {
+	res := brandResult{brandID: brandID}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	snapshots, err := vehicles.GetHistory(ctx, brandID, "", "")
+	if err != nil {
+		return res, err
+	}
+
+	changes := modelyear.Detect(snapshots)
+	res.transitions = len(changes)
+	for _, t := range changes {
+		if t.Ongoing {
+			res.ongoing++
+		}
+	}
+	if dryRun {
+		return res, nil
+	}
+
+	res.removed, err = repo.ReplaceBrand(ctx, brandID, changes)
+	return res, err
+}
@@ -0,0 +1,297 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spehlivan/price-list/backend/config"
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"github.com/spehlivan/price-list/backend/internal/repository"
+	"github.com/spehlivan/price-list/backend/internal/variant"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const usageText = `Usage: variants <command> [flags]
+
+Commands:
+  names -brand BRAND [-model TEXT]
+                      list variant keys with their labels and first/last dates
+  alias -brand BRAND -from KEY -to KEY [-note TEXT]
+                      record that KEY FROM is the same variant as KEY TO and
+                      restamp the brand's history
+  unalias -brand BRAND -from KEY
+                      remove an alias and restamp the brand's history
+  list [-brand BRAND] list aliases
+  stamp [-brand BRAND]
+                      (re)write variantKey and variantId on every stored row
+
+Keys are normalized model|trim|engine strings as printed by names. After
+stamping, rerun cmd/materialize so vehicles_daily carries the new ids.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usageText)
+		os.Exit(2)
+	}
+
+	cfg := config.Load()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	client, err := mongo.Connect(options.Client().ApplyURI(cfg.MongoURI))
+	if err != nil {
+		log.Fatalf("Failed to connect to MongoDB: %v", err)
+	}
+	defer client.Disconnect(context.Background())
+
+	if err := client.Ping(ctx, nil); err != nil {
+		log.Fatalf("Failed to ping MongoDB: %v", err)
+	}
+
+	db := client.Database(cfg.Database)
+	repo := repository.NewVariantRepository(db)
+	if err := repo.EnsureIndexes(ctx); err != nil {
+		log.Printf("Warning: Failed to ensure variant alias indexes: %v", err)
+	}
+	vehicles := db.Collection("vehicles")
+
+	args := os.Args[2:]
+	switch os.Args[1] {
+	case "names":
+		err = printNames(ctx, vehicles, args)
+	case "alias":
+		err = addAlias(ctx, repo, vehicles, args)
+	case "unalias":
+		err = removeAlias(ctx, repo, vehicles, args)
+	case "list":
+		err = listAliases(ctx, repo, args)
+	case "stamp":
+		err = stampCommand(ctx, repo, vehicles, args)
+	default:
+		fmt.Fprint(os.Stderr, usageText)
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatalf("%s: %v", os.Args[1], err)
+	}
+}
+
+func printNames(ctx context.Context, vehicles *mongo.Collection, args []string) error {
+	fs := flag.NewFlagSet("names", flag.ExitOnError)
+	brand := fs.String("brand", "", "brand id")
+	model := fs.String("model", "", "only keys whose model contains this text")
+	fs.Parse(args)
+
+	if *brand == "" {
+		return errors.New("-brand is required")
+	}
+
+	pipeline := bson.A{
+		bson.D{{Key: "$match", Value: bson.D{{Key: "brandId", Value: *brand}}}},
+		bson.D{{Key: "$unwind", Value: "$rows"}},
+		bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{
+				{Key: "model", Value: "$rows.model"},
+				{Key: "trim", Value: "$rows.trim"},
+				{Key: "engine", Value: "$rows.engine"},
+			}},
+			{Key: "firstSeen", Value: bson.D{{Key: "$min", Value: "$date"}}},
+			{Key: "lastSeen", Value: bson.D{{Key: "$max", Value: "$date"}}},
+		}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "_id.model", Value: 1}, {Key: "firstSeen", Value: 1}}}},
+	}
+	cursor, err := vehicles.Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	var groups []struct {
+		Label struct {
+			Model  string `bson:"model"`
+			Trim   string `bson:"trim"`
+			Engine string `bson:"engine"`
+		} `bson:"_id"`
+		FirstSeen string `bson:"firstSeen"`
+		LastSeen  string `bson:"lastSeen"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return err
+	}
+
+	needle := variant.Normalize(*model)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tFIRST\tLAST\tMODEL\tTRIM\tENGINE")
+	for _, g := range groups {
+		key := variant.Key(g.Label.Model, g.Label.Trim, g.Label.Engine)
+		if needle != "" && !strings.Contains(strings.SplitN(key, "|", 2)[0], needle) {
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", key, g.FirstSeen, g.LastSeen, g.Label.Model, g.Label.Trim, g.Label.Engine)
+	}
+	return w.Flush()
+}
+
+func addAlias(ctx context.Context, repo *repository.VariantRepository, vehicles *mongo.Collection, args []string) error {
+	fs := flag.NewFlagSet("alias", flag.ExitOnError)
+	brand := fs.String("brand", "", "brand id")
+	from := fs.String("from", "", "key of the renamed (or old) label")
+	to := fs.String("to", "", "key it is the same variant as")
+	note := fs.String("note", "", "why the labels are the same variant")
+	fs.Parse(args)
+
+	if *brand == "" || *from == "" || *to == "" {
+		return errors.New("-brand, -from and -to are required")
+	}
+	if strings.Count(*from, "|") != 2 || strings.Count(*to, "|") != 2 {
+		return errors.New("keys must have the form model|trim|engine")
+	}
+
+	resolver, err := repo.Resolver(ctx, *brand)
+	if err != nil {
+		return err
+	}
+	if resolver.Canonical(*brand, *to) == *from {
+		return fmt.Errorf("%q already resolves to %q; the alias would form a cycle", *to, *from)
+	}
+
+	alias := &models.VariantAlias{BrandID: *brand, FromKey: *from, ToKey: *to, Note: *note}
+	if err := repo.SetAlias(ctx, alias); err != nil {
+		return err
+	}
+	fmt.Printf("Aliased %q -> %q for %s\n", *from, *to, *brand)
+	return stamp(ctx, repo, vehicles, *brand)
+}
+
+func removeAlias(ctx context.Context, repo *repository.VariantRepository, vehicles *mongo.Collection, args []string) error {
+	fs := flag.NewFlagSet("unalias", flag.ExitOnError)
+	brand := fs.String("brand", "", "brand id")
+	from := fs.String("from", "", "key whose alias is removed")
+	fs.Parse(args)
+
+	if *brand == "" || *from == "" {
+		return errors.New("-brand and -from are required")
+	}
+	if err := repo.RemoveAlias(ctx, *brand, *from); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return fmt.Errorf("no alias of %q for %s", *from, *brand)
+		}
+		return err
+	}
+	fmt.Printf("Removed alias of %q for %s\n", *from, *brand)
+	return stamp(ctx, repo, vehicles, *brand)
+}
+
+func listAliases(ctx context.Context, repo *repository.VariantRepository, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	brand := fs.String("brand", "", "restrict to a brand id")
+	fs.Parse(args)
+
+	aliases, err := repo.Aliases(ctx, *brand)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "BRAND\tFROM\tTO\tCREATED\tNOTE")
+	for _, a := range aliases {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", a.BrandID, a.FromKey, a.ToKey, a.CreatedAt.Format("2006-01-02"), a.Note)
+	}
+	return w.Flush()
+}
+
+func stampCommand(ctx context.Context, repo *repository.VariantRepository, vehicles *mongo.Collection, args []string) error {
+	fs := flag.NewFlagSet("stamp", flag.ExitOnError)
+	brand := fs.String("brand", "", "restrict to a brand id")
+	fs.Parse(args)
+	return stamp(ctx, repo, vehicles, *brand)
+}
+
+// stamp rewrites variantKey and variantId on every row of a brand's stored
+// snapshots (all brands when brandID is empty). Only those two fields are
+// set, so the rest of each raw row stays as imported.
+func stamp(ctx context.Context, repo *repository.VariantRepository, vehicles *mongo.Collection, brandID string) error {
+	resolver, err := repo.Resolver(ctx, brandID)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.D{}
+	if brandID != "" {
+		filter = append(filter, bson.E{Key: "brandId", Value: brandID})
+	}
+	opts := options.Find().SetProjection(bson.D{
+		{Key: "brandId", Value: 1},
+		{Key: "rows.model", Value: 1},
+		{Key: "rows.trim", Value: 1},
+		{Key: "rows.engine", Value: 1},
+	})
+	cursor, err := vehicles.Find(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	const batchSize = 200
+	var writes []mongo.WriteModel
+	var docs, rows int
+	flush := func() error {
+		if len(writes) == 0 {
+			return nil
+		}
+		_, err := vehicles.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+		writes = writes[:0]
+		return err
+	}
+
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID      bson.ObjectID         `bson:"_id"`
+			BrandID string                `bson:"brandId"`
+			Rows    []models.PriceListRow `bson:"rows"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return err
+		}
+		set := bson.D{}
+		for i, row := range doc.Rows {
+			key := variant.RowKey(row)
+			prefix := "rows." + strconv.Itoa(i) + "."
+			set = append(set,
+				bson.E{Key: prefix + "variantKey", Value: key},
+				bson.E{Key: prefix + "variantId", Value: resolver.ID(doc.BrandID, key)},
+			)
+		}
+		if len(set) == 0 {
+			continue
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.D{{Key: "_id", Value: doc.ID}}).
+			SetUpdate(bson.D{{Key: "$set", Value: set}}))
+		docs++
+		rows += len(doc.Rows)
+		if len(writes) >= batchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Stamped %d rows in %d snapshots\n", rows, docs)
+	return nil
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -13,6 +15,29 @@ type Config struct {
 	Port        string
 	GinMode     string
 	CORSOrigins string
+
+	// TrustedProxies lists the addresses or CIDRs of reverse proxies allowed to
+	// set X-Forwarded-For. Empty trusts none, so the client IP used for rate
+	// limiting is always the peer address and cannot be spoofed by a header.
+	TrustedProxies []string
+
+	// APIKeysRequired rejects anonymous requests on read routes when true.
+	// The public frontend reads anonymously, so it is off by default;
+	// anonymous reads are rate limited per client IP and admin always needs a key.
+	APIKeysRequired bool
+
+	// Rate limiting: store is "memory" (single replica), "mongo" (shared
+	// across replicas) or "off". Limits are token buckets of Burst tokens
+	// refilled at PerSecond, per client IP or per API key.
+	RateLimitStore        string
+	RateLimitBurst        float64
+	RateLimitPerSecond    float64
+	RateLimitKeyBurst     float64
+	RateLimitKeyPerSecond float64
+
+	// ErrorRetentionDays is how long cmd/migrate keeps imported collector
+	// errors in error_log before the TTL index removes them; 0 keeps them forever
+	ErrorRetentionDays int
 }
 
 func Load() *Config {
@@ -40,6 +65,18 @@ func Load() *Config {
 		Port:        getEnv("PORT", "8080"),
 		GinMode:     getEnv("GIN_MODE", "debug"),
 		CORSOrigins: getEnv("CORS_ORIGINS", "http://localhost:5173"),
+
+		TrustedProxies: getEnvList("TRUSTED_PROXIES"),
+
+		APIKeysRequired: getEnvBool("API_KEYS_REQUIRED", false),
+
+		RateLimitStore:        getEnv("RATE_LIMIT_STORE", "memory"),
+		RateLimitBurst:        getEnvFloat("RATE_LIMIT_BURST", 60),
+		RateLimitPerSecond:    getEnvFloat("RATE_LIMIT_PER_SECOND", 1),
+		RateLimitKeyBurst:     getEnvFloat("RATE_LIMIT_KEY_BURST", 300),
+		RateLimitKeyPerSecond: getEnvFloat("RATE_LIMIT_KEY_PER_SECOND", 5),
+
+		ErrorRetentionDays: getEnvInt("ERROR_RETENTION_DAYS", 90),
 	}
 }
 
@@ -49,3 +86,35 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+// getEnvList splits a comma-separated value, dropping empty items
+func getEnvList(key string) []string {
+	var values []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	if value, err := strconv.ParseBool(os.Getenv(key)); err == nil {
+		return value
+	}
+	return fallback
+}
+
+func getEnvFloat(key string, fallback float64) float64 {
+	if value, err := strconv.ParseFloat(os.Getenv(key), 64); err == nil && value > 0 {
+		return value
+	}
+	return fallback
+}
+
+func getEnvInt(key string, fallback int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil && value >= 0 {
+		return value
+	}
+	return fallback
+}
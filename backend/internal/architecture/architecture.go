@@ -0,0 +1,184 @@
+// Package architecture ports the architecture generator's trim ladders to Go
+// so a model's ladder can be computed for any date and compared across dates.
+// A ladder lists a model's priced variants cheapest first, each with its step
+// from the base price.
+package architecture
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spehlivan/price-list/backend/internal/market"
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"github.com/spehlivan/price-list/backend/internal/variant"
+)
+
+var whitespace = regexp.MustCompile(`\s+`)
+
+// ModelKey is the comparable form of a model name, so that URL slugs
+// ("id-4") and listed spellings ("ID.4") of the same model meet
+func ModelKey(model string) string {
+	return strings.ReplaceAll(variant.Normalize(strings.ReplaceAll(model, "-", " ")), ".", " ")
+}
+
+// Ladders builds one ladder per brand and model, ordered by brand then model
+func Ladders(vehicles []market.Vehicle) []models.TrimLadder {
+	groups := make(map[[2]string][]market.Vehicle)
+	var keys [][2]string
+	for _, v := range vehicles {
+		key := [2]string{v.BrandID, ModelKey(v.Row.Model)}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], v)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	ladders := make([]models.TrimLadder, 0, len(keys))
+	for _, key := range keys {
+		ladders = append(ladders, Ladder(groups[key]))
+	}
+	return ladders
+}
+
+// Find builds the ladder of a brand's model, false when it is not listed
+func Find(vehicles []market.Vehicle, brandID, model string) (models.TrimLadder, bool) {
+	key := ModelKey(model)
+	var rows []market.Vehicle
+	for _, v := range vehicles {
+		if v.BrandID == brandID && ModelKey(v.Row.Model) == key {
+			rows = append(rows, v)
+		}
+	}
+	if len(rows) == 0 {
+		return models.TrimLadder{}, false
+	}
+	return Ladder(rows), true
+}
+
+// Ladder builds the ladder of one model's vehicles. The model name and segment
+// are those of the base variant.
+func Ladder(vehicles []market.Vehicle) models.TrimLadder {
+	sorted := append([]market.Vehicle(nil), vehicles...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Price() < sorted[j].Price() })
+
+	base, top := sorted[0], sorted[len(sorted)-1]
+	basePrice := base.Price()
+	trims := make([]models.TrimStep, len(sorted))
+	for i, v := range sorted {
+		trims[i] = models.TrimStep{
+			Trim:           v.Row.Trim,
+			Price:          v.Price(),
+			PriceFormatted: market.FormatTL(v.Price()),
+			StepFromBase:   v.Price() - basePrice,
+			StepPercent:    market.Round(stepPercent(v.Price(), basePrice), 2),
+			Engine:         v.Row.Engine,
+			Transmission:   v.Row.Canonical.Transmission,
+			Fuel:           v.Fuel(),
+			VariantID:      v.VariantID,
+		}
+	}
+
+	seg := base.Segment()
+	spread := top.Price() - basePrice
+	return models.TrimLadder{
+		ID:                 strings.ToLower(whitespace.ReplaceAllString(base.BrandID+"-"+base.Row.Model, "-")),
+		Model:              base.Row.Model,
+		Brand:              base.Row.Brand,
+		BrandID:            base.BrandID,
+		Trims:              trims,
+		BasePrice:          basePrice,
+		TopPrice:           top.Price(),
+		PriceSpread:        spread,
+		PriceSpreadPercent: market.Round(stepPercent(top.Price(), basePrice), 2),
+		TrimCount:          len(trims),
+		Segment:            &seg,
+	}
+}
+
+// Competitors lists the ladders of other brands' models in the ladder's
+// segment, cheapest base price first
+func Competitors(ladders []models.TrimLadder, of models.TrimLadder) []models.CrossBrandEntry {
+	entries := []models.CrossBrandEntry{}
+	for _, l := range ladders {
+		if l.BrandID == of.BrandID || l.Segment == nil || of.Segment == nil || l.Segment.Code != of.Segment.Code {
+			continue
+		}
+		entries = append(entries, models.CrossBrandEntry{
+			Brand:              l.Brand,
+			BrandID:            l.BrandID,
+			Model:              l.Model,
+			BasePrice:          l.BasePrice,
+			TopPrice:           l.TopPrice,
+			BasePriceFormatted: market.FormatTL(l.BasePrice),
+			TopPriceFormatted:  market.FormatTL(l.TopPrice),
+			TrimCount:          l.TrimCount,
+		})
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].BasePrice < entries[j].BasePrice })
+	return entries
+}
+
+// Compare describes how a ladder changed from previous to current. An empty
+// previous ladder (the model was not listed) makes every trim an addition.
+func Compare(previous, current models.TrimLadder, from, to string) models.LadderChange {
+	change := models.LadderChange{
+		From:     from,
+		To:       to,
+		Previous: previous,
+		Steps:    []models.TrimStepChange{},
+		Added:    []models.TrimStep{},
+		Removed:  []models.TrimStep{},
+	}
+	if previous.TrimCount > 0 {
+		change.BasePriceChange = current.BasePrice - previous.BasePrice
+		change.TopPriceChange = current.TopPrice - previous.TopPrice
+		change.SpreadChange = current.PriceSpread - previous.PriceSpread
+		change.SpreadPercentChange = market.Round(current.PriceSpreadPercent-previous.PriceSpreadPercent, 2)
+	}
+
+	before := make(map[string]models.TrimStep, len(previous.Trims))
+	for _, t := range previous.Trims {
+		before[t.VariantID] = t
+	}
+	listed := make(map[string]bool, len(current.Trims))
+	for _, t := range current.Trims {
+		listed[t.VariantID] = true
+		old, ok := before[t.VariantID]
+		if !ok {
+			change.Added = append(change.Added, t)
+			continue
+		}
+		change.Steps = append(change.Steps, models.TrimStepChange{
+			VariantID:          t.VariantID,
+			Trim:               t.Trim,
+			Engine:             t.Engine,
+			FromPrice:          old.Price,
+			ToPrice:            t.Price,
+			PriceChange:        t.Price - old.Price,
+			PriceChangePercent: market.Round(stepPercent(t.Price, old.Price), 2),
+			FromStepPercent:    old.StepPercent,
+			ToStepPercent:      t.StepPercent,
+			StepPercentChange:  market.Round(t.StepPercent-old.StepPercent, 2),
+		})
+	}
+	for _, t := range previous.Trims {
+		if !listed[t.VariantID] {
+			change.Removed = append(change.Removed, t)
+		}
+	}
+	return change
+}
+
+// stepPercent returns how far price is above base in percent
+func stepPercent(price, base float64) float64 {
+	if base <= 0 {
+		return 0
+	}
+	return (price - base) / base * 100
+}
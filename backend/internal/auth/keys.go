@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// keyPrefix marks price-list API keys so they are recognizable in logs and secret scanners
+const keyPrefix = "pl"
+
+// GenerateKey creates a new random API key. It returns the plaintext key (shown
+// to the user once), its public id and the hash that is stored in MongoDB.
+// Keys have the form pl_<keyId>_<secret>.
+func GenerateKey() (plaintext, keyID, hash string, err error) {
+	idBytes := make([]byte, 4)
+	secret := make([]byte, 32)
+	if _, err := rand.Read(idBytes); err != nil {
+		return "", "", "", fmt.Errorf("generating key id: %w", err)
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", "", fmt.Errorf("generating key secret: %w", err)
+	}
+
+	keyID = hex.EncodeToString(idBytes)
+	plaintext = keyPrefix + "_" + keyID + "_" + base64.RawURLEncoding.EncodeToString(secret)
+	return plaintext, keyID, HashKey(plaintext), nil
+}
+
+// HashKey returns the hex SHA-256 digest of a plaintext key. Keys carry 256 bits
+// of entropy, so a fast hash is sufficient.
+func HashKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(plaintext)))
+	return hex.EncodeToString(sum[:])
+}
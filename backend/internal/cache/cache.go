@@ -0,0 +1,103 @@
+// Package cache memoizes rendered responses per data version. The data version
+// (e.g. the newest collectedAt) is probed cheaply; when it changes every cached
+// entry is dropped, so importing a new snapshot invalidates the cache.
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// Version identifies the state of the underlying data
+type Version struct {
+	Tag          string
+	LastModified time.Time
+}
+
+// ETag returns a strong entity tag for the response identified by key at this version
+func (v Version) ETag(key string) string {
+	sum := sha256.Sum256([]byte(v.Tag + "\x00" + key))
+	return `"` + hex.EncodeToString(sum[:12]) + `"`
+}
+
+// VersionFunc looks up the current data version
+type VersionFunc func(ctx context.Context) (Version, error)
+
+// Cache holds rendered response bodies for the current data version
+type Cache struct {
+	versionFn  VersionFunc
+	probeTTL   time.Duration
+	maxEntries int
+
+	mu       sync.Mutex
+	version  Version
+	probedAt time.Time
+	entries  map[string][]byte
+}
+
+// New creates a cache. The version is re-probed at most once per probeTTL and
+// at most maxEntries bodies are kept.
+func New(versionFn VersionFunc, probeTTL time.Duration, maxEntries int) *Cache {
+	return &Cache{
+		versionFn:  versionFn,
+		probeTTL:   probeTTL,
+		maxEntries: maxEntries,
+		entries:    make(map[string][]byte),
+	}
+}
+
+// Version returns the current data version, dropping cached bodies when it changed
+func (c *Cache) Version(ctx context.Context) (Version, error) {
+	c.mu.Lock()
+	if !c.probedAt.IsZero() && time.Since(c.probedAt) < c.probeTTL {
+		v := c.version
+		c.mu.Unlock()
+		return v, nil
+	}
+	c.mu.Unlock()
+
+	v, err := c.versionFn(ctx)
+	if err != nil {
+		return Version{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if v.Tag != c.version.Tag {
+		c.entries = make(map[string][]byte)
+	}
+	c.version = v
+	c.probedAt = time.Now()
+	return v, nil
+}
+
+// Get returns the cached body for key if it was rendered at version v
+func (c *Cache) Get(v Version, key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if v.Tag != c.version.Tag {
+		return nil, false
+	}
+	body, ok := c.entries[key]
+	return body, ok
+}
+
+// Set stores the body rendered for key at version v
+func (c *Cache) Set(v Version, key string, body []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if v.Tag != c.version.Tag {
+		return
+	}
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxEntries {
+		// Evict an arbitrary entry; hot keys are re-rendered on the next request
+		for k := range c.entries {
+			delete(c.entries, k)
+			break
+		}
+	}
+	c.entries[key] = body
+}
@@ -0,0 +1,269 @@
+// Package campaign detects campaign episodes in a brand's snapshot history.
+// A variant is on campaign while its price list shows a campaign price below
+// the list price ("listed"), or while its price sits clearly below the price
+// before a drop that is later undone ("dip"). Drops that never recover within
+// MaxDipDays are price cuts, not campaigns.
+package campaign
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/spehlivan/price-list/backend/internal/market"
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"github.com/spehlivan/price-list/backend/internal/variant"
+)
+
+const (
+	// MinDipPercent is the drop below the reference price that starts a dip
+	MinDipPercent = 2.0
+	// recoveryPercent is how close to the reference price a dip must return
+	recoveryPercent = 0.5
+	// MaxDipDays is how long a dip may last and still count as temporary
+	MaxDipDays = 90
+	// MaxGapDays is the longest run of missing snapshots an episode survives
+	MaxGapDays = 14
+)
+
+const dateLayout = "2006-01-02"
+
+// observation is one snapshot of a variant
+type observation struct {
+	date     string
+	price    float64
+	list     float64
+	campaign float64
+}
+
+// listedDepth returns the campaign discount off the list price in percent,
+// 0 without a campaign price below the list price
+func (o observation) listedDepth() float64 {
+	if o.campaign <= 0 || o.list <= 0 || o.campaign >= o.list {
+		return 0
+	}
+	return (o.list - o.campaign) / o.list * 100
+}
+
+// series is the observed history of one variant
+type series struct {
+	id   string
+	row  models.PriceListRow // newest row, for labels
+	obs  []observation
+	name string
+}
+
+// Detect finds the campaign episodes of one brand's snapshots, which must be
+// sorted by date. Episodes still running in the newest snapshot are active.
+func Detect(snapshots []models.VehicleDocument, detectedAt time.Time) []models.Campaign {
+	if len(snapshots) == 0 {
+		return nil
+	}
+	brandID := snapshots[0].BrandID
+	newest := snapshots[len(snapshots)-1].Date
+
+	byID := make(map[string]*series)
+	var order []string
+	for _, snap := range snapshots {
+		seen := make(map[string]bool)
+		for _, row := range snap.Rows {
+			if row.PriceNumeric <= 0 {
+				continue
+			}
+			id := row.VariantID
+			if id == "" {
+				id = variant.ID(brandID, variant.RowKey(row))
+			}
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			s, ok := byID[id]
+			if !ok {
+				s = &series{id: id}
+				byID[id] = s
+				order = append(order, id)
+			}
+			s.row, s.name = row, snap.Brand
+			o := observation{date: snap.Date, price: row.PriceNumeric}
+			if row.PriceListNumeric != nil {
+				o.list = *row.PriceListNumeric
+			}
+			if row.PriceCampaignNumeric != nil {
+				o.campaign = *row.PriceCampaignNumeric
+			}
+			s.obs = append(s.obs, o)
+		}
+	}
+
+	var campaigns []models.Campaign
+	for _, id := range order {
+		s := byID[id]
+		for _, ep := range s.episodes(newest) {
+			campaigns = append(campaigns, s.campaign(brandID, ep, detectedAt))
+		}
+	}
+	return campaigns
+}
+
+// episode is a detected run of discounted observations
+type episode struct {
+	kind      string
+	obs       []observation
+	reference float64 // 0 for listed episodes, whose reference is each list price
+	endedOn   string
+	active    bool
+}
+
+// episodes walks a variant's observations. Listed campaigns take precedence:
+// a listed observation closes any open dip and resets its reference price.
+func (s *series) episodes(newest string) []episode {
+	var out []episode
+	var listed, dip *episode
+	var reference float64
+
+	closeListed := func(endedOn string) {
+		if listed != nil {
+			listed.endedOn = endedOn
+			out = append(out, *listed)
+			listed = nil
+		}
+	}
+	closeDip := func(endedOn string) {
+		if dip != nil && endedOn != "" {
+			dip.endedOn = endedOn
+			out = append(out, *dip)
+		}
+		dip = nil // a dip that never recovers is dropped
+	}
+
+	for i, o := range s.obs {
+		if i > 0 && days(s.obs[i-1].date, o.date) > MaxGapDays {
+			closeListed("")
+			closeDip("")
+			reference = 0
+		}
+		if dip != nil && days(dip.obs[0].date, o.date) > MaxDipDays {
+			closeDip("") // a lasting price cut
+			reference = 0
+		}
+
+		if o.listedDepth() > 0 {
+			closeDip("")
+			reference = 0
+			if listed == nil {
+				listed = &episode{kind: models.CampaignKindListed}
+			}
+			listed.obs = append(listed.obs, o)
+			continue
+		}
+		closeListed(o.date)
+
+		switch {
+		case dip != nil && o.price >= dip.reference*(1-recoveryPercent/100):
+			closeDip(o.date)
+			reference = o.price
+		case dip != nil:
+			dip.obs = append(dip.obs, o)
+		case reference > 0 && o.price <= reference*(1-MinDipPercent/100):
+			dip = &episode{kind: models.CampaignKindDip, obs: []observation{o}, reference: reference}
+		default:
+			reference = o.price
+		}
+	}
+
+	last := s.obs[len(s.obs)-1].date
+	if listed != nil {
+		listed.active = last == newest
+		out = append(out, *listed)
+	}
+	if dip != nil && last == newest {
+		dip.active = true
+		out = append(out, *dip)
+	}
+	return out
+}
+
+// campaign turns an episode into its stored form
+func (s *series) campaign(brandID string, ep episode, detectedAt time.Time) models.Campaign {
+	start, end := ep.obs[0].date, ep.obs[len(ep.obs)-1].date
+	c := models.Campaign{
+		ID:         fmt.Sprintf("%s/%s/%s", s.id, ep.kind, start),
+		BrandID:    brandID,
+		Brand:      s.name,
+		VariantID:  s.id,
+		Model:      s.row.Model,
+		Trim:       s.row.Trim,
+		Engine:     s.row.Engine,
+		Kind:       ep.kind,
+		Start:      start,
+		End:        end,
+		EndedOn:    ep.endedOn,
+		Active:     ep.active,
+		Snapshots:  len(ep.obs),
+		DetectedAt: detectedAt,
+	}
+	if ep.endedOn != "" {
+		c.DurationDays = days(start, ep.endedOn)
+	} else {
+		c.DurationDays = days(start, end) + 1
+	}
+
+	var sum float64
+	for i, o := range ep.obs {
+		price, reference := o.price, ep.reference
+		if ep.kind == models.CampaignKindListed {
+			price, reference = o.campaign, o.list
+		}
+		depth := (reference - price) / reference * 100
+		sum += depth
+		if i == 0 || price < c.LowestPrice {
+			c.LowestPrice = price
+		}
+		c.MaxDepthPercent = max(c.MaxDepthPercent, depth)
+		c.ReferencePrice = reference
+	}
+	c.AvgDepthPercent = market.Round(sum/float64(len(ep.obs)), 1)
+	c.MaxDepthPercent = market.Round(c.MaxDepthPercent, 1)
+	return c
+}
+
+// days returns the number of days from a to b
+func days(a, b string) int {
+	ta, err1 := time.Parse(dateLayout, a)
+	tb, err2 := time.Parse(dateLayout, b)
+	if err1 != nil || err2 != nil {
+		return 0
+	}
+	return int(tb.Sub(ta).Hours() / 24)
+}
+
+// Summarize computes per-brand duration and depth statistics, by brand id
+func Summarize(campaigns []models.Campaign) []models.BrandCampaignStats {
+	byBrand := make(map[string][]models.Campaign)
+	for _, c := range campaigns {
+		byBrand[c.BrandID] = append(byBrand[c.BrandID], c)
+	}
+	stats := make([]models.BrandCampaignStats, 0, len(byBrand))
+	for brandID, cs := range byBrand {
+		st := models.BrandCampaignStats{BrandID: brandID, Campaigns: len(cs)}
+		durations := make([]float64, len(cs))
+		var duration, depth float64
+		for i, c := range cs {
+			if c.Active {
+				st.Active++
+			}
+			durations[i] = float64(c.DurationDays)
+			duration += float64(c.DurationDays)
+			depth += c.AvgDepthPercent
+		}
+		sort.Float64s(durations)
+		n := len(durations)
+		st.MedianDurationDays = (durations[(n-1)/2] + durations[n/2]) / 2
+		st.AvgDurationDays = market.Round(duration/float64(n), 1)
+		st.AvgDepthPercent = market.Round(depth/float64(n), 1)
+		stats = append(stats, st)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].BrandID < stats[j].BrandID })
+	return stats
+}
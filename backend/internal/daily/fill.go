@@ -0,0 +1,116 @@
+// Package daily materializes gap-free daily series from brand snapshots.
+package daily
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"github.com/spehlivan/price-list/backend/internal/variant"
+)
+
+// ForwardFill expands one brand's snapshots (sorted by date) into one
+// document per calendar day from..to. A day with a snapshot holds exactly that
+// snapshot's rows; a day without one carries the previous snapshot's variants
+// forward, for at most maxCarry days (0 carries indefinitely). Variants a
+// snapshot leaves out are therefore never filled back in. Snapshots before
+// from only seed the carried values. Days without any row are omitted.
+func ForwardFill(snapshots []models.VehicleDocument, from, to string, maxCarry int, builtAt time.Time) []models.DailyDocument {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	start, err1 := time.Parse("2006-01-02", from)
+	end, err2 := time.Parse("2006-01-02", to)
+	first, err3 := time.Parse("2006-01-02", snapshots[0].Date)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return nil
+	}
+
+	type observation struct {
+		date time.Time
+		rows []models.PriceListRow
+	}
+	lastSeen := make(map[string]*observation)
+	var order []string          // first-seen order keeps the row order stable
+	var current map[string]bool // variants of the newest snapshot so far
+	brand, collectedAt := snapshots[0].Brand, ""
+
+	var docs []models.DailyDocument
+	next := 0
+	for day := first; !day.After(end); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+
+		var observed map[string]bool
+		for next < len(snapshots) && snapshots[next].Date <= date {
+			snap := snapshots[next]
+			next++
+			if snap.Date != date {
+				continue // not a calendar date
+			}
+			observed = make(map[string]bool)
+			current = observed
+			brand, collectedAt = snap.Brand, snap.CollectedAt
+			for _, row := range snap.Rows {
+				key := variantKey(&row)
+				if !observed[key] {
+					observed[key] = true
+					if _, ok := lastSeen[key]; !ok {
+						order = append(order, key)
+					}
+					lastSeen[key] = &observation{date: day}
+				}
+				lastSeen[key].rows = append(lastSeen[key].rows, row)
+			}
+		}
+		if day.Before(start) {
+			continue
+		}
+
+		doc := models.DailyDocument{
+			BrandID:     snapshots[0].BrandID,
+			Brand:       brand,
+			Date:        date,
+			CollectedAt: collectedAt,
+			Filled:      observed == nil,
+			Rows:        []models.DailyRow{},
+			BuiltAt:     builtAt,
+		}
+		for _, key := range order {
+			if !current[key] {
+				continue
+			}
+			obs := lastSeen[key]
+			age := int(day.Sub(obs.date).Hours() / 24)
+			if maxCarry > 0 && age > maxCarry {
+				continue
+			}
+			filled := !observed[key]
+			for _, row := range obs.rows {
+				doc.Rows = append(doc.Rows, models.DailyRow{
+					PriceListRow: row,
+					Filled:       filled,
+					SourceDate:   obs.date.Format("2006-01-02"),
+				})
+			}
+			if filled {
+				doc.FilledRows += len(obs.rows)
+			}
+		}
+		if len(doc.Rows) == 0 {
+			continue
+		}
+		doc.RowCount = len(doc.Rows)
+		docs = append(docs, doc)
+	}
+	return docs
+}
+
+// variantKey separates model years of the same model, trim and engine
+func variantKey(row *models.PriceListRow) string {
+	key := variant.RowKey(*row)
+	if row.ModelYear != nil {
+		key += fmt.Sprintf("|%v", row.ModelYear)
+	}
+	return key
+}
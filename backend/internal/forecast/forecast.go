@@ -0,0 +1,312 @@
+// Package forecast fits lightweight time-series models to a variant's daily
+// price and forecasts it with prediction intervals. List prices hold for weeks
+// and then jump, so next to a drift model and exponential smoothing there is
+// a step model that forecasts jumps at the rate and size seen in the history.
+// Every model is backtested from rolling origins on the same series.
+package forecast
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/spehlivan/price-list/backend/internal/market"
+	"github.com/spehlivan/price-list/backend/internal/models"
+)
+
+const (
+	// DefaultHorizon is the number of days forecast by default
+	DefaultHorizon = 30
+	// MaxHorizon bounds the horizon
+	MaxHorizon = 180
+	// DefaultLevel is the default interval coverage in percent
+	DefaultLevel = 80
+	// MinHistory is the shortest daily series that is forecast
+	MinHistory = 14
+	// StepPercent is the smallest daily move the step model counts as a jump
+	StepPercent = 0.1
+
+	backtestOrigins = 5
+)
+
+const dateLayout = "2006-01-02"
+
+// zScores are the normal quantiles of the supported interval levels
+var zScores = map[int]float64{80: 1.2816, 90: 1.6449, 95: 1.9600}
+
+// Levels lists the supported interval levels
+var Levels = []int{80, 90, 95}
+
+// ErrShortHistory is returned for series shorter than MinHistory days
+var ErrShortHistory = errors.New("not enough history to forecast")
+
+// Prior is the market-wide repricing behavior the models fall back on when a
+// variant's own history shows less movement. A list price that has not moved
+// yet will still move, so the intervals never collapse to zero width.
+type Prior struct {
+	JumpRate float64 // jumps per day
+	JumpSd   float64 // spread of the log jump size
+}
+
+// DefaultPrior is a variant repricing about every two months by about 3%
+var DefaultPrior = Prior{JumpRate: 1.0 / 60, JumpSd: 0.03}
+
+// dailySd is the prior's daily spread of the log price
+func (p Prior) dailySd() float64 {
+	return math.Sqrt(p.JumpRate) * p.JumpSd
+}
+
+// fit is a model's forecast of the h days after its training series
+type fit struct {
+	mean, lower, upper []float64
+	params             map[string]float64
+	changeProbability  *float64
+}
+
+type fitter func(y []float64, h int, z float64, p Prior) fit
+
+var fitters = []struct {
+	name string
+	fit  fitter
+}{
+	{models.ForecastDrift, drift},
+	{models.ForecastHolt, holt},
+	{models.ForecastStep, step},
+}
+
+// Daily turns trend points into one price per calendar day, carrying each
+// observed price forward over days without a snapshot
+func Daily(points []models.TrendPoint) (dates []string, prices []float64) {
+	for _, p := range points {
+		t, err := time.Parse(dateLayout, p.Date)
+		if err != nil || p.Price <= 0 {
+			continue
+		}
+		if n := len(dates); n > 0 {
+			last, _ := time.Parse(dateLayout, dates[n-1])
+			if !t.After(last) {
+				continue
+			}
+			for d := last.AddDate(0, 0, 1); d.Before(t); d = d.AddDate(0, 0, 1) {
+				dates = append(dates, d.Format(dateLayout))
+				prices = append(prices, prices[n-1])
+			}
+		}
+		dates = append(dates, p.Date)
+		prices = append(prices, p.Price)
+	}
+	return dates, prices
+}
+
+// Forecast fits every model to the points' daily series and forecasts the
+// horizon days after its last date at the given interval level, with the
+// prior as the least movement the models assume
+func Forecast(points []models.TrendPoint, horizon, level int, prior Prior) (models.ForecastResponse, error) {
+	dates, y := Daily(points)
+	if len(y) < MinHistory {
+		return models.ForecastResponse{}, ErrShortHistory
+	}
+	z, ok := zScores[level]
+	if !ok {
+		z, level = zScores[DefaultLevel], DefaultLevel
+	}
+	n := len(y)
+	resp := models.ForecastResponse{
+		From:      dates[0],
+		To:        dates[n-1],
+		Days:      n,
+		LastPrice: y[n-1],
+		Horizon:   horizon,
+		Level:     level,
+		Models:    []models.ForecastModel{},
+	}
+	last, _ := time.Parse(dateLayout, dates[n-1])
+
+	bestMAPE := math.Inf(1)
+	for _, f := range fitters {
+		r := f.fit(y, horizon, z, prior)
+		m := models.ForecastModel{
+			Name:              f.name,
+			Params:            r.params,
+			Points:            make([]models.ForecastPoint, horizon),
+			Backtest:          backtest(f.fit, y, horizon, z, prior),
+			ChangeProbability: r.changeProbability,
+		}
+		for i := range m.Points {
+			m.Points[i] = models.ForecastPoint{
+				Date:  last.AddDate(0, 0, i+1).Format(dateLayout),
+				Price: market.Round(r.mean[i], 2),
+				Lower: market.Round(r.lower[i], 2),
+				Upper: market.Round(r.upper[i], 2),
+			}
+		}
+		if m.Backtest != nil && m.Backtest.MAPE < bestMAPE {
+			bestMAPE, resp.Best = m.Backtest.MAPE, f.name
+		}
+		resp.Models = append(resp.Models, m)
+	}
+	return resp, nil
+}
+
+// backtest forecasts from up to backtestOrigins rolling origins in the last
+// part of the series, each leaving at least MinHistory days to train on
+func backtest(f fitter, y []float64, horizon int, z float64, p Prior) *models.ForecastBacktest {
+	n := len(y)
+	h := min(horizon, n/3)
+	if h < 1 {
+		return nil
+	}
+	stride := max(1, h/2)
+	bt := &models.ForecastBacktest{Horizon: h}
+	var absErr, pctErr float64
+	var count, covered int
+	for k := 0; k < backtestOrigins; k++ {
+		origin := n - h - k*stride
+		if origin < MinHistory {
+			break
+		}
+		r := f(y[:origin], h, z, p)
+		for i := 0; i < h; i++ {
+			actual := y[origin+i]
+			absErr += math.Abs(actual - r.mean[i])
+			pctErr += math.Abs(actual-r.mean[i]) / actual * 100
+			if actual >= r.lower[i]-0.005 && actual <= r.upper[i]+0.005 {
+				covered++
+			}
+			count++
+		}
+		bt.Origins++
+	}
+	if count == 0 {
+		return nil
+	}
+	bt.MAE = market.Round(absErr/float64(count), 2)
+	bt.MAPE = market.Round(pctErr/float64(count), 3)
+	bt.Coverage = market.Round(float64(covered)/float64(count)*100, 1)
+	return bt
+}
+
+// drift extends the average daily change of the series. The interval grows
+// with the spread of the daily changes around it and with the uncertainty of
+// the drift itself, and never falls below the prior's spread.
+func drift(y []float64, h int, z float64, p Prior) fit {
+	n := len(y)
+	slope := (y[n-1] - y[0]) / float64(n-1)
+	var sse float64
+	for t := 1; t < n; t++ {
+		e := y[t] - y[t-1] - slope
+		sse += e * e
+	}
+	sigma := max(math.Sqrt(sse/float64(max(1, n-2))), y[n-1]*p.dailySd())
+	r := newFit(h, map[string]float64{"slope": market.Round(slope, 4), "sigma": market.Round(sigma, 4)})
+	for i := 0; i < h; i++ {
+		steps := float64(i + 1)
+		se := sigma * math.Sqrt(steps*(1+steps/float64(n-1)))
+		r.set(i, y[n-1]+steps*slope, z*se)
+	}
+	return r
+}
+
+// holt is exponential smoothing with a linear trend (Holt's method), with
+// the smoothing parameters chosen by grid search on one-step errors and the
+// error spread floored at the prior's
+func holt(y []float64, h int, z float64, p Prior) fit {
+	bestSSE := math.Inf(1)
+	var alpha, beta, level, trend float64
+	for a := 0.05; a < 1; a += 0.05 {
+		for _, b := range []float64{0, 0.01, 0.05, 0.1, 0.2, 0.3} {
+			l, tr, sse := smooth(y, a, b)
+			if sse < bestSSE {
+				bestSSE, alpha, beta, level, trend = sse, a, b, l, tr
+			}
+		}
+	}
+	sigma := max(math.Sqrt(bestSSE/float64(max(1, len(y)-2))), y[len(y)-1]*p.dailySd())
+	r := newFit(h, map[string]float64{
+		"alpha": market.Round(alpha, 2),
+		"beta":  market.Round(beta, 2),
+		"trend": market.Round(trend, 4),
+		"sigma": market.Round(sigma, 4),
+	})
+	var variance float64 = 1
+	for i := 0; i < h; i++ {
+		if i > 0 {
+			c := alpha * (1 + float64(i)*beta)
+			variance += c * c
+		}
+		r.set(i, level+float64(i+1)*trend, z*sigma*math.Sqrt(variance))
+	}
+	return r
+}
+
+// smooth runs Holt's recursions and returns the final level and trend with
+// the sum of squared one-step errors
+func smooth(y []float64, alpha, beta float64) (level, trend, sse float64) {
+	level = y[0]
+	for t := 1; t < len(y); t++ {
+		e := y[t] - (level + trend)
+		sse += e * e
+		prev := level
+		level = alpha*y[t] + (1-alpha)*(level+trend)
+		trend = beta*(level-prev) + (1-beta)*trend
+	}
+	return level, trend, sse
+}
+
+// step holds the last price and adds jumps arriving at the historical daily
+// rate with the historical mean and spread of the log jump size, a compound
+// Poisson process in log price. The rate and spread are floored at the
+// prior's, so a history without jumps still allows them.
+func step(y []float64, h int, z float64, p Prior) fit {
+	n := len(y)
+	var sizes []float64
+	for t := 1; t < n; t++ {
+		if math.Abs(y[t]-y[t-1])/y[t-1]*100 >= StepPercent {
+			sizes = append(sizes, math.Log(y[t]/y[t-1]))
+		}
+	}
+	rate := float64(len(sizes)) / float64(n-1)
+	var mu, sd float64
+	for _, s := range sizes {
+		mu += s
+	}
+	if len(sizes) > 0 {
+		mu /= float64(len(sizes))
+	}
+	for _, s := range sizes {
+		sd += (s - mu) * (s - mu)
+	}
+	if len(sizes) > 1 {
+		sd = math.Sqrt(sd / float64(len(sizes)-1))
+	}
+	rate, sd = max(rate, p.JumpRate), max(sd, p.JumpSd)
+
+	r := newFit(h, map[string]float64{
+		"jumps":           float64(len(sizes)),
+		"ratePerDay":      market.Round(rate, 4),
+		"meanJumpPercent": market.Round((math.Exp(mu)-1)*100, 2),
+		"jumpSdPercent":   market.Round(sd*100, 2),
+	})
+	for i := 0; i < h; i++ {
+		jumps := rate * float64(i+1)
+		center := math.Log(y[n-1]) + jumps*mu
+		spread := z * math.Sqrt(jumps*(mu*mu+sd*sd))
+		r.mean[i] = math.Exp(center)
+		r.lower[i] = math.Exp(center - spread)
+		r.upper[i] = math.Exp(center + spread)
+	}
+	prob := market.Round((1-math.Exp(-rate*float64(h)))*100, 1)
+	r.changeProbability = &prob
+	return r
+}
+
+func newFit(h int, params map[string]float64) fit {
+	return fit{mean: make([]float64, h), lower: make([]float64, h), upper: make([]float64, h), params: params}
+}
+
+// set stores a point forecast with a symmetric interval, floored at zero
+func (f fit) set(i int, mean, halfWidth float64) {
+	f.mean[i] = mean
+	f.lower[i] = max(0, mean-halfWidth)
+	f.upper[i] = mean + halfWidth
+}
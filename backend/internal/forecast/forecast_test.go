@@ -0,0 +1,209 @@
+package forecast
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/spehlivan/price-list/backend/internal/models"
+)
+
+// series returns one trend point per day from 2026-01-01 with the prices
+func series(prices ...float64) []models.TrendPoint {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	points := make([]models.TrendPoint, len(prices))
+	for i, p := range prices {
+		points[i] = models.TrendPoint{Date: start.AddDate(0, 0, i).Format(dateLayout), Price: p}
+	}
+	return points
+}
+
+func flat(n int, price float64) []float64 {
+	y := make([]float64, n)
+	for i := range y {
+		y[i] = price
+	}
+	return y
+}
+
+// jumps is a price list repriced by pct every period days
+func jumps(n, period int, pct float64) []float64 {
+	y := make([]float64, n)
+	price := 1_000_000.0
+	for i := range y {
+		if i > 0 && i%period == 0 {
+			price *= 1 + pct/100
+		}
+		y[i] = price
+	}
+	return y
+}
+
+func TestDaily(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		points     []models.TrendPoint
+		wantDates  []string
+		wantPrices []float64
+	}{
+		{
+			name:       "consecutive days",
+			points:     series(100, 110),
+			wantDates:  []string{"2026-01-01", "2026-01-02"},
+			wantPrices: []float64{100, 110},
+		},
+		{
+			name:       "gap carried forward",
+			points:     []models.TrendPoint{{Date: "2026-01-01", Price: 100}, {Date: "2026-01-04", Price: 120}},
+			wantDates:  []string{"2026-01-01", "2026-01-02", "2026-01-03", "2026-01-04"},
+			wantPrices: []float64{100, 100, 100, 120},
+		},
+		{
+			name: "unpriced, malformed and out-of-order points skipped",
+			points: []models.TrendPoint{
+				{Date: "2026-01-02", Price: 100},
+				{Date: "2026-01-01", Price: 90},
+				{Date: "2026-01-03", Price: 0},
+				{Date: "not a date", Price: 95},
+				{Date: "2026-01-03", Price: 105},
+			},
+			wantDates:  []string{"2026-01-02", "2026-01-03"},
+			wantPrices: []float64{100, 105},
+		},
+		{
+			name: "empty",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dates, prices := Daily(tc.points)
+			if !slices.Equal(dates, tc.wantDates) || !slices.Equal(prices, tc.wantPrices) {
+				t.Errorf("got %v %v, want %v %v", dates, prices, tc.wantDates, tc.wantPrices)
+			}
+		})
+	}
+}
+
+func TestForecast(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		y       []float64
+		horizon int
+		level   int
+		wantErr error
+	}{
+		{name: "short history", y: flat(MinHistory-1, 100), horizon: 30, level: 80, wantErr: ErrShortHistory},
+		{name: "flat history", y: flat(120, 1_000_000), horizon: 30, level: 80},
+		{name: "monthly jumps", y: jumps(180, 30, 4), horizon: 60, level: 95},
+		{name: "unsupported level falls back", y: jumps(90, 20, -2), horizon: 10, level: 85},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := Forecast(series(tc.y...), tc.horizon, tc.level, DefaultPrior)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if _, ok := zScores[resp.Level]; !ok {
+				t.Errorf("level %d is not a supported level", resp.Level)
+			}
+			if resp.Days != len(tc.y) || resp.LastPrice != tc.y[len(tc.y)-1] {
+				t.Errorf("got %d days ending at %v, want %d ending at %v", resp.Days, resp.LastPrice, len(tc.y), tc.y[len(tc.y)-1])
+			}
+			if len(resp.Models) != len(fitters) {
+				t.Fatalf("got %d models, want %d", len(resp.Models), len(fitters))
+			}
+			for _, m := range resp.Models {
+				if len(m.Points) != tc.horizon {
+					t.Errorf("%s: got %d points, want %d", m.Name, len(m.Points), tc.horizon)
+				}
+				for i, p := range m.Points {
+					if !(p.Lower < p.Price && p.Price < p.Upper) {
+						t.Errorf("%s: day %d interval [%v, %v] does not strictly contain %v", m.Name, i+1, p.Lower, p.Upper, p.Price)
+						break
+					}
+				}
+				if m.Backtest == nil {
+					t.Errorf("%s: missing backtest", m.Name)
+				}
+			}
+			if resp.Best == "" {
+				t.Error("no best model chosen")
+			}
+		})
+	}
+}
+
+// TestForecastFlatHistoryKeepsUncertainty checks the prior: a price that
+// never moved still gets widening intervals and a chance to change
+func TestForecastFlatHistoryKeepsUncertainty(t *testing.T) {
+	resp, err := Forecast(series(flat(120, 1_000_000)...), 180, 80, DefaultPrior)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range resp.Models {
+		first, last := m.Points[0], m.Points[len(m.Points)-1]
+		if first.Upper-first.Lower <= 0 || last.Upper-last.Lower <= first.Upper-first.Lower {
+			t.Errorf("%s: interval width %v on day 1 and %v on day 180, want positive and widening",
+				m.Name, first.Upper-first.Lower, last.Upper-last.Lower)
+		}
+		if m.Name == models.ForecastStep && (m.ChangeProbability == nil || *m.ChangeProbability <= 0) {
+			t.Errorf("step: change probability %v, want positive", m.ChangeProbability)
+		}
+	}
+}
+
+func TestBacktest(t *testing.T) {
+	// exact forecasts the true series with a zero-width interval
+	truth := jumps(100, 10, 1)
+	exact := func(y []float64, h int, z float64, p Prior) fit {
+		r := newFit(h, nil)
+		for i := 0; i < h; i++ {
+			r.set(i, truth[len(y)+i], 0)
+		}
+		return r
+	}
+	// off is 10% too high with an interval that misses
+	off := func(y []float64, h int, z float64, p Prior) fit {
+		r := newFit(h, nil)
+		for i := 0; i < h; i++ {
+			r.set(i, truth[len(y)+i]*1.1, truth[len(y)+i]*0.05)
+		}
+		return r
+	}
+
+	for _, tc := range []struct {
+		name         string
+		f            fitter
+		y            []float64
+		horizon      int
+		want         *models.ForecastBacktest
+		wantMAPE     float64
+		wantCoverage float64
+	}{
+		{name: "too short for an origin", f: exact, y: truth[:MinHistory+2], horizon: 30},
+		{name: "exact", f: exact, y: truth, horizon: 30, want: &models.ForecastBacktest{Origins: 4, Horizon: 30}, wantMAPE: 0, wantCoverage: 100},
+		{name: "biased", f: off, y: truth, horizon: 10, want: &models.ForecastBacktest{Origins: 5, Horizon: 10}, wantMAPE: 10, wantCoverage: 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			bt := backtest(tc.f, tc.y, tc.horizon, zScores[DefaultLevel], DefaultPrior)
+			if tc.want == nil {
+				if bt != nil {
+					t.Fatalf("got %+v, want no backtest", bt)
+				}
+				return
+			}
+			if bt == nil {
+				t.Fatal("got no backtest")
+			}
+			if bt.Origins != tc.want.Origins || bt.Horizon != tc.want.Horizon {
+				t.Errorf("got %d origins of %d days, want %d of %d", bt.Origins, bt.Horizon, tc.want.Origins, tc.want.Horizon)
+			}
+			if math.Abs(bt.MAPE-tc.wantMAPE) > 0.01 || bt.Coverage != tc.wantCoverage {
+				t.Errorf("got MAPE %v coverage %v, want %v and %v", bt.MAPE, bt.Coverage, tc.wantMAPE, tc.wantCoverage)
+			}
+		})
+	}
+}
@@ -0,0 +1,346 @@
+// Package gaps generalizes the gaps generator's heatmap: vehicles are counted
+// in cells spanned by user-chosen dimensions and price band edges, and cells
+// holding fewer than GapBelow vehicles are scored as opportunities by how
+// popular each of their coordinates is in the market.
+package gaps
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spehlivan/price-list/backend/internal/market"
+	"github.com/spehlivan/price-list/backend/internal/models"
+)
+
+// GapBelow is the vehicle count under which a cell is a gap, as in the generator
+const GapBelow = 2
+
+// MaxDimensions bounds the dimensions of one analysis
+const MaxDimensions = 4
+
+// maxCells bounds the heatmap size
+const maxCells = 50_000
+
+// topOpportunities is the length of the top opportunities list
+const topOpportunities = 20
+
+// Defaults: the generator's price ranges and its segment × fuel × transmission
+// cells; EV range buckets in km
+var (
+	DefaultDimensions = []string{models.GapDimSegment, models.GapDimFuel, models.GapDimTransmission}
+	DefaultPriceEdges = []float64{0, 500_000, 1_000_000, 1_500_000, 2_000_000, 3_000_000, 5_000_000}
+	DefaultRangeEdges = []float64{0, 300, 400, 500, 600}
+)
+
+// Config selects the heatmap's dimensions and band edges. Bands run from each
+// edge to the next; the last one is open.
+type Config struct {
+	Dimensions []string
+	PriceEdges []float64
+	RangeEdges []float64 // EV range buckets, used with the evRange dimension
+}
+
+// DefaultConfig mirrors the generator's heatmap
+func DefaultConfig() Config {
+	return Config{Dimensions: DefaultDimensions, PriceEdges: DefaultPriceEdges, RangeEdges: DefaultRangeEdges}
+}
+
+// ErrTooManyCells is returned when the dimensions span too large a heatmap
+var ErrTooManyCells = fmt.Errorf("gaps: the heatmap would exceed %d cells", maxCells)
+
+// ParseDimensions parses a comma-separated list of distinct dimensions
+func ParseDimensions(s string) ([]string, error) {
+	var dims []string
+	for _, d := range strings.Split(s, ",") {
+		d = strings.TrimSpace(d)
+		if !slices.Contains(models.GapDimensions, d) {
+			return nil, fmt.Errorf("unknown dimension %q; use %s", d, strings.Join(models.GapDimensions, ", "))
+		}
+		if slices.Contains(dims, d) {
+			return nil, fmt.Errorf("dimension %q given twice", d)
+		}
+		dims = append(dims, d)
+	}
+	if len(dims) > MaxDimensions {
+		return nil, fmt.Errorf("at most %d dimensions", MaxDimensions)
+	}
+	return dims, nil
+}
+
+// ParseEdges parses comma-separated, strictly ascending, non-negative band edges
+func ParseEdges(s string) ([]float64, error) {
+	var edges []float64
+	for _, part := range strings.Split(s, ",") {
+		e, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || e < 0 || math.IsInf(e, 0) {
+			return nil, errors.New("edges must be non-negative numbers")
+		}
+		if len(edges) > 0 && e <= edges[len(edges)-1] {
+			return nil, errors.New("edges must be strictly ascending")
+		}
+		edges = append(edges, e)
+	}
+	return edges, nil
+}
+
+// bands turns edges into labelled bands
+func bands(edges []float64, unit func(float64) string, suffix string) []models.PriceRangeInfo {
+	out := make([]models.PriceRangeInfo, len(edges))
+	for i, e := range edges {
+		label := unit(e) + "+" + suffix
+		var upper float64
+		if i+1 < len(edges) {
+			upper = edges[i+1]
+			label = unit(e) + "-" + unit(upper) + suffix
+		}
+		out[i] = models.PriceRangeInfo{Label: label, Min: e, Max: upper}
+	}
+	return out
+}
+
+// find returns the band holding v, false below the first edge
+func find(bs []models.PriceRangeInfo, v float64) (models.PriceRangeInfo, bool) {
+	for i := len(bs) - 1; i >= 0; i-- {
+		if v >= bs[i].Min {
+			return bs[i], true
+		}
+	}
+	return models.PriceRangeInfo{}, false
+}
+
+// amount formats a price edge the way the generator labels its ranges (500K, 1.5M)
+func amount(v float64) string {
+	switch {
+	case v >= 1_000_000:
+		return strconv.FormatFloat(v/1_000_000, 'f', -1, 64) + "M"
+	case v >= 1_000:
+		return strconv.FormatFloat(v/1_000, 'f', -1, 64) + "K"
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func km(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// value returns a vehicle's value along a dimension, false when unknown
+func value(v market.Vehicle, dim string, ranges []models.PriceRangeInfo) (string, bool) {
+	row := v.Row
+	var s string
+	switch dim {
+	case models.GapDimSegment:
+		s = v.Segment().Code
+	case models.GapDimSize:
+		s = v.Segment().Size
+	case models.GapDimBody:
+		s = v.Segment().Body
+	case models.GapDimFuel:
+		s = v.Fuel()
+	case models.GapDimTransmission:
+		s = row.Canonical.Transmission
+	case models.GapDimDriveType:
+		s = row.Canonical.DriveType
+	case models.GapDimSeating:
+		if row.SeatingCapacity != nil {
+			s = seats(*row.SeatingCapacity)
+		}
+	case models.GapDimEVRange:
+		if v.IsElectric() && row.WltpRange != nil {
+			if b, ok := find(ranges, *row.WltpRange); ok {
+				s = b.Label
+			}
+		}
+	}
+	return s, s != ""
+}
+
+// seats totals a seating capacity such as "7" or "8+1", empty when unparsable
+func seats(raw string) string {
+	total := 0
+	for _, part := range strings.Split(raw, "+") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n <= 0 {
+			return ""
+		}
+		total += n
+	}
+	return strconv.Itoa(total)
+}
+
+// Analyze builds the heatmap of vehicles under cfg
+func Analyze(vehicles []market.Vehicle, cfg Config) (models.GapAnalysis, error) {
+	priceBands := bands(cfg.PriceEdges, amount, "")
+	var rangeBands []models.PriceRangeInfo
+	if slices.Contains(cfg.Dimensions, models.GapDimEVRange) {
+		rangeBands = bands(cfg.RangeEdges, km, " km")
+	}
+	out := models.GapAnalysis{
+		Dimensions:       cfg.Dimensions,
+		PriceRanges:      priceBands,
+		RangeBands:       rangeBands,
+		Cells:            []models.GapAnalysisCell{},
+		TopOpportunities: []models.GapAnalysisCell{},
+	}
+
+	// Place vehicles in cells and count how often each coordinate occurs
+	members := make(map[string][]market.Vehicle)
+	seen := make([]map[string]int, len(cfg.Dimensions))
+	for i := range seen {
+		seen[i] = make(map[string]int)
+	}
+	bandCount := make(map[string]int)
+	for _, v := range vehicles {
+		values, ok := coordinates(v, cfg.Dimensions, rangeBands)
+		b, inBand := find(priceBands, v.Price())
+		if !ok || !inBand {
+			out.Excluded++
+			continue
+		}
+		for i, s := range values {
+			seen[i][s]++
+		}
+		bandCount[b.Label]++
+		key := cellKey(values, b.Label)
+		members[key] = append(members[key], v)
+		out.Vehicles++
+	}
+
+	axes := make([][]string, len(cfg.Dimensions))
+	cells := len(priceBands)
+	for i, counts := range seen {
+		for s := range counts {
+			axes[i] = append(axes[i], s)
+		}
+		sort.Strings(axes[i])
+		cells *= max(len(axes[i]), 1)
+	}
+	if cells > maxCells {
+		return out, ErrTooManyCells
+	}
+	if out.Vehicles == 0 {
+		return out, nil
+	}
+
+	var scoreSum float64
+	var scored int
+	forEachCombination(axes, func(values []string) {
+		for _, b := range priceBands {
+			vs := members[cellKey(values, b.Label)]
+			cell := models.GapAnalysisCell{
+				Values:        make(map[string]string, len(values)),
+				PriceRange:    b.Label,
+				PriceRangeMin: b.Min,
+				PriceRangeMax: b.Max,
+				VehicleCount:  len(vs),
+				Brands:        brands(vs),
+				HasGap:        len(vs) < GapBelow,
+			}
+			for i, dim := range cfg.Dimensions {
+				cell.Values[dim] = values[i]
+			}
+			if len(vs) > 0 {
+				var sum float64
+				for _, v := range vs {
+					sum += v.Price()
+				}
+				cell.AvgPrice = math.Round(sum / float64(len(vs)))
+			}
+			if cell.HasGap {
+				total := popularity(bandCount, b.Label)
+				for i, s := range values {
+					total += popularity(seen[i], s)
+				}
+				cell.OpportunityScore = market.Round(total/float64(len(values)+1)*100, 2)
+				out.Summary.TotalGaps++
+			}
+			if cell.OpportunityScore > 0 {
+				scoreSum += cell.OpportunityScore
+				scored++
+			}
+			out.Cells = append(out.Cells, cell)
+		}
+	})
+	out.Summary.TotalCells = len(out.Cells)
+	if scored > 0 {
+		out.Summary.AvgOpportunityScore = market.Round(scoreSum/float64(scored), 2)
+	}
+
+	for _, cell := range out.Cells {
+		if cell.HasGap && cell.OpportunityScore > 0 {
+			out.TopOpportunities = append(out.TopOpportunities, cell)
+		}
+	}
+	sort.SliceStable(out.TopOpportunities, func(i, j int) bool {
+		return out.TopOpportunities[i].OpportunityScore > out.TopOpportunities[j].OpportunityScore
+	})
+	out.TopOpportunities = out.TopOpportunities[:min(topOpportunities, len(out.TopOpportunities))]
+	return out, nil
+}
+
+// coordinates returns a vehicle's values along dims, false when one is unknown
+func coordinates(v market.Vehicle, dims []string, ranges []models.PriceRangeInfo) ([]string, bool) {
+	values := make([]string, len(dims))
+	for i, dim := range dims {
+		s, ok := value(v, dim, ranges)
+		if !ok {
+			return nil, false
+		}
+		values[i] = s
+	}
+	return values, true
+}
+
+// popularity is a coordinate's count relative to the most common value of its
+// dimension, so every dimension contributes 0–1 to an opportunity score
+func popularity(counts map[string]int, s string) float64 {
+	top := 0
+	for _, n := range counts {
+		top = max(top, n)
+	}
+	if top == 0 {
+		return 0
+	}
+	return float64(counts[s]) / float64(top)
+}
+
+func cellKey(values []string, band string) string {
+	return strings.Join(values, "\x00") + "\x00" + band
+}
+
+// forEachCombination calls fn with every combination of one value per axis
+func forEachCombination(axes [][]string, fn func([]string)) {
+	values := make([]string, len(axes))
+	var walk func(int)
+	walk = func(i int) {
+		if i == len(axes) {
+			fn(values)
+			return
+		}
+		for _, s := range axes[i] {
+			values[i] = s
+			walk(i + 1)
+		}
+	}
+	walk(0)
+}
+
+// brands returns the sorted distinct brand names of vehicles
+func brands(vehicles []market.Vehicle) []string {
+	names := []string{}
+	for _, v := range vehicles {
+		name := v.Row.Brand
+		if name == "" {
+			name = v.BrandID
+		}
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
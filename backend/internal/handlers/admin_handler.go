@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"github.com/spehlivan/price-list/backend/internal/repository"
+)
+
+type AdminHandler struct {
+	keys       *repository.APIKeyRepository
+	quarantine *repository.QuarantineRepository
+}
+
+func NewAdminHandler(keys *repository.APIKeyRepository, quarantine *repository.QuarantineRepository) *AdminHandler {
+	return &AdminHandler{keys: keys, quarantine: quarantine}
+}
+
+// ListKeys returns all API keys with today's usage
+func (h *AdminHandler) ListKeys(c *gin.Context) {
+	ctx := c.Request.Context()
+	keys, err := h.keys.List(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch API keys"})
+		return
+	}
+
+	today := time.Now().UTC().Format("2006-01-02")
+	usage, err := h.keys.GetUsage(ctx, "", today, today)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch API key usage"})
+		return
+	}
+	usageByKey := make(map[string]int64, len(usage))
+	for _, u := range usage {
+		usageByKey[u.KeyID] = u.Count
+	}
+
+	summaries := make([]models.APIKeySummary, 0, len(keys))
+	for _, key := range keys {
+		summaries = append(summaries, models.APIKeySummary{
+			APIKey:     key,
+			UsageToday: usageByKey[key.KeyID],
+		})
+	}
+	c.JSON(http.StatusOK, models.APIKeyListResponse{Keys: summaries})
+}
+
+// GetUsage returns daily request counters per key. Defaults to the last 30 days.
+func (h *AdminHandler) GetUsage(c *gin.Context) {
+	now := time.Now().UTC()
+	from := c.DefaultQuery("from", now.AddDate(0, 0, -29).Format("2006-01-02"))
+	to := c.DefaultQuery("to", now.Format("2006-01-02"))
+
+	if !isDate(from) || !isDate(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from and to must be dates in YYYY-MM-DD format"})
+		return
+	}
+
+	usage, err := h.keys.GetUsage(c.Request.Context(), c.Query("key"), from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch API key usage"})
+		return
+	}
+	c.JSON(http.StatusOK, models.APIKeyUsageResponse{From: from, To: to, Usage: usage})
+}
+
+// GetQuarantine returns data files rejected at import time with their reasons
+func (h *AdminHandler) GetQuarantine(c *gin.Context) {
+	limit := 100
+	if l := c.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 {
+			limit = min(parsed, 1000)
+		}
+	}
+
+	data, err := h.quarantine.List(c.Request.Context(), c.Query("brand"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch quarantine"})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+// isDate reports whether s is a YYYY-MM-DD date
+func isDate(s string) bool {
+	_, err := time.Parse("2006-01-02", s)
+	return err == nil
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"github.com/spehlivan/price-list/backend/internal/repository"
+)
+
+type AnomalyHandler struct {
+	repo *repository.AnomalyRepository
+}
+
+func NewAnomalyHandler(repo *repository.AnomalyRepository) *AnomalyHandler {
+	return &AnomalyHandler{repo: repo}
+}
+
+// GetAnomalies returns the day-over-day anomaly guard verdicts of imported snapshots
+func (h *AnomalyHandler) GetAnomalies(c *gin.Context) {
+	from, to := c.Query("from"), c.Query("to")
+	if (from != "" && !isDate(from)) || (to != "" && !isDate(to)) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from and to must be dates in YYYY-MM-DD format"})
+		return
+	}
+
+	verdict := c.Query("verdict")
+	if verdict != "" && verdict != models.VerdictFlagged && verdict != models.VerdictRejected {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "verdict must be flagged or rejected"})
+		return
+	}
+
+	limit := 100
+	if l := c.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 {
+			limit = min(parsed, 1000)
+		}
+	}
+
+	data, err := h.repo.List(c.Request.Context(), c.Query("brand"), verdict, from, to, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch anomalies"})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spehlivan/price-list/backend/internal/cache"
+	"github.com/spehlivan/price-list/backend/internal/middleware"
+)
+
+// serveCached writes compute's result as JSON, memoized per data version.
+// Responses carry a strong ETag and Last-Modified derived from the data, and
+// matching conditional requests are answered with 304 without touching the
+// database. Responses to keyed requests are private, so shared caches never
+// hand them to clients without the key. compute errors are returned unhandled
+// so callers keep their own error responses.
+func serveCached(c *gin.Context, rc *cache.Cache, compute func() (any, error)) error {
+	v, err := rc.Version(c.Request.Context())
+	if err != nil {
+		log.Printf("Cache version probe failed: %v", err)
+		data, err := compute()
+		if err != nil {
+			return err
+		}
+		c.JSON(http.StatusOK, data)
+		return nil
+	}
+
+	// Relative queries (e.g. trend ?days=) shift with the calendar, so the
+	// current day is part of the key
+	key := c.Request.URL.Path + "?" + c.Request.URL.Query().Encode() + "@" + time.Now().UTC().Format("2006-01-02")
+	etag := v.ETag(key)
+
+	c.Header("ETag", etag)
+	if middleware.KeyFromContext(c) != nil {
+		c.Header("Cache-Control", "private, max-age=60")
+	} else {
+		c.Header("Cache-Control", "public, max-age=60")
+	}
+	c.Header("Vary", "X-API-Key, Authorization")
+	if !v.LastModified.IsZero() {
+		c.Header("Last-Modified", v.LastModified.UTC().Format(http.TimeFormat))
+	}
+	if notModified(c.Request, etag, v.LastModified) {
+		c.Status(http.StatusNotModified)
+		return nil
+	}
+
+	body, ok := rc.Get(v, key)
+	if !ok {
+		data, err := compute()
+		if err != nil {
+			return err
+		}
+		if body, err = json.Marshal(data); err != nil {
+			return err
+		}
+		rc.Set(v, key, body)
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+	return nil
+}
+
+// notModified evaluates If-None-Match, falling back to If-Modified-Since
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == etag || candidate == "*" {
+				return true
+			}
+		}
+		return false
+	}
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		if t, err := http.ParseTime(ims); err == nil {
+			return !lastModified.Truncate(time.Second).After(t)
+		}
+	}
+	return false
+}
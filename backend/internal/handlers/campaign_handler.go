@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"github.com/spehlivan/price-list/backend/internal/repository"
+)
+
+type CampaignHandler struct {
+	repo *repository.CampaignRepository
+}
+
+func NewCampaignHandler(repo *repository.CampaignRepository) *CampaignHandler {
+	return &CampaignHandler{repo: repo}
+}
+
+// GetCampaigns returns detected campaign episodes with each brand's typical
+// campaign duration and depth
+func (h *CampaignHandler) GetCampaigns(c *gin.Context) {
+	q := repository.CampaignQuery{BrandID: c.Query("brand"), Kind: c.Query("kind")}
+	if q.Kind != "" && q.Kind != models.CampaignKindListed && q.Kind != models.CampaignKindDip {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "kind must be listed or dip"})
+		return
+	}
+	if a := c.Query("active"); a != "" {
+		active, err := strconv.ParseBool(a)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "active must be true or false"})
+			return
+		}
+		q.Active = &active
+	}
+	limit, ok := positiveIntQuery(c, "limit", 100)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+		return
+	}
+	q.Limit = min(limit, 1000)
+
+	data, err := h.repo.List(c.Request.Context(), q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch campaigns"})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spehlivan/price-list/backend/internal/repository"
+)
+
+// maxErrorPage bounds page so the skip stays far from overflowing
+const maxErrorPage = 10000
+
+type ErrorHandler struct {
+	repo *repository.ErrorLogRepository
+}
+
+func NewErrorHandler(repo *repository.ErrorLogRepository) *ErrorHandler {
+	return &ErrorHandler{repo: repo}
+}
+
+// GetErrors returns a page of the collector error log with counts over time.
+// from and to are inclusive dates and default to the last 7 days.
+func (h *ErrorHandler) GetErrors(c *gin.Context) {
+	now := time.Now().UTC()
+	from := c.DefaultQuery("from", now.AddDate(0, 0, -6).Format("2006-01-02"))
+	to := c.DefaultQuery("to", now.Format("2006-01-02"))
+
+	start, err1 := time.Parse("2006-01-02", from)
+	end, err2 := time.Parse("2006-01-02", to)
+	if err1 != nil || err2 != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from and to must be dates in YYYY-MM-DD format"})
+		return
+	}
+
+	page, ok := positiveIntQuery(c, "page", 1)
+	if !ok || page > maxErrorPage {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "page must be an integer between 1 and " + strconv.Itoa(maxErrorPage)})
+		return
+	}
+	pageSize, ok := positiveIntQuery(c, "pageSize", 100)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "pageSize must be a positive integer"})
+		return
+	}
+
+	data, err := h.repo.Query(c.Request.Context(), repository.ErrorLogQuery{
+		Category: c.Query("category"),
+		Severity: c.Query("severity"),
+		Source:   c.Query("source"),
+		BrandID:  c.Query("brand"),
+		From:     start,
+		To:       end.AddDate(0, 0, 1),
+		Page:     page,
+		PageSize: min(pageSize, 500),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch errors data"})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
+
+// positiveIntQuery parses an optional positive integer query parameter
+func positiveIntQuery(c *gin.Context, name string, fallback int) (int, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return fallback, true
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed <= 0 {
+		return 0, false
+	}
+	return parsed, true
+}
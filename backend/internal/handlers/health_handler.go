@@ -2,14 +2,19 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/spehlivan/price-list/backend/internal/repository"
 )
 
-type HealthHandler struct{}
+type HealthHandler struct {
+	collection *repository.CollectionHealthRepository
+}
 
-func NewHealthHandler() *HealthHandler {
-	return &HealthHandler{}
+func NewHealthHandler(collection *repository.CollectionHealthRepository) *HealthHandler {
+	return &HealthHandler{collection: collection}
 }
 
 func (h *HealthHandler) Health(c *gin.Context) {
@@ -17,3 +22,33 @@ func (h *HealthHandler) Health(c *gin.Context) {
 		"status": "ok",
 	})
 }
+
+// CollectionHealth returns per-brand collector health over a window of days
+// ending at `to` (default today). Defaults to the last 30 days.
+func (h *HealthHandler) CollectionHealth(c *gin.Context) {
+	to := c.DefaultQuery("to", time.Now().UTC().Format("2006-01-02"))
+	if !isDate(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must be a date in YYYY-MM-DD format"})
+		return
+	}
+
+	days := 30
+	if d := c.Query("days"); d != "" {
+		parsed, err := strconv.Atoi(d)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "days must be a positive integer"})
+			return
+		}
+		days = min(parsed, 365)
+	}
+
+	end, _ := time.Parse("2006-01-02", to)
+	from := end.AddDate(0, 0, -(days - 1)).Format("2006-01-02")
+
+	data, err := h.collection.Summarize(c.Request.Context(), from, to, c.Query("brand"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch collection health"})
+		return
+	}
+	c.JSON(http.StatusOK, data)
+}
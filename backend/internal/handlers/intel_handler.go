@@ -17,7 +17,9 @@ func NewIntelHandler(repo *repository.IntelRepository) *IntelHandler {
 	return &IntelHandler{repo: repo}
 }
 
-// respondWithData handles common error/success response for intel endpoints
+// respondWithData handles common error/success response for intel endpoints.
+// Vehicle items are classified by segment (see segmentItems) and ?segment=
+// restricts them.
 func respondWithData(c *gin.Context, data any, err error, label string) {
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
@@ -30,15 +32,30 @@ func respondWithData(c *gin.Context, data any, err error, label string) {
 	c.JSON(http.StatusOK, data)
 }
 
-// GetEvents returns the latest price change events
+// GetEvents returns the latest price change events with their segments
 func (h *IntelHandler) GetEvents(c *gin.Context) {
+	segments, ok := segmentFilter(c)
+	if !ok {
+		return
+	}
 	data, err := h.repo.GetEvents(c.Request.Context())
+	if err == nil {
+		segmentItems(data, segments, "events", "bigMoves.topIncreases", "bigMoves.topDecreases")
+	}
 	respondWithData(c, data, err, "events")
 }
 
-// GetArchitecture returns the latest trim ladder / architecture data
+// GetArchitecture returns the latest trim ladder / architecture data; ladders
+// carry their segment
 func (h *IntelHandler) GetArchitecture(c *gin.Context) {
+	segments, ok := segmentFilter(c)
+	if !ok {
+		return
+	}
 	data, err := h.repo.GetArchitecture(c.Request.Context())
+	if err == nil {
+		segmentItems(data, segments, "ladders")
+	}
 	respondWithData(c, data, err, "architecture")
 }
 
@@ -48,26 +65,41 @@ func (h *IntelHandler) GetGaps(c *gin.Context) {
 	respondWithData(c, data, err, "gaps")
 }
 
-// GetPromos returns the latest price drops / promotions data
+// GetPromos returns the latest price drops / promotions data with segments
 func (h *IntelHandler) GetPromos(c *gin.Context) {
+	segments, ok := segmentFilter(c)
+	if !ok {
+		return
+	}
 	data, err := h.repo.GetPromos(c.Request.Context())
+	if err == nil {
+		segmentItems(data, segments, "priceDrops", "recentDrops")
+	}
 	respondWithData(c, data, err, "promos")
 }
 
-// GetLifecycle returns the latest model lifecycle data
+// GetLifecycle returns the latest model lifecycle data with segments
 func (h *IntelHandler) GetLifecycle(c *gin.Context) {
+	segments, ok := segmentFilter(c)
+	if !ok {
+		return
+	}
 	data, err := h.repo.GetLifecycle(c.Request.Context())
+	if err == nil {
+		segmentItems(data, segments, "modelYearTransitions", "entryPriceDeltas", "staleModels", "allModels")
+	}
 	respondWithData(c, data, err, "lifecycle")
 }
 
-// GetErrors returns the latest error log
-func (h *IntelHandler) GetErrors(c *gin.Context) {
-	data, err := h.repo.GetErrors(c.Request.Context())
-	respondWithData(c, data, err, "errors")
-}
-
-// GetInsights returns the latest deal scores and outlier data
+// GetInsights returns the latest deal scores and outlier data with segments
 func (h *IntelHandler) GetInsights(c *gin.Context) {
+	segments, ok := segmentFilter(c)
+	if !ok {
+		return
+	}
 	data, err := h.repo.GetInsights(c.Request.Context())
+	if err == nil {
+		segmentItems(data, segments, "topDeals", "cheapOutliers", "expensiveOutliers", "allVehicles")
+	}
 	respondWithData(c, data, err, "insights")
 }
@@ -0,0 +1,406 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spehlivan/price-list/backend/internal/architecture"
+	"github.com/spehlivan/price-list/backend/internal/cache"
+	"github.com/spehlivan/price-list/backend/internal/gaps"
+	"github.com/spehlivan/price-list/backend/internal/market"
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"github.com/spehlivan/price-list/backend/internal/reaction"
+	"github.com/spehlivan/price-list/backend/internal/repository"
+	"github.com/spehlivan/price-list/backend/internal/scoring"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// MarketHandler serves analyses computed live over the stored snapshots,
+// as opposed to the precomputed intel documents
+type MarketHandler struct {
+	vehicles   *repository.VehicleRepository
+	vocabulary *repository.VocabularyRepository
+	cache      *cache.Cache
+}
+
+func NewMarketHandler(vehicles *repository.VehicleRepository, vocabulary *repository.VocabularyRepository, rc *cache.Cache) *MarketHandler {
+	return &MarketHandler{vehicles: vehicles, vocabulary: vocabulary, cache: rc}
+}
+
+// loadMarket returns every brand's priced vehicles as of date (newest when
+// empty) and the newest snapshot date among them
+func (h *MarketHandler) loadMarket(ctx context.Context, date string) ([]market.Vehicle, string, error) {
+	docs, err := h.vehicles.GetMarket(ctx, date)
+	if err != nil {
+		return nil, "", err
+	}
+	table, err := h.vocabulary.Table(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	newest := ""
+	for _, doc := range docs {
+		newest = max(newest, doc.Date)
+	}
+	return market.FromSnapshots(docs, table), newest, nil
+}
+
+// GetScore recomputes deal scores for the market on a date. With vehicleId
+// (a variant id) it returns that vehicle and its peer group, otherwise the
+// top deals and outliers of the selected segments. Peer groups and component
+// weights are selectable so alternative scoring models can be compared.
+func (h *MarketHandler) GetScore(c *gin.Context) {
+	date := c.Query("date")
+	if date != "" && !isDate(date) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "date must be in YYYY-MM-DD format"})
+		return
+	}
+	cfg := scoring.DefaultConfig()
+	if g := c.Query("peerGroup"); g != "" {
+		if !scoring.ValidPeerGroup(g) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "peerGroup must be one of " + strings.Join(models.PeerGroups, ", ")})
+			return
+		}
+		cfg.PeerGroup = g
+	}
+	for _, p := range []struct {
+		name   string
+		target *float64
+	}{
+		{"campaignWeight", &cfg.Weights.CampaignDiscount},
+		{"hpWeight", &cfg.Weights.TLPerHP},
+		{"rangeWeight", &cfg.Weights.TLPerKm},
+	} {
+		w, ok := weightQuery(c, p.name)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": p.name + " must be a number between 0 and 10"})
+			return
+		}
+		*p.target = w
+	}
+	segments, ok := segmentFilter(c)
+	if !ok {
+		return
+	}
+	vehicleID := c.Query("vehicleId")
+
+	err := serveCached(c, h.cache, func() (any, error) {
+		vehicles, newest, err := h.loadMarket(c.Request.Context(), date)
+		if err != nil {
+			return nil, err
+		}
+		scored := scoring.Score(vehicles, cfg)
+		resp := models.ScoreResponse{Date: newest, PeerGroup: cfg.PeerGroup, Weights: cfg.Weights}
+
+		if vehicleID != "" {
+			for i := range scored {
+				if scored[i].ID == vehicleID {
+					resp.Vehicle = &scored[i]
+					break
+				}
+			}
+			if resp.Vehicle == nil {
+				return nil, mongo.ErrNoDocuments
+			}
+			resp.Peers = scoring.Peers(scored, resp.Vehicle.PeerKey)
+			return resp, nil
+		}
+
+		var kept []models.VehicleWithScore
+		for _, v := range scored {
+			if segments.Match(*v.Segment) {
+				kept = append(kept, v)
+			}
+		}
+		summary := scoring.Summarize(kept)
+		resp.TopDeals = summary.TopDeals
+		resp.CheapOutliers = summary.CheapOutliers
+		resp.ExpensiveOutliers = summary.ExpensiveOutliers
+		resp.AllVehicles = kept
+		return resp, nil
+	})
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Vehicle not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute scores"})
+	}
+}
+
+// GetModelArchitecture computes a model's trim ladder as of a date (newest
+// when empty) with the same-segment models of other brands. With compare (an
+// earlier or later date) it also reports how the ladder's trims moved.
+func (h *MarketHandler) GetModelArchitecture(c *gin.Context) {
+	brandID, model := c.Param("brand"), c.Param("model")
+	date, compare := c.Query("date"), c.Query("compare")
+	for name, value := range map[string]string{"date": date, "compare": compare} {
+		if value != "" && !isDate(value) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": name + " must be in YYYY-MM-DD format"})
+			return
+		}
+	}
+
+	err := serveCached(c, h.cache, func() (any, error) {
+		ctx := c.Request.Context()
+		vehicles, _, err := h.loadMarket(ctx, date)
+		if err != nil {
+			return nil, err
+		}
+		ladder, ok := architecture.Find(vehicles, brandID, model)
+		if !ok {
+			return nil, mongo.ErrNoDocuments
+		}
+		resp := models.ModelArchitecture{
+			Date:        brandDate(vehicles, brandID),
+			Ladder:      ladder,
+			Competitors: architecture.Competitors(architecture.Ladders(vehicles), ladder),
+		}
+		if compare != "" {
+			before, _, err := h.loadMarket(ctx, compare)
+			if err != nil {
+				return nil, err
+			}
+			previous, _ := architecture.Find(before, brandID, model)
+			change := architecture.Compare(previous, ladder, brandDate(before, brandID), resp.Date)
+			resp.Change = &change
+		}
+		return resp, nil
+	})
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Model not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute architecture"})
+	}
+}
+
+// brandDate returns the snapshot date of a brand's vehicles, empty without any
+func brandDate(vehicles []market.Vehicle, brandID string) string {
+	for _, v := range vehicles {
+		if v.BrandID == brandID {
+			return v.Date
+		}
+	}
+	return ""
+}
+
+// GetGapAnalysis builds a gap heatmap for a market date over the requested
+// dimensions, price band edges and EV range buckets, defaulting to the
+// generator's segment × fuel × transmission cells and price ranges
+func (h *MarketHandler) GetGapAnalysis(c *gin.Context) {
+	date := c.Query("date")
+	if date != "" && !isDate(date) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "date must be in YYYY-MM-DD format"})
+		return
+	}
+	cfg := gaps.DefaultConfig()
+	if d := c.Query("dimensions"); d != "" {
+		dims, err := gaps.ParseDimensions(d)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		cfg.Dimensions = dims
+	}
+	for _, p := range []struct {
+		name   string
+		target *[]float64
+	}{
+		{"priceEdges", &cfg.PriceEdges},
+		{"rangeEdges", &cfg.RangeEdges},
+	} {
+		if value := c.Query(p.name); value != "" {
+			edges, err := gaps.ParseEdges(value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": p.name + ": " + err.Error()})
+				return
+			}
+			*p.target = edges
+		}
+	}
+	segments, ok := segmentFilter(c)
+	if !ok {
+		return
+	}
+
+	err := serveCached(c, h.cache, func() (any, error) {
+		vehicles, newest, err := h.loadMarket(c.Request.Context(), date)
+		if err != nil {
+			return nil, err
+		}
+		var kept []market.Vehicle
+		for _, v := range vehicles {
+			if segments.Match(v.Segment()) {
+				kept = append(kept, v)
+			}
+		}
+		analysis, err := gaps.Analyze(kept, cfg)
+		analysis.Date = newest
+		return analysis, err
+	})
+	if errors.Is(err, gaps.ErrTooManyCells) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Too many cells; use fewer dimensions or price bands"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to analyze gaps"})
+	}
+}
+
+// GetReactions analyzes how brands answer each other's price moves between
+// from and to: a follow matrix with lead/lag index correlation over the
+// selected segments, or over a vehicle's segment with that vehicle's own moves
+// and the competitor moves within the window after each. Without from the
+// last reaction.DefaultHistoryDays up to to (today when empty) are analyzed.
+func (h *MarketHandler) GetReactions(c *gin.Context) {
+	from, to := c.Query("from"), c.Query("to")
+	for name, value := range map[string]string{"from": from, "to": to} {
+		if value != "" && !isDate(value) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": name + " must be in YYYY-MM-DD format"})
+			return
+		}
+	}
+	cfg := reaction.DefaultConfig()
+	cfg.VehicleID = c.Query("vehicleId")
+	window, ok := positiveIntQuery(c, "window", reaction.DefaultWindowDays)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "window must be a positive integer"})
+		return
+	}
+	cfg.WindowDays = min(window, reaction.MaxWindowDays)
+	if m := c.Query("minMove"); m != "" {
+		v, err := strconv.ParseFloat(m, 64)
+		if err != nil || v <= 0 || v > 50 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "minMove must be a percentage in (0, 50]"})
+			return
+		}
+		cfg.MinMovePercent = v
+	}
+	if cfg.VehicleID != "" && c.Query("segment") != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "vehicleId and segment cannot be combined"})
+		return
+	}
+	segments, ok := segmentFilter(c)
+	if !ok {
+		return
+	}
+	cfg.Segments = segments
+	end := time.Now().UTC()
+	if to != "" {
+		end, _ = time.Parse("2006-01-02", to)
+	}
+	if from == "" {
+		from = end.AddDate(0, 0, -reaction.DefaultHistoryDays).Format("2006-01-02")
+	} else if start, _ := time.Parse("2006-01-02", from); start.After(end) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must not be after to"})
+		return
+	} else if end.Sub(start) > reaction.MaxHistoryDays*24*time.Hour {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from and to must be at most " + strconv.Itoa(reaction.MaxHistoryDays) + " days apart"})
+		return
+	}
+
+	err := serveCached(c, h.cache, func() (any, error) {
+		docs, err := h.vehicles.GetHistory(c.Request.Context(), "", from, to)
+		if err != nil {
+			return nil, err
+		}
+		return reaction.Analyze(docs, cfg)
+	})
+	if errors.Is(err, reaction.ErrVehicleNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Vehicle not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to analyze reactions"})
+	}
+}
+
+// GetRankings ranks the market on a date by a value-for-money metric (price
+// per HP, kWh, km of range or liter of cargo). Vehicles lacking the metric are
+// left out; ties share a rank.
+func (h *MarketHandler) GetRankings(c *gin.Context) {
+	metric := c.Query("metric")
+	if !slices.Contains(models.ValueMetricNames, metric) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "metric must be one of " + strings.Join(models.ValueMetricNames, ", ")})
+		return
+	}
+	order := c.DefaultQuery("order", "asc")
+	if order != "asc" && order != "desc" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "order must be asc or desc"})
+		return
+	}
+	date := c.Query("date")
+	if date != "" && !isDate(date) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "date must be in YYYY-MM-DD format"})
+		return
+	}
+	limit, ok := positiveIntQuery(c, "limit", 50)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+		return
+	}
+	segments, ok := segmentFilter(c)
+	if !ok {
+		return
+	}
+	brand, fuel := c.Query("brand"), c.Query("fuel")
+
+	err := serveCached(c, h.cache, func() (any, error) {
+		vehicles, newest, err := h.loadMarket(c.Request.Context(), date)
+		if err != nil {
+			return nil, err
+		}
+		resp := models.RankingsResponse{Date: newest, Metric: metric, Order: order, Entries: []models.RankedVehicle{}}
+		var ranked []models.RankedVehicle
+		for _, v := range vehicles {
+			if (brand != "" && v.BrandID != brand) || (fuel != "" && v.Fuel() != fuel) || !segments.Match(v.Segment()) {
+				continue
+			}
+			value, ok := market.Metric(v.Row.Metrics, metric)
+			if !ok {
+				continue
+			}
+			ranked = append(ranked, models.RankedVehicle{Value: value, BrandID: v.BrandID, VariantID: v.VariantID, Date: v.Date, Vehicle: v.Row})
+		}
+		sort.SliceStable(ranked, func(i, j int) bool {
+			if order == "desc" {
+				return ranked[i].Value > ranked[j].Value
+			}
+			return ranked[i].Value < ranked[j].Value
+		})
+		for i := range ranked {
+			ranked[i].Rank = i + 1
+			if i > 0 && ranked[i].Value == ranked[i-1].Value {
+				ranked[i].Rank = ranked[i-1].Rank
+			}
+		}
+		resp.Total = len(ranked)
+		resp.Entries = append(resp.Entries, ranked[:min(limit, 500, len(ranked))]...)
+		return resp, nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute rankings"})
+	}
+}
+
+// weightQuery reads an optional score weight in [0, 10], 0 when absent
+func weightQuery(c *gin.Context, name string) (float64, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return 0, true
+	}
+	w, err := strconv.ParseFloat(value, 64)
+	if err != nil || w < 0 || w > 10 {
+		return 0, false
+	}
+	return w, true
+}
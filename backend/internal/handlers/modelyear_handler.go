@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"github.com/spehlivan/price-list/backend/internal/repository"
+)
+
+type ModelYearHandler struct {
+	repo *repository.ModelYearRepository
+}
+
+func NewModelYearHandler(repo *repository.ModelYearRepository) *ModelYearHandler {
+	return &ModelYearHandler{repo: repo}
+}
+
+// GetModelYearChanges returns the model-year transitions precomputed by
+// cmd/modelyears, newest first. from and to select transitions by the date
+// the new year first appeared.
+func (h *ModelYearHandler) GetModelYearChanges(c *gin.Context) {
+	q := repository.ModelYearQuery{BrandID: c.Query("brand"), From: c.Query("from"), To: c.Query("to")}
+	for name, value := range map[string]string{"from": q.From, "to": q.To} {
+		if value != "" && !isDate(value) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": name + " must be in YYYY-MM-DD format"})
+			return
+		}
+	}
+	segments, ok := segmentFilter(c)
+	if !ok {
+		return
+	}
+
+	changes, err := h.repo.List(c.Request.Context(), q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch model-year transitions"})
+		return
+	}
+	transitions := changes[:0]
+	for _, t := range changes {
+		if t.Segment != nil && segments.Match(*t.Segment) {
+			transitions = append(transitions, t)
+		}
+	}
+	c.JSON(http.StatusOK, models.ModelYearChangesData{Total: len(transitions), Transitions: transitions})
+}
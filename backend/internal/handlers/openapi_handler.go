@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spehlivan/price-list/backend/internal/openapi"
+)
+
+type OpenAPIHandler struct {
+	doc *openapi.Document
+}
+
+func NewOpenAPIHandler(doc *openapi.Document) *OpenAPIHandler {
+	return &OpenAPIHandler{doc: doc}
+}
+
+// GetSpec returns the OpenAPI 3 document describing this API
+func (h *OpenAPIHandler) GetSpec(c *gin.Context) {
+	c.JSON(http.StatusOK, h.doc)
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spehlivan/price-list/backend/internal/market"
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"github.com/spehlivan/price-list/backend/internal/segment"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// segmentFilter reads the ?segment= parameter: comma-separated segment codes
+// (C-SUV), sizes (C) or body types (SUV). It answers 400 itself.
+func segmentFilter(c *gin.Context) (segment.Filter, bool) {
+	f, err := segment.ParseFilter(c.Query("segment"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return segment.Filter{}, false
+	}
+	return f, true
+}
+
+// segmentLatest classifies and filters every brand of a latest response and
+// adds the value metrics of the kept rows
+func segmentLatest(data *models.LatestData, f segment.Filter) {
+	data.TotalVehicles = 0
+	for brandID, brand := range data.Brands {
+		segment.Annotate(brandID, brand.Vehicles)
+		brand.Vehicles = f.Rows(brand.Vehicles)
+		market.AnnotateMetrics(brand.Vehicles)
+		data.Brands[brandID] = brand
+		data.TotalVehicles += len(brand.Vehicles)
+	}
+}
+
+// segmentStored classifies and filters the rows of a snapshot, adding metrics
+func segmentStored(data *models.StoredData, f segment.Filter) {
+	segment.Annotate(data.BrandID, data.Rows)
+	data.Rows = f.Rows(data.Rows)
+	market.AnnotateMetrics(data.Rows)
+	data.RowCount = len(data.Rows)
+}
+
+// segmentDaily classifies and filters the rows of a forward-filled day,
+// adding metrics
+func segmentDaily(doc *models.DailyDocument, f segment.Filter) {
+	rows := make([]models.PriceListRow, len(doc.Rows))
+	for i, row := range doc.Rows {
+		rows[i] = row.PriceListRow
+	}
+	segment.Annotate(doc.BrandID, rows)
+
+	kept := doc.Rows[:0]
+	doc.FilledRows = 0
+	for i, row := range doc.Rows {
+		if !f.Match(*rows[i].Segment) {
+			continue
+		}
+		row.Segment = rows[i].Segment
+		row.Metrics = market.Metrics(row.PriceListRow)
+		kept = append(kept, row)
+		if row.Filled {
+			doc.FilledRows++
+		}
+	}
+	doc.Rows = kept
+	doc.RowCount = len(kept)
+}
+
+// itemPriceFields are the price fields of intel items, in order of preference
+var itemPriceFields = []string{"price", "newPrice", "currentPrice", "entryPrice", "currentEntryPrice", "newEntryPrice", "basePrice"}
+
+// segmentItems classifies the vehicle items of a precomputed intel document
+// and drops those outside the filter. Paths name item arrays at the top level
+// or one level down ("bigMoves.topIncreases"); each kept item gains a segment
+// field. Summaries are left as generated, i.e. over all segments.
+func segmentItems(doc bson.M, f segment.Filter, paths ...string) {
+	for _, path := range paths {
+		parent, child, nested := strings.Cut(path, ".")
+		if !nested {
+			if items, ok := doc[path].(bson.A); ok {
+				doc[path] = classifyItems(items, f)
+			}
+			continue
+		}
+		d, ok := doc[parent].(bson.D)
+		if !ok {
+			continue
+		}
+		for i := range d {
+			if items, ok := d[i].Value.(bson.A); ok && d[i].Key == child {
+				d[i].Value = classifyItems(items, f)
+			}
+		}
+	}
+}
+
+func classifyItems(items bson.A, f segment.Filter) bson.A {
+	inputs := make([]segment.Input, len(items))
+	entry := make(map[string]float64)
+	for i, item := range items {
+		d, ok := item.(bson.D)
+		if !ok {
+			continue
+		}
+		in := itemInput(d)
+		inputs[i] = in
+		key := in.BrandID + "|" + in.Model
+		if p, ok := entry[key]; in.Price > 0 && (!ok || in.Price < p) {
+			entry[key] = in.Price
+		}
+	}
+
+	kept := bson.A{}
+	for i, item := range items {
+		d, ok := item.(bson.D)
+		if !ok {
+			continue
+		}
+		in := inputs[i]
+		in.Price = entry[in.BrandID+"|"+in.Model]
+		s := segment.Classify(in)
+		if f.Match(s) {
+			kept = append(kept, append(d, bson.E{Key: "segment", Value: s}))
+		}
+	}
+	return kept
+}
+
+func itemInput(d bson.D) segment.Input {
+	var in segment.Input
+	prices := make(map[string]float64)
+	for _, e := range d {
+		switch v := e.Value.(type) {
+		case string:
+			switch e.Key {
+			case "brandId":
+				in.BrandID = v
+			case "model":
+				in.Model = v
+			case "trim":
+				in.Trim = v
+			}
+		case float64:
+			prices[e.Key] = v
+		case int32:
+			prices[e.Key] = float64(v)
+		case int64:
+			prices[e.Key] = float64(v)
+		}
+	}
+	for _, field := range itemPriceFields {
+		if p, ok := prices[field]; ok && p > 0 {
+			in.Price = p
+			break
+		}
+	}
+	return in
+}
@@ -3,23 +3,32 @@ package handlers
 import (
 	"errors"
 	"net/http"
+	"sort"
 
 	"github.com/gin-gonic/gin"
+	"github.com/spehlivan/price-list/backend/internal/market"
+	"github.com/spehlivan/price-list/backend/internal/models"
 	"github.com/spehlivan/price-list/backend/internal/repository"
+	"github.com/spehlivan/price-list/backend/internal/vocab"
+	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
 type StatsHandler struct {
-	repo *repository.StatsRepository
+	repo       *repository.StatsRepository
+	vocabulary *repository.VocabularyRepository
 }
 
-func NewStatsHandler(repo *repository.StatsRepository) *StatsHandler {
-	return &StatsHandler{repo: repo}
+func NewStatsHandler(repo *repository.StatsRepository, vocabulary *repository.VocabularyRepository) *StatsHandler {
+	return &StatsHandler{repo: repo, vocabulary: vocabulary}
 }
 
-// GetStats returns the latest precomputed statistics
+// GetStats returns the latest precomputed statistics. The generator buckets
+// vehicles by raw fuel and transmission labels ("Hibrit", "DSG"), so those
+// breakdowns are regrouped on the canonical vocabulary before responding.
 func (h *StatsHandler) GetStats(c *gin.Context) {
-	data, err := h.repo.GetLatest(c.Request.Context())
+	ctx := c.Request.Context()
+	data, err := h.repo.GetLatest(ctx)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "No stats data available"})
@@ -28,5 +37,114 @@ func (h *StatsHandler) GetStats(c *gin.Context) {
 		}
 		return
 	}
+	table, err := h.vocabulary.Table(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load vocabulary"})
+		return
+	}
+	if err := canonicalStats(data, table); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch statistics"})
+		return
+	}
 	c.JSON(http.StatusOK, data)
 }
+
+// canonicalStats rewrites the fuel and transmission breakdowns of a stats
+// document in place, merging raw labels that share a canonical value
+func canonicalStats(data bson.M, table *vocab.Table) error {
+	var fuels []models.FuelStats
+	if ok, err := decodeField(data, "fuelStats", &fuels); err != nil {
+		return err
+	} else if ok {
+		data["fuelStats"] = regroup(fuels,
+			func(s models.FuelStats) string { return canonicalLabel(table, models.VocabFuel, s.Fuel) },
+			func(into *models.FuelStats, s models.FuelStats) {
+				into.AvgPrice = weighted(into.AvgPrice, into.Count, s.AvgPrice, s.Count, 0)
+				into.Count += s.Count
+				into.Percentage = market.Round(into.Percentage+s.Percentage, 1)
+			},
+			func(s *models.FuelStats, key string) { s.Fuel = key },
+			func(s models.FuelStats) int { return s.Count })
+	}
+
+	var transmissions []models.TransmissionStats
+	if ok, err := decodeField(data, "transmissionStats", &transmissions); err != nil {
+		return err
+	} else if ok {
+		data["transmissionStats"] = regroup(transmissions,
+			func(s models.TransmissionStats) string {
+				return canonicalLabel(table, models.VocabTransmission, s.Transmission)
+			},
+			func(into *models.TransmissionStats, s models.TransmissionStats) {
+				into.AvgPrice = weighted(into.AvgPrice, into.Count, s.AvgPrice, s.Count, 0)
+				into.Count += s.Count
+				into.Percentage = market.Round(into.Percentage+s.Percentage, 1)
+			},
+			func(s *models.TransmissionStats, key string) { s.Transmission = key },
+			func(s models.TransmissionStats) int { return s.Count })
+	}
+
+	var consumption models.FuelConsumptionStats
+	if ok, err := decodeField(data, "fuelConsumptionStats", &consumption); err != nil {
+		return err
+	} else if ok {
+		consumption.ByFuel = regroup(consumption.ByFuel,
+			func(s models.FuelConsumption) string { return canonicalLabel(table, models.VocabFuel, s.Fuel) },
+			func(into *models.FuelConsumption, s models.FuelConsumption) {
+				into.AvgConsumption = weighted(into.AvgConsumption, into.Count, s.AvgConsumption, s.Count, 1)
+				into.Count += s.Count
+			},
+			func(s *models.FuelConsumption, key string) { s.Fuel = key },
+			func(s models.FuelConsumption) int { return s.Count })
+		data["fuelConsumptionStats"] = consumption
+	}
+	return nil
+}
+
+// canonicalLabel maps a raw bucket label, counting empty labels as unknown
+func canonicalLabel(table *vocab.Table, field, raw string) string {
+	if canonical := table.Canonical(field, raw); canonical != "" {
+		return canonical
+	}
+	return models.VocabUnknown
+}
+
+// decodeField decodes data[key] into out, reporting whether the key was set
+func decodeField(data bson.M, key string, out any) (bool, error) {
+	v, ok := data[key]
+	if !ok || v == nil {
+		return false, nil
+	}
+	t, raw, err := bson.MarshalValue(v)
+	if err != nil {
+		return false, err
+	}
+	return true, bson.RawValue{Type: t, Value: raw}.Unmarshal(out)
+}
+
+// regroup merges buckets with the same canonical key into the first of them,
+// then orders the result by count, largest first
+func regroup[T any](buckets []T, key func(T) string, merge func(into *T, b T), relabel func(*T, string), count func(T) int) []T {
+	out := make([]T, 0, len(buckets))
+	index := make(map[string]int)
+	for _, b := range buckets {
+		k := key(b)
+		if i, ok := index[k]; ok {
+			merge(&out[i], b)
+			continue
+		}
+		relabel(&b, k)
+		index[k] = len(out)
+		out = append(out, b)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return count(out[i]) > count(out[j]) })
+	return out
+}
+
+// weighted is the count-weighted mean of two averages
+func weighted(a float64, na int, b float64, nb int, decimals int) float64 {
+	if na+nb == 0 {
+		return 0
+	}
+	return market.Round((a*float64(na)+b*float64(nb))/float64(na+nb), decimals)
+}
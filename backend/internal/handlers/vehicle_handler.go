@@ -3,75 +3,283 @@ package handlers
 import (
 	"errors"
 	"net/http"
+	"slices"
+	"sort"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/spehlivan/price-list/backend/internal/cache"
+	"github.com/spehlivan/price-list/backend/internal/forecast"
+	"github.com/spehlivan/price-list/backend/internal/models"
 	"github.com/spehlivan/price-list/backend/internal/repository"
+	"github.com/spehlivan/price-list/backend/internal/staleness"
+	"github.com/spehlivan/price-list/backend/internal/variant"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
 type VehicleHandler struct {
-	repo *repository.VehicleRepository
+	repo     *repository.VehicleRepository
+	variants *repository.VariantRepository
+	cache    *cache.Cache
 }
 
-func NewVehicleHandler(repo *repository.VehicleRepository) *VehicleHandler {
-	return &VehicleHandler{repo: repo}
+func NewVehicleHandler(repo *repository.VehicleRepository, variants *repository.VariantRepository, rc *cache.Cache) *VehicleHandler {
+	return &VehicleHandler{repo: repo, variants: variants, cache: rc}
 }
 
 // GetIndex returns available dates per brand
 func (h *VehicleHandler) GetIndex(c *gin.Context) {
-	data, err := h.repo.GetIndex(c.Request.Context())
+	err := serveCached(c, h.cache, func() (any, error) {
+		return h.repo.GetIndex(c.Request.Context())
+	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch index"})
-		return
 	}
-	c.JSON(http.StatusOK, data)
 }
 
-// GetLatest returns the latest data for all brands
+// GetLatest returns the latest data for all brands, optionally restricted to
+// segments
 func (h *VehicleHandler) GetLatest(c *gin.Context) {
-	data, err := h.repo.GetLatest(c.Request.Context())
+	segments, ok := segmentFilter(c)
+	if !ok {
+		return
+	}
+
+	err := serveCached(c, h.cache, func() (any, error) {
+		data, err := h.repo.GetLatest(c.Request.Context())
+		if err != nil {
+			return nil, err
+		}
+		segmentLatest(data, segments)
+		return data, nil
+	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch latest data"})
+	}
+}
+
+// GetCoverage returns per-brand calendars of present and missing snapshot
+// dates over a window of days (default 90) ending at `to` (default: newest data)
+func (h *VehicleHandler) GetCoverage(c *gin.Context) {
+	to := c.Query("to")
+	if to != "" && !isDate(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must be a date in YYYY-MM-DD format"})
+		return
+	}
+	days, ok := positiveIntQuery(c, "days", 90)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be a positive integer"})
 		return
 	}
-	c.JSON(http.StatusOK, data)
+
+	err := serveCached(c, h.cache, func() (any, error) {
+		return h.repo.GetCoverage(c.Request.Context(), c.Query("brand"), to, min(days, 730))
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch coverage"})
+	}
 }
 
-// GetTrend returns price history for a specific vehicle
+// GetStaleness reports per brand and model when prices last really changed
+// and which sources look frozen: collected, but unchanged for `days` (default
+// 30) up to each brand's newest snapshot on or before `to`. Only the
+// staleness.HistoryDays before `to` (today when empty) are read.
+func (h *VehicleHandler) GetStaleness(c *gin.Context) {
+	to := c.Query("to")
+	if to != "" && !isDate(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must be a date in YYYY-MM-DD format"})
+		return
+	}
+	days, ok := positiveIntQuery(c, "days", staleness.DefaultDays)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be a positive integer"})
+		return
+	}
+	days = min(days, staleness.MaxDays)
+	end := time.Now().UTC()
+	if to != "" {
+		end, _ = time.Parse("2006-01-02", to)
+	}
+	from := end.AddDate(0, 0, -staleness.HistoryDays).Format("2006-01-02")
+
+	err := serveCached(c, h.cache, func() (any, error) {
+		docs, err := h.repo.GetHistory(c.Request.Context(), c.Query("brand"), from, to)
+		if err != nil {
+			return nil, err
+		}
+		data := models.StalenessData{To: to, Days: days, Brands: []models.BrandStaleness{}}
+		for start := 0; start < len(docs); {
+			end := start
+			for end < len(docs) && docs[end].BrandID == docs[start].BrandID {
+				end++
+			}
+			b := staleness.Analyze(docs[start:end], days)
+			if b.Frozen {
+				data.Frozen++
+			}
+			data.Brands = append(data.Brands, b)
+			start = end
+		}
+		sort.SliceStable(data.Brands, func(i, j int) bool {
+			a, b := data.Brands[i], data.Brands[j]
+			if a.Frozen != b.Frozen {
+				return a.Frozen
+			}
+			return a.DaysSinceChange > b.DaysSinceChange
+		})
+		data.Total = len(data.Brands)
+		return data, nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute staleness"})
+	}
+}
+
+// GetTrend returns price history for a specific vehicle, identified either by
+// variantId or by brand, model, trim and engine. Unless lineage=false, the
+// history follows the variant across renames.
 func (h *VehicleHandler) GetTrend(c *gin.Context) {
-	brand := c.Query("brand")
-	model := c.Query("model")
-	trim := c.Query("trim")
-	engine := c.Query("engine")
+	q := repository.TrendQuery{
+		BrandID: c.Query("brand"),
+		Model:   c.Query("model"),
+		Trim:    c.Query("trim"),
+		Engine:  c.Query("engine"),
+		Limit:   10,
+	}
+	variantID := c.Query("variantId")
 
-	if brand == "" || model == "" || trim == "" || engine == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "brand, model, trim, and engine query parameters are required"})
+	if variantID == "" && (q.BrandID == "" || q.Model == "" || q.Trim == "" || q.Engine == "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "variantId, or brand, model, trim, and engine query parameters are required"})
+		return
+	}
+	fill, ok := fillForward(c)
+	if !ok {
+		return
+	}
+	q.Fill = fill
+	lineage := true
+	switch c.Query("lineage") {
+	case "", "true":
+	case "false":
+		lineage = false
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "lineage must be true or false"})
 		return
 	}
 
-	limit := 10
-	days := 0
 	if d := c.Query("days"); d != "" {
 		if parsed, err := strconv.Atoi(d); err == nil && parsed > 0 {
 			if parsed > 3650 {
 				parsed = 3650 // cap at ~10 years to bound the query (avoid abuse)
 			}
-			days = parsed
-			limit = parsed // allow up to `days` data points
+			q.Days = parsed
+			q.Limit = parsed // allow up to `days` data points
 		}
 	}
 
-	points, err := h.repo.GetTrend(c.Request.Context(), brand, model, trim, engine, limit, days)
+	err := serveCached(c, h.cache, func() (any, error) {
+		ctx := c.Request.Context()
+		if variantID != "" {
+			brandID, row, err := h.repo.FindVariant(ctx, variantID)
+			if err != nil {
+				return nil, err
+			}
+			q.BrandID, q.Model, q.Trim, q.Engine = brandID, row.Model, row.Trim, row.Engine
+		}
+
+		key := variant.Key(q.Model, q.Trim, q.Engine)
+		resp := models.TrendResponse{}
+		if lineage {
+			resolver, err := h.variants.Resolver(ctx, q.BrandID)
+			if err != nil {
+				return nil, err
+			}
+			q.Keys = resolver.Lineage(q.BrandID, key)
+			resp.VariantID = resolver.ID(q.BrandID, key)
+			if resp.Lineage, err = h.repo.GetVariantNames(ctx, q.BrandID, q.Keys); err != nil {
+				return nil, err
+			}
+		}
+
+		points, err := h.repo.GetTrend(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		resp.Points = points
+		return resp, nil
+	})
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Variant not found"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch trend data"})
+	}
+}
+
+// GetForecast forecasts a variant's price over the next horizon days (default
+// 30) with drift, exponential smoothing and step models fitted to its daily
+// trend across renames, each with prediction intervals and its backtest error.
+// Intervals assume at least the market-wide repricing of forecast.DefaultPrior.
+func (h *VehicleHandler) GetForecast(c *gin.Context) {
+	variantID := c.Query("vehicleId")
+	if variantID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "vehicleId query parameter is required"})
+		return
+	}
+	horizon, ok := positiveIntQuery(c, "horizon", forecast.DefaultHorizon)
+	if !ok || horizon > forecast.MaxHorizon {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "horizon must be an integer between 1 and 180"})
+		return
+	}
+	level, ok := positiveIntQuery(c, "level", forecast.DefaultLevel)
+	if !ok || !slices.Contains(forecast.Levels, level) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "level must be 80, 90 or 95"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"points": points})
+	err := serveCached(c, h.cache, func() (any, error) {
+		ctx := c.Request.Context()
+		brandID, row, err := h.repo.FindVariant(ctx, variantID)
+		if err != nil {
+			return nil, err
+		}
+		resolver, err := h.variants.Resolver(ctx, brandID)
+		if err != nil {
+			return nil, err
+		}
+		key := variant.Key(row.Model, row.Trim, row.Engine)
+		points, err := h.repo.GetTrend(ctx, repository.TrendQuery{
+			BrandID: brandID,
+			Model:   row.Model,
+			Trim:    row.Trim,
+			Engine:  row.Engine,
+			Keys:    resolver.Lineage(brandID, key),
+			Limit:   365,
+		})
+		if err != nil {
+			return nil, err
+		}
+		resp, err := forecast.Forecast(points, horizon, level, forecast.DefaultPrior)
+		resp.VariantID = resolver.ID(brandID, key)
+		return resp, err
+	})
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Variant not found"})
+		return
+	}
+	if errors.Is(err, forecast.ErrShortHistory) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Not enough price history to forecast"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute forecast"})
+	}
 }
 
-// GetVehicles returns vehicle data for a specific brand and date
+// GetVehicles returns vehicle data for a specific brand and date, optionally
+// restricted to segments
 func (h *VehicleHandler) GetVehicles(c *gin.Context) {
 	brand := c.Query("brand")
 	date := c.Query("date")
@@ -85,16 +293,50 @@ func (h *VehicleHandler) GetVehicles(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "date query parameter is required"})
 		return
 	}
+	fill, ok := fillForward(c)
+	if !ok {
+		return
+	}
+	segments, ok := segmentFilter(c)
+	if !ok {
+		return
+	}
 
-	data, err := h.repo.GetByBrandAndDate(c.Request.Context(), brand, date)
+	err := serveCached(c, h.cache, func() (any, error) {
+		if fill {
+			doc, err := h.repo.GetFilledByBrandAndDate(c.Request.Context(), brand, date)
+			if err != nil {
+				return nil, err
+			}
+			segmentDaily(doc, segments)
+			return doc, nil
+		}
+		data, err := h.repo.GetByBrandAndDate(c.Request.Context(), brand, date)
+		if err != nil {
+			return nil, err
+		}
+		segmentStored(data, segments)
+		return data, nil
+	})
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Data not found for the specified brand and date"})
 		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch vehicle data"})
 		}
-		return
 	}
+}
 
-	c.JSON(http.StatusOK, data)
+// fillForward reads the ?fill= parameter: "forward" selects the forward-filled
+// daily series, empty or "none" the observed snapshots. It answers 400 itself.
+func fillForward(c *gin.Context) (bool, bool) {
+	switch c.Query("fill") {
+	case "", "none":
+		return false, true
+	case "forward":
+		return true, true
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "fill must be none or forward"})
+		return false, false
+	}
 }
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"github.com/spehlivan/price-list/backend/internal/repository"
+	"github.com/spehlivan/price-list/backend/internal/vocab"
+)
+
+type VocabularyHandler struct {
+	repo *repository.VocabularyRepository
+}
+
+func NewVocabularyHandler(repo *repository.VocabularyRepository) *VocabularyHandler {
+	return &VocabularyHandler{repo: repo}
+}
+
+// GetVocabulary returns the effective fuel, transmission and drive type
+// mapping table and the raw values in stored data it does not cover
+func (h *VocabularyHandler) GetVocabulary(c *gin.Context) {
+	table, err := h.repo.Table(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch vocabulary"})
+		return
+	}
+	h.respond(c, table, nil)
+}
+
+// PutVocabulary maps a raw value onto a canonical value and restamps the
+// stored rows of that field. Stored rows are rewritten before the override
+// is saved, so cached responses only turn over once the data is consistent.
+func (h *VocabularyHandler) PutVocabulary(c *gin.Context) {
+	var req models.VocabularyUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if _, ok := models.VocabularyValues[req.Field]; !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "field must be one of " + strings.Join(models.VocabularyFields, ", ")})
+		return
+	}
+	if vocab.Key(req.Raw) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "raw must not be empty"})
+		return
+	}
+	if !vocab.Valid(req.Field, req.Canonical) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "canonical must be one of " + strings.Join(models.VocabularyValues[req.Field], ", ")})
+		return
+	}
+
+	ctx := c.Request.Context()
+	overrides, err := h.repo.Overrides(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch vocabulary"})
+		return
+	}
+	entry := models.VocabularyEntry{Field: req.Field, Raw: req.Raw, Canonical: req.Canonical}
+	table := vocab.New(append(overrides, entry))
+
+	restamped, err := h.repo.Restamp(ctx, table, req.Field)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to apply vocabulary"})
+		return
+	}
+	if err := h.repo.Set(ctx, entry); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save vocabulary"})
+		return
+	}
+	h.respond(c, table, &restamped)
+}
+
+// DeleteVocabulary removes an override, reverting the raw value to the
+// built-in table, and restamps the stored rows of that field
+func (h *VocabularyHandler) DeleteVocabulary(c *gin.Context) {
+	field := c.Query("field")
+	raw := vocab.Key(c.Query("raw"))
+	if field == "" || raw == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "field and raw query parameters are required"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	overrides, err := h.repo.Overrides(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch vocabulary"})
+		return
+	}
+	remaining := make([]models.VocabularyEntry, 0, len(overrides))
+	for _, o := range overrides {
+		if o.Field != field || o.Raw != raw {
+			remaining = append(remaining, o)
+		}
+	}
+	if len(remaining) == len(overrides) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Vocabulary override not found"})
+		return
+	}
+	table := vocab.New(remaining)
+
+	restamped, err := h.repo.Restamp(ctx, table, field)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to apply vocabulary"})
+		return
+	}
+	if err := h.repo.Remove(ctx, field, raw); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete vocabulary override"})
+		return
+	}
+	h.respond(c, table, &restamped)
+}
+
+// RestampVocabulary rewrites the canonical values of every stored row from
+// the current table, e.g. for snapshots imported before the vocabulary existed
+func (h *VocabularyHandler) RestampVocabulary(c *gin.Context) {
+	ctx := c.Request.Context()
+	table, err := h.repo.Table(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch vocabulary"})
+		return
+	}
+
+	var restamped int64
+	for _, field := range models.VocabularyFields {
+		n, err := h.repo.Restamp(ctx, table, field)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to apply vocabulary"})
+			return
+		}
+		restamped += n
+	}
+	h.respond(c, table, &restamped)
+}
+
+func (h *VocabularyHandler) respond(c *gin.Context, table *vocab.Table, restamped *int64) {
+	unmapped, err := h.repo.Unmapped(c.Request.Context(), table)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch vocabulary"})
+		return
+	}
+	c.JSON(http.StatusOK, models.VocabularyResponse{
+		Entries:   table.Entries(),
+		Unmapped:  unmapped,
+		Values:    models.VocabularyValues,
+		Restamped: restamped,
+	})
+}
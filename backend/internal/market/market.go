@@ -0,0 +1,172 @@
+// Package market flattens brand snapshots into one comparable set of priced
+// vehicles, each carrying its variant id, canonical attributes and segment,
+// and holds the price helpers the Go-side analyses share (price bands,
+// Turkish lira formatting and peer statistics).
+package market
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"github.com/spehlivan/price-list/backend/internal/segment"
+	"github.com/spehlivan/price-list/backend/internal/variant"
+	"github.com/spehlivan/price-list/backend/internal/vocab"
+)
+
+// Vehicle is a priced row of a brand's snapshot. Row.Canonical and
+// Row.Segment are always set, Row.Metrics when the row has any.
+type Vehicle struct {
+	BrandID   string
+	Date      string // snapshot date
+	VariantID string
+	Row       models.PriceListRow
+}
+
+// Price returns the row's price
+func (v Vehicle) Price() float64 {
+	return v.Row.PriceNumeric
+}
+
+// Fuel returns the canonical fuel
+func (v Vehicle) Fuel() string {
+	return v.Row.Canonical.Fuel
+}
+
+// Segment returns the vehicle's segment
+func (v Vehicle) Segment() models.Segment {
+	return *v.Row.Segment
+}
+
+// IsElectric reports whether the vehicle is a battery electric
+func (v Vehicle) IsElectric() bool {
+	return isElectric(v.Row)
+}
+
+// FromSnapshots flattens snapshots into vehicles, skipping unpriced rows.
+// Canonical attributes come from the table so vocabulary edits apply even to
+// rows stamped before them.
+func FromSnapshots(docs []models.VehicleDocument, table *vocab.Table) []Vehicle {
+	var vehicles []Vehicle
+	for _, doc := range docs {
+		rows := append([]models.PriceListRow(nil), doc.Rows...)
+		segment.Annotate(doc.BrandID, rows)
+		for _, row := range rows {
+			if row.PriceNumeric <= 0 {
+				continue
+			}
+			attrs := table.Row(row)
+			row.Canonical = &attrs
+			row.Metrics = Metrics(row)
+			id := row.VariantID
+			if id == "" {
+				id = variant.ID(doc.BrandID, variant.RowKey(row))
+			}
+			vehicles = append(vehicles, Vehicle{BrandID: doc.BrandID, Date: doc.Date, VariantID: id, Row: row})
+		}
+	}
+	return vehicles
+}
+
+// Price bands, as used by the insights generator
+const (
+	BandEconomy = "economy" // < 1M TL
+	BandBudget  = "budget"  // 1–2M
+	BandMid     = "mid"     // 2–3.5M
+	BandPremium = "premium" // 3.5–6M
+	BandLuxury  = "luxury"  // > 6M
+)
+
+var priceBands = []struct {
+	upper float64
+	name  string
+}{
+	{1_000_000, BandEconomy},
+	{2_000_000, BandBudget},
+	{3_500_000, BandMid},
+	{6_000_000, BandPremium},
+}
+
+// PriceBand returns the price band of a price
+func PriceBand(price float64) string {
+	for _, b := range priceBands {
+		if price < b.upper {
+			return b.name
+		}
+	}
+	return BandLuxury
+}
+
+// FormatTL formats a price the way the generators do (tr-TR currency without
+// decimals), e.g. ₺2.252.000
+func FormatTL(price float64) string {
+	n := int64(math.Round(price))
+	sign := ""
+	if n < 0 {
+		sign, n = "-", -n
+	}
+	digits := strconv.FormatInt(n, 10)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(d)
+	}
+	return sign + "₺" + b.String()
+}
+
+// Stats summarizes a set of values for z-scores and percentiles
+type Stats struct {
+	Mean   float64
+	StdDev float64 // population standard deviation
+	Sorted []float64
+}
+
+// NewStats computes the statistics of values
+func NewStats(values []float64) Stats {
+	s := Stats{Sorted: append([]float64(nil), values...)}
+	sort.Float64s(s.Sorted)
+	if len(values) == 0 {
+		return s
+	}
+	for _, v := range values {
+		s.Mean += v
+	}
+	s.Mean /= float64(len(values))
+	var sq float64
+	for _, v := range values {
+		sq += (v - s.Mean) * (v - s.Mean)
+	}
+	s.StdDev = math.Sqrt(sq / float64(len(values)))
+	return s
+}
+
+// ZScore returns how many standard deviations value is from the mean
+func (s Stats) ZScore(value float64) float64 {
+	if s.StdDev == 0 {
+		return 0
+	}
+	return (value - s.Mean) / s.StdDev
+}
+
+// Percentile returns the rank of value in 0–100, taken at the first value
+// not below it. Sets of fewer than three values say nothing and return 50.
+func (s Stats) Percentile(value float64) float64 {
+	if len(s.Sorted) < 3 {
+		return 50
+	}
+	i := sort.SearchFloat64s(s.Sorted, value)
+	if i == len(s.Sorted) {
+		return 100
+	}
+	return math.Round((float64(i) + 0.5) / float64(len(s.Sorted)) * 100)
+}
+
+// Round rounds to the given number of decimals
+func Round(v float64, decimals int) float64 {
+	p := math.Pow(10, float64(decimals))
+	return math.Round(v*p) / p
+}
@@ -0,0 +1,74 @@
+package market
+
+import (
+	"math"
+
+	"github.com/spehlivan/price-list/backend/internal/models"
+)
+
+// Metrics computes a row's value-for-money metrics, rounded to whole lira;
+// nil when it has none. The LCV metric needs the row's segment, so rows are
+// classified first.
+func Metrics(row models.PriceListRow) *models.ValueMetrics {
+	price := row.PriceNumeric
+	if price <= 0 {
+		return nil
+	}
+	per := func(unit *float64, scale float64) *float64 {
+		if unit == nil || *unit <= 0 {
+			return nil
+		}
+		v := math.Round(price / (*unit * scale))
+		return &v
+	}
+
+	m := models.ValueMetrics{
+		TLPerHP:  per(row.PowerHP, 1),
+		TLPerKWh: per(row.BatteryCapacity, 1),
+	}
+	if isElectric(row) {
+		m.TLPerKm = per(row.WltpRange, 1)
+	}
+	if row.Segment != nil && row.Segment.Body == models.BodyLCV {
+		m.TLPerLiter = per(row.CargoVolume, 1000) // m³
+	}
+	if m == (models.ValueMetrics{}) {
+		return nil
+	}
+	return &m
+}
+
+// Metric returns the named metric of a row's metrics
+func Metric(m *models.ValueMetrics, name string) (float64, bool) {
+	if m == nil {
+		return 0, false
+	}
+	var v *float64
+	switch name {
+	case models.MetricTLPerHP:
+		v = m.TLPerHP
+	case models.MetricTLPerKWh:
+		v = m.TLPerKWh
+	case models.MetricTLPerKm:
+		v = m.TLPerKm
+	case models.MetricTLPerLiter:
+		v = m.TLPerLiter
+	}
+	if v == nil {
+		return 0, false
+	}
+	return *v, true
+}
+
+// AnnotateMetrics sets the metrics of every row; rows must be classified
+func AnnotateMetrics(rows []models.PriceListRow) {
+	for i := range rows {
+		rows[i].Metrics = Metrics(rows[i])
+	}
+}
+
+// isElectric reports whether a row is a battery electric. Range-per-price is
+// meaningless for plug-in hybrids, whose WLTP range is the electric-only one.
+func isElectric(row models.PriceListRow) bool {
+	return (row.Canonical != nil && row.Canonical.Fuel == models.FuelElectric) || (row.IsElectric != nil && *row.IsElectric)
+}
@@ -0,0 +1,174 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spehlivan/price-list/backend/internal/auth"
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"github.com/spehlivan/price-list/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// APIKeyContextKey is the gin context key holding the authenticated *models.APIKey
+const APIKeyContextKey = "apiKey"
+
+// keyCacheTTL bounds how long a revoked key keeps working on a replica
+const keyCacheTTL = time.Minute
+
+type cachedKey struct {
+	key       *models.APIKey
+	expiresAt time.Time
+}
+
+// APIKeyAuth authenticates requests by API key and enforces scopes and daily quotas
+type APIKeyAuth struct {
+	repo     *repository.APIKeyRepository
+	required bool
+
+	mu    sync.Mutex
+	cache map[string]cachedKey
+}
+
+// NewAPIKeyAuth creates the API key middleware. When required is false,
+// requests without a key are served anonymously on read routes; a key that is
+// present is always validated.
+func NewAPIKeyAuth(repo *repository.APIKeyRepository, required bool) *APIKeyAuth {
+	return &APIKeyAuth{
+		repo:     repo,
+		required: required,
+		cache:    make(map[string]cachedKey),
+	}
+}
+
+// Authenticate resolves the API key of the request. Usage is counted by
+// CountUsage once the rate limiter has admitted the request.
+func (a *APIKeyAuth) Authenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		plaintext := extractKey(c.Request)
+		if plaintext == "" {
+			c.Next()
+			return
+		}
+
+		key, err := a.lookup(c.Request.Context(), auth.HashKey(plaintext))
+		if err != nil {
+			log.Printf("API key lookup failed: %v", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify API key"})
+			return
+		}
+		if key == nil || key.Revoked() {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+			return
+		}
+
+		c.Set(APIKeyContextKey, key)
+		c.Next()
+	}
+}
+
+// CountUsage counts a request against its key's daily quota, aborting with
+// 429 once the quota is exhausted. It reports whether the request may
+// proceed; anonymous requests always may.
+func (a *APIKeyAuth) CountUsage(c *gin.Context) bool {
+	key := KeyFromContext(c)
+	if key == nil {
+		return true
+	}
+
+	today := time.Now().UTC().Format("2006-01-02")
+	count, err := a.repo.IncrementUsage(c.Request.Context(), key.KeyID, today)
+	if err != nil {
+		log.Printf("API key usage update failed for %s: %v", key.KeyID, err)
+		return true
+	}
+	if key.DailyQuota > 0 {
+		remaining := int64(key.DailyQuota) - count
+		c.Header("X-Quota-Limit", strconv.Itoa(key.DailyQuota))
+		c.Header("X-Quota-Remaining", strconv.FormatInt(max(remaining, 0), 10))
+		if remaining < 0 {
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Daily quota exceeded"})
+			return false
+		}
+	}
+	return true
+}
+
+// RequireScope rejects requests whose key lacks scope. Anonymous requests pass
+// read scopes unless keys are required; the admin scope always needs a key.
+func (a *APIKeyAuth) RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := KeyFromContext(c)
+		if key == nil {
+			if !a.required && scope != models.ScopeAdmin {
+				c.Next()
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "API key required"})
+			return
+		}
+		if !key.HasScope(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "API key lacks scope " + scope})
+			return
+		}
+		c.Next()
+	}
+}
+
+// KeyFromContext returns the authenticated key of the request, or nil for anonymous requests
+func KeyFromContext(c *gin.Context) *models.APIKey {
+	if v, ok := c.Get(APIKeyContextKey); ok {
+		if key, ok := v.(*models.APIKey); ok {
+			return key
+		}
+	}
+	return nil
+}
+
+// lookup returns the key for hash, or nil if it does not exist. Only known
+// keys are cached, so random invalid keys cannot grow the cache.
+func (a *APIKeyAuth) lookup(ctx context.Context, hash string) (*models.APIKey, error) {
+	now := time.Now()
+	a.mu.Lock()
+	entry, ok := a.cache[hash]
+	a.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.key, nil
+	}
+
+	key, err := a.repo.FindByHash(ctx, hash)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	for h, e := range a.cache {
+		if now.After(e.expiresAt) {
+			delete(a.cache, h)
+		}
+	}
+	a.cache[hash] = cachedKey{key: key, expiresAt: now.Add(keyCacheTTL)}
+	a.mu.Unlock()
+	return key, nil
+}
+
+// extractKey reads the key from the X-API-Key header or an Authorization: Bearer header
+func extractKey(r *http.Request) string {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return strings.TrimSpace(key)
+	}
+	if authz := r.Header.Get("Authorization"); len(authz) > 7 && strings.EqualFold(authz[:7], "Bearer ") {
+		return strings.TrimSpace(authz[7:])
+	}
+	return ""
+}
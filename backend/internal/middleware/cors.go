@@ -8,6 +8,13 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// exposeHeaders are response headers readable by browser clients
+var exposeHeaders = []string{
+	"Content-Length", "ETag", "Last-Modified",
+	"X-Quota-Limit", "X-Quota-Remaining",
+	"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After",
+}
+
 // CORSMiddleware returns a configured CORS middleware
 func CORSMiddleware(allowedOrigins string) gin.HandlerFunc {
 	origins := strings.Split(allowedOrigins, ",")
@@ -17,9 +24,9 @@ func CORSMiddleware(allowedOrigins string) gin.HandlerFunc {
 
 	return cors.New(cors.Config{
 		AllowOrigins:     origins,
-		AllowMethods:     []string{"GET", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowMethods:     []string{"GET", "PUT", "POST", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-API-Key", "If-None-Match", "If-Modified-Since"},
+		ExposeHeaders:    exposeHeaders,
 		AllowCredentials: false,
 		MaxAge:           12 * time.Hour,
 	})
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spehlivan/price-list/backend/internal/ratelimit"
+)
+
+// RateLimiter throttles clients with token buckets keyed by API key, or by
+// client IP for anonymous requests. Each route consumes its own cost.
+type RateLimiter struct {
+	store     ratelimit.Store
+	anonymous ratelimit.Limit
+	keyed     ratelimit.Limit
+	admit     func(*gin.Context) bool
+}
+
+// NewRateLimiter creates a rate limiter. anonymous applies per client IP and
+// keyed per API key. admit, if set, runs for every request the limiter lets
+// through (APIKeyAuth.CountUsage) and aborts it by returning false, so
+// throttled requests never consume quota. A nil store disables limiting.
+func NewRateLimiter(store ratelimit.Store, anonymous, keyed ratelimit.Limit, admit func(*gin.Context) bool) *RateLimiter {
+	return &RateLimiter{store: store, anonymous: anonymous, keyed: keyed, admit: admit}
+}
+
+// Cost returns a middleware that takes cost tokens from the client's bucket.
+// It must run after APIKeyAuth.Authenticate so keyed clients are recognized.
+func (l *RateLimiter) Cost(cost float64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if l.store == nil {
+			l.next(c)
+			return
+		}
+
+		limit, bucketKey := l.anonymous, "ip:"+c.ClientIP()
+		if key := KeyFromContext(c); key != nil {
+			limit, bucketKey = l.keyed, "key:"+key.KeyID
+		}
+
+		res, err := l.store.Take(c.Request.Context(), bucketKey, cost, limit)
+		if err != nil {
+			// Fail open: an unavailable store must not take the API down
+			log.Printf("Rate limit store error for %s: %v", bucketKey, err)
+			l.next(c)
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.FormatFloat(limit.Burst, 'f', -1, 64))
+		c.Header("RateLimit-Remaining", strconv.FormatFloat(math.Floor(res.Remaining), 'f', -1, 64))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+
+		if !res.Allowed {
+			c.Header("Retry-After", strconv.Itoa(max(ceilSeconds(res.RetryAfter), 1)))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Rate limit exceeded"})
+			return
+		}
+		l.next(c)
+	}
+}
+
+// next passes an admitted request on to the admit hook and the handler
+func (l *RateLimiter) next(c *gin.Context) {
+	if l.admit != nil && !l.admit(c) {
+		return
+	}
+	c.Next()
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Snapshot verdicts of the day-over-day anomaly guard
+const (
+	VerdictFlagged  = "flagged"  // imported, but looks implausible
+	VerdictRejected = "rejected" // quarantined instead of imported
+)
+
+// SnapshotComparison holds day-over-day metrics of a brand snapshot against
+// the previous accepted snapshot
+type SnapshotComparison struct {
+	PreviousDate      string   `json:"previousDate" bson:"previousDate"`
+	PreviousRowCount  int      `json:"previousRowCount" bson:"previousRowCount"`
+	RowCount          int      `json:"rowCount" bson:"rowCount"`
+	RowCountChange    float64  `json:"rowCountChange" bson:"rowCountChange"` // fraction, -0.5 = halved
+	PreviousMedian    float64  `json:"previousMedian" bson:"previousMedian"`
+	Median            float64  `json:"median" bson:"median"`
+	MedianPriceChange float64  `json:"medianPriceChange" bson:"medianPriceChange"` // fraction
+	VanishedVariants  int      `json:"vanishedVariants" bson:"vanishedVariants"`
+	VanishedShare     float64  `json:"vanishedShare" bson:"vanishedShare"` // fraction of previous variants
+	Violations        []string `json:"violations" bson:"violations"`
+}
+
+// SnapshotVerdict is an anomaly guard decision (import_verdicts collection)
+type SnapshotVerdict struct {
+	BrandID    string             `json:"brandId" bson:"brandId"`
+	Date       string             `json:"date" bson:"date"`
+	Path       string             `json:"path" bson:"path"`
+	Verdict    string             `json:"verdict" bson:"verdict"`
+	Comparison SnapshotComparison `json:"comparison" bson:"comparison"`
+	CheckedAt  time.Time          `json:"checkedAt" bson:"checkedAt"`
+}
+
+// AnomaliesData is the anomaly report response, shaped like ErrorLogResponse
+type AnomaliesData struct {
+	GeneratedAt string            `json:"generatedAt"`
+	Verdicts    []SnapshotVerdict `json:"verdicts"`
+	Summary     struct {
+		Total     int            `json:"total"`
+		ByVerdict map[string]int `json:"byVerdict"`
+		ByBrand   map[string]int `json:"byBrand"`
+	} `json:"summary"`
+}
@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// API key scopes
+const (
+	ScopeReadVehicles = "vehicles:read"
+	ScopeReadIntel    = "intel:read"
+	ScopeAdmin        = "admin"
+)
+
+// AllScopes lists every scope a key can be granted
+var AllScopes = []string{ScopeReadVehicles, ScopeReadIntel, ScopeAdmin}
+
+// APIKey is the MongoDB document for the api_keys collection.
+// Only the SHA-256 hash of the key is stored; the plaintext is shown once at creation.
+type APIKey struct {
+	KeyID      string     `json:"keyId" bson:"keyId"`
+	Name       string     `json:"name" bson:"name"`
+	Hash       string     `json:"-" bson:"hash"`
+	Scopes     []string   `json:"scopes" bson:"scopes"`
+	DailyQuota int        `json:"dailyQuota" bson:"dailyQuota"` // 0 means unlimited
+	CreatedAt  time.Time  `json:"createdAt" bson:"createdAt"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty" bson:"revokedAt,omitempty"`
+}
+
+// HasScope reports whether the key grants scope. The admin scope grants everything.
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// Revoked reports whether the key has been revoked
+func (k *APIKey) Revoked() bool {
+	return k.RevokedAt != nil
+}
+
+// APIKeyUsage is the daily request counter of a key (api_key_usage collection)
+type APIKeyUsage struct {
+	KeyID string `json:"keyId" bson:"keyId"`
+	Date  string `json:"date" bson:"date"` // YYYY-MM-DD (UTC)
+	Count int64  `json:"count" bson:"count"`
+}
+
+// APIKeySummary is an API key with its usage for the admin endpoint
+type APIKeySummary struct {
+	APIKey
+	UsageToday int64 `json:"usageToday"`
+}
+
+// APIKeyUsageResponse is the admin usage report
+type APIKeyUsageResponse struct {
+	From  string        `json:"from"`
+	To    string        `json:"to"`
+	Usage []APIKeyUsage `json:"usage"`
+}
+
+// APIKeyListResponse is the admin key listing
+type APIKeyListResponse struct {
+	Keys []APIKeySummary `json:"keys"`
+}
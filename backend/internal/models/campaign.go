@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// How a campaign episode was detected
+const (
+	CampaignKindListed = "listed" // a campaign price below the list price
+	CampaignKindDip    = "dip"    // a temporary price dip that recovered
+)
+
+// Campaign is one campaign episode of a variant (campaigns collection). An
+// episode runs over consecutive snapshots in which the variant is discounted.
+type Campaign struct {
+	ID        string `json:"id" bson:"_id"` // variantId/kind/start
+	BrandID   string `json:"brandId" bson:"brandId"`
+	Brand     string `json:"brand" bson:"brand"`
+	VariantID string `json:"variantId" bson:"variantId"`
+	Model     string `json:"model" bson:"model"`
+	Trim      string `json:"trim" bson:"trim"`
+	Engine    string `json:"engine" bson:"engine"`
+	Kind      string `json:"kind" bson:"kind"`
+
+	Start        string `json:"start" bson:"start"`                         // first discounted snapshot
+	End          string `json:"end" bson:"end"`                             // last discounted snapshot
+	EndedOn      string `json:"endedOn,omitempty" bson:"endedOn,omitempty"` // first snapshot back at full price
+	Active       bool   `json:"active" bson:"active"`                       // still discounted in the brand's newest snapshot
+	DurationDays int    `json:"durationDays" bson:"durationDays"`           // start to endedOn, or through end when unknown
+	Snapshots    int    `json:"snapshots" bson:"snapshots"`
+
+	ReferencePrice  float64   `json:"referencePrice" bson:"referencePrice"` // list price, or the price before a dip
+	LowestPrice     float64   `json:"lowestPrice" bson:"lowestPrice"`
+	AvgDepthPercent float64   `json:"avgDepthPercent" bson:"avgDepthPercent"`
+	MaxDepthPercent float64   `json:"maxDepthPercent" bson:"maxDepthPercent"`
+	DetectedAt      time.Time `json:"detectedAt" bson:"detectedAt"`
+}
+
+// BrandCampaignStats summarizes a brand's campaign episodes
+type BrandCampaignStats struct {
+	BrandID            string  `json:"brandId"`
+	Campaigns          int     `json:"campaigns"`
+	Active             int     `json:"active"`
+	AvgDurationDays    float64 `json:"avgDurationDays"`
+	MedianDurationDays float64 `json:"medianDurationDays"`
+	AvgDepthPercent    float64 `json:"avgDepthPercent"`
+}
+
+// CampaignsData is the campaign tracker response
+type CampaignsData struct {
+	Total     int                  `json:"total"` // episodes matching the filters, before limit
+	Brands    []BrandCampaignStats `json:"brands"`
+	Campaigns []Campaign           `json:"campaigns"` // newest start first
+}
@@ -0,0 +1,40 @@
+package models
+
+// CoverageDay is one calendar day of a brand's coverage calendar
+type CoverageDay struct {
+	Date     string `json:"date"`
+	Present  bool   `json:"present"`
+	RowCount int    `json:"rowCount"` // 0 when missing
+}
+
+// CoverageGap is a run of consecutive missing days
+type CoverageGap struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Days    int    `json:"days"`
+	Ongoing bool   `json:"ongoing"` // the gap reaches the end of the window
+}
+
+// BrandCoverage describes which days of the window have a snapshot for a brand.
+// The window starts no earlier than the brand's first snapshot.
+type BrandCoverage struct {
+	Name         string        `json:"name"`
+	FirstDate    string        `json:"firstDate"` // first snapshot ever
+	From         string        `json:"from"`
+	To           string        `json:"to"`
+	Days         int           `json:"days"`
+	PresentDays  int           `json:"presentDays"`
+	MissingDays  int           `json:"missingDays"`
+	CoveragePct  float64       `json:"coveragePct"`
+	LongestGap   *CoverageGap  `json:"longestGap"`
+	Gaps         []CoverageGap `json:"gaps"`
+	MissingDates []string      `json:"missingDates"`
+	Calendar     []CoverageDay `json:"calendar"`
+}
+
+// CoverageData is the coverage calendar response
+type CoverageData struct {
+	From   string                   `json:"from"`
+	To     string                   `json:"to"`
+	Brands map[string]BrandCoverage `json:"brands"`
+}
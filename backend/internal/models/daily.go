@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// DailyRow is a variant's row on one calendar day. Filled rows were not
+// observed that day and are carried forward from the snapshot of SourceDate.
+type DailyRow struct {
+	PriceListRow `bson:",inline"`
+	Filled       bool   `json:"filled" bson:"filled"`
+	SourceDate   string `json:"sourceDate" bson:"sourceDate"`
+}
+
+// DailyDocument is one brand's forward-filled day (vehicles_daily collection)
+type DailyDocument struct {
+	BrandID     string     `json:"brandId" bson:"brandId"`
+	Brand       string     `json:"brand" bson:"brand"`
+	Date        string     `json:"date" bson:"date"`
+	CollectedAt string     `json:"collectedAt" bson:"collectedAt"` // of the newest source snapshot
+	Filled      bool       `json:"filled" bson:"filled"`           // no snapshot was collected this day
+	RowCount    int        `json:"rowCount" bson:"rowCount"`
+	FilledRows  int        `json:"filledRows" bson:"filledRows"`
+	Rows        []DailyRow `json:"rows" bson:"rows"`
+	BuiltAt     time.Time  `json:"builtAt" bson:"builtAt"`
+}
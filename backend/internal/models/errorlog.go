@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// LoggedError is one collector error (error_log collection), as written to
+// data/errors.json by scripts/lib/errorLogger.ts
+type LoggedError struct {
+	ID             string         `json:"id" bson:"id"`
+	Timestamp      string         `json:"timestamp" bson:"timestamp"`
+	Severity       string         `json:"severity" bson:"severity"`
+	Category       string         `json:"category" bson:"category"`
+	Source         string         `json:"source" bson:"source"`
+	Brand          string         `json:"brand,omitempty" bson:"brand,omitempty"`
+	BrandID        string         `json:"brandId,omitempty" bson:"brandId,omitempty"`
+	Code           string         `json:"code" bson:"code"`
+	Message        string         `json:"message" bson:"message"`
+	Details        map[string]any `json:"details,omitempty" bson:"details,omitempty"`
+	Stack          string         `json:"stack,omitempty" bson:"stack,omitempty"`
+	Recovered      bool           `json:"recovered" bson:"recovered"`
+	RecoveryMethod string         `json:"recoveryMethod,omitempty" bson:"recoveryMethod,omitempty"`
+
+	LoggedAt  time.Time  `json:"-" bson:"loggedAt"`            // parsed Timestamp, for range queries
+	ExpiresAt *time.Time `json:"-" bson:"expiresAt,omitempty"` // TTL; nil keeps the entry
+}
+
+// ErrorCount is the number of errors logged on one day
+type ErrorCount struct {
+	Date       string         `json:"date"`
+	Total      int            `json:"total"`
+	BySeverity map[string]int `json:"bySeverity"`
+}
+
+// ErrorLogResponse is a page of the error log over the window [from, to).
+// clearedAt is the start of the window; the errors page still renders it.
+type ErrorLogResponse struct {
+	GeneratedAt string        `json:"generatedAt"`
+	ClearedAt   string        `json:"clearedAt"`
+	From        string        `json:"from"`
+	To          string        `json:"to"`
+	Page        int           `json:"page"`
+	PageSize    int           `json:"pageSize"`
+	Errors      []LoggedError `json:"errors"`
+	Summary     struct {
+		Total      int            `json:"total"`
+		ByCategory map[string]int `json:"byCategory"`
+		BySeverity map[string]int `json:"bySeverity"`
+		BySource   map[string]int `json:"bySource"`
+		ByBrand    map[string]int `json:"byBrand"`
+	} `json:"summary"`
+	Timeline []ErrorCount `json:"timeline"`
+}
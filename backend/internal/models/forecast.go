@@ -0,0 +1,51 @@
+package models
+
+// Forecast model names
+const (
+	ForecastDrift = "drift" // random walk with drift
+	ForecastHolt  = "holt"  // exponential smoothing with a trend
+	ForecastStep  = "step"  // flat price with jumps at the historical rate and size
+)
+
+// ForecastPoint is a forecast day with its prediction interval
+type ForecastPoint struct {
+	Date  string  `json:"date"`
+	Price float64 `json:"price"`
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+}
+
+// ForecastBacktest is a model's error on history: forecasts from rolling
+// origins compared with the prices that followed
+type ForecastBacktest struct {
+	Origins  int     `json:"origins"`
+	Horizon  int     `json:"horizon"` // days forecast from each origin
+	MAE      float64 `json:"mae"`
+	MAPE     float64 `json:"mape"`     // percent
+	Coverage float64 `json:"coverage"` // percent of actual prices inside the interval
+}
+
+// ForecastModel is one fitted model's forecast
+type ForecastModel struct {
+	Name     string             `json:"name"`
+	Params   map[string]float64 `json:"params"` // fitted parameters
+	Points   []ForecastPoint    `json:"points"`
+	Backtest *ForecastBacktest  `json:"backtest,omitempty"` // nil when the history is too short
+	// Step model: chance in percent of at least one price change within the
+	// horizon
+	ChangeProbability *float64 `json:"changeProbability,omitempty"`
+}
+
+// ForecastResponse is the forecast endpoint response. The series is the
+// variant's daily price, carried forward over days without a snapshot.
+type ForecastResponse struct {
+	VariantID string          `json:"variantId"`
+	From      string          `json:"from"`
+	To        string          `json:"to"`
+	Days      int             `json:"days"` // length of the daily series
+	LastPrice float64         `json:"lastPrice"`
+	Horizon   int             `json:"horizon"`
+	Level     int             `json:"level"`          // interval coverage in percent
+	Best      string          `json:"best,omitempty"` // lowest backtest MAPE
+	Models    []ForecastModel `json:"models"`
+}
@@ -0,0 +1,51 @@
+package models
+
+// Dimensions of the gap analyzer, besides the price band every cell has
+const (
+	GapDimSegment      = "segment"      // segment code, e.g. C-SUV
+	GapDimSize         = "size"         // segment size, A–F
+	GapDimBody         = "body"         // body type, e.g. SUV
+	GapDimFuel         = "fuel"         // canonical fuel
+	GapDimTransmission = "transmission" // canonical transmission
+	GapDimDriveType    = "driveType"    // canonical drive type
+	GapDimSeating      = "seating"      // total seats, e.g. 8+1 counts as 9
+	GapDimEVRange      = "evRange"      // WLTP range bucket of electric vehicles
+)
+
+// GapDimensions lists the gap analyzer dimensions
+var GapDimensions = []string{
+	GapDimSegment, GapDimSize, GapDimBody, GapDimFuel, GapDimTransmission,
+	GapDimDriveType, GapDimSeating, GapDimEVRange,
+}
+
+// GapAnalysisCell is one cell of a custom gap heatmap: a combination of
+// dimension values within a price band
+type GapAnalysisCell struct {
+	Values           map[string]string `json:"values"` // dimension → value
+	PriceRange       string            `json:"priceRange"`
+	PriceRangeMin    float64           `json:"priceRangeMin"`
+	PriceRangeMax    float64           `json:"priceRangeMax"` // 0 for the open top band
+	VehicleCount     int               `json:"vehicleCount"`
+	Brands           []string          `json:"brands"`
+	AvgPrice         float64           `json:"avgPrice"`
+	HasGap           bool              `json:"hasGap"`
+	OpportunityScore float64           `json:"opportunityScore"` // 0–100, gaps only
+}
+
+// GapAnalysis is a gap heatmap over user-chosen dimensions and price bands.
+// Cells span every combination of the values seen in the market.
+type GapAnalysis struct {
+	Date        string           `json:"date"` // market date: newest snapshot date used
+	Dimensions  []string         `json:"dimensions"`
+	PriceRanges []PriceRangeInfo `json:"priceRanges"`
+	RangeBands  []PriceRangeInfo `json:"rangeBands,omitempty"` // EV range buckets in km, with evRange
+	Vehicles    int              `json:"vehicles"`             // vehicles placed in cells
+	Excluded    int              `json:"excluded"`             // vehicles below the first band or lacking a value
+	Summary     struct {
+		TotalCells          int     `json:"totalCells"`
+		TotalGaps           int     `json:"totalGaps"`
+		AvgOpportunityScore float64 `json:"avgOpportunityScore"`
+	} `json:"summary"`
+	Cells            []GapAnalysisCell `json:"cells"`
+	TopOpportunities []GapAnalysisCell `json:"topOpportunities"`
+}
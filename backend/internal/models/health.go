@@ -0,0 +1,57 @@
+package models
+
+// CollectionHealthReport is data/health-report.json as written by the collector
+type CollectionHealthReport struct {
+	GeneratedAt  string                  `json:"generatedAt"`
+	Date         string                  `json:"date"`
+	TotalBrands  int                     `json:"totalBrands"`
+	Successful   int                     `json:"successful"`
+	Failed       int                     `json:"failed"`
+	UsedFallback int                     `json:"usedFallback"`
+	Details      []CollectionHealthEntry `json:"details"`
+}
+
+// CollectionHealthEntry is the collection outcome of one brand on one date
+// (collection_health collection)
+type CollectionHealthEntry struct {
+	Date         string  `json:"date" bson:"date"`
+	GeneratedAt  string  `json:"generatedAt" bson:"generatedAt"`
+	Brand        string  `json:"brand" bson:"brand"`
+	Success      bool    `json:"success" bson:"success"`
+	Count        int     `json:"count" bson:"count"`
+	Error        *string `json:"error" bson:"error"`
+	UsedFallback bool    `json:"usedFallback" bson:"usedFallback"`
+	Elapsed      int64   `json:"elapsed" bson:"elapsed"` // milliseconds
+}
+
+// LatencyPercentiles summarizes collection run times in milliseconds
+type LatencyPercentiles struct {
+	P50 int64 `json:"p50"`
+	P90 int64 `json:"p90"`
+	P99 int64 `json:"p99"`
+	Max int64 `json:"max"`
+}
+
+// BrandCollectionHealth summarizes one brand's collection runs over a window.
+// A run only counts as fresh when it succeeded without falling back to stale data.
+type BrandCollectionHealth struct {
+	Brand          string             `json:"brand"`
+	Reports        int                `json:"reports"`        // dates with a health report entry
+	MissingReports int                `json:"missingReports"` // dates in the window without one
+	Successes      int                `json:"successes"`
+	Fallbacks      int                `json:"fallbacks"`
+	SuccessRate    float64            `json:"successRate"`
+	FallbackRate   float64            `json:"fallbackRate"`
+	Latency        LatencyPercentiles `json:"latency"`
+	LastSuccess    *string            `json:"lastSuccess"`   // last fresh date, may precede the window
+	FailureStreak  int                `json:"failureStreak"` // latest consecutive reports without fresh data
+	LastError      *string            `json:"lastError"`
+}
+
+// CollectionHealthData is the collection health response
+type CollectionHealthData struct {
+	From   string                  `json:"from"`
+	To     string                  `json:"to"`
+	Days   int                     `json:"days"`
+	Brands []BrandCollectionHealth `json:"brands"`
+}
@@ -37,6 +37,8 @@ type PriceEvent struct {
 	PriceChangePercent  *float64 `json:"priceChangePercent,omitempty" bson:"priceChangePercent,omitempty"`
 	Date                string   `json:"date" bson:"date"`
 	PreviousDate        *string  `json:"previousDate,omitempty" bson:"previousDate,omitempty"`
+
+	Segment *Segment `json:"segment,omitempty" bson:"-"` // classified when serving
 }
 
 type VolatilityMetric struct {
@@ -77,6 +79,8 @@ type TrimStep struct {
 	Engine         string  `json:"engine" bson:"engine"`
 	Transmission   string  `json:"transmission" bson:"transmission"`
 	Fuel           string  `json:"fuel" bson:"fuel"`
+
+	VariantID string `json:"variantId,omitempty" bson:"variantId,omitempty"` // set on ladders computed live
 }
 
 type TrimLadder struct {
@@ -90,6 +94,8 @@ type TrimLadder struct {
 	PriceSpread        float64    `json:"priceSpread" bson:"priceSpread"`
 	PriceSpreadPercent float64    `json:"priceSpreadPercent" bson:"priceSpreadPercent"`
 	TrimCount          int        `json:"trimCount" bson:"trimCount"`
+
+	Segment *Segment `json:"segment,omitempty" bson:"-"` // classified when serving
 }
 
 type CrossBrandEntry struct {
@@ -123,6 +129,45 @@ type ArchitectureData struct {
 	} `json:"summary" bson:"summary"`
 }
 
+// ModelArchitecture is a model's trim ladder computed live for a date, the
+// same-segment models of other brands and, when asked for, how the ladder
+// changed since an earlier date
+type ModelArchitecture struct {
+	Date        string            `json:"date"`        // the brand's snapshot date
+	Ladder      TrimLadder        `json:"ladder"`
+	Competitors []CrossBrandEntry `json:"competitors"` // same segment, cheapest base price first
+	Change      *LadderChange     `json:"change,omitempty"`
+}
+
+// LadderChange compares a model's ladder on two dates. Trims are matched by
+// variant id; Previous is empty when the model was not listed on From.
+type LadderChange struct {
+	From                string           `json:"from"` // the brand's snapshot date, empty without one
+	To                  string           `json:"to"`
+	Previous            TrimLadder       `json:"previous"`
+	BasePriceChange     float64          `json:"basePriceChange"`
+	TopPriceChange      float64          `json:"topPriceChange"`
+	SpreadChange        float64          `json:"spreadChange"`
+	SpreadPercentChange float64          `json:"spreadPercentChange"` // percentage points
+	Steps               []TrimStepChange `json:"steps"`               // trims listed on both dates, in ladder order
+	Added               []TrimStep       `json:"added"`
+	Removed             []TrimStep       `json:"removed"`
+}
+
+// TrimStepChange is one trim's price and step from base on two dates
+type TrimStepChange struct {
+	VariantID          string  `json:"variantId"`
+	Trim               string  `json:"trim"`
+	Engine             string  `json:"engine"`
+	FromPrice          float64 `json:"fromPrice"`
+	ToPrice            float64 `json:"toPrice"`
+	PriceChange        float64 `json:"priceChange"`
+	PriceChangePercent float64 `json:"priceChangePercent"`
+	FromStepPercent    float64 `json:"fromStepPercent"`
+	ToStepPercent      float64 `json:"toStepPercent"`
+	StepPercentChange  float64 `json:"stepPercentChange"` // percentage points
+}
+
 // === Gaps Data ===
 
 type GapCell struct {
@@ -199,6 +244,8 @@ type PriceDrop struct {
 	CampaignPrice         *float64       `json:"campaignPrice,omitempty" bson:"campaignPrice,omitempty"`
 	CampaignDiscount      *float64       `json:"campaignDiscount,omitempty" bson:"campaignDiscount,omitempty"`
 	OtvRate               *float64       `json:"otvRate,omitempty" bson:"otvRate,omitempty"`
+
+	Segment *Segment `json:"segment,omitempty" bson:"-"` // classified when serving
 }
 
 type RecentDrop struct {
@@ -218,6 +265,8 @@ type RecentDrop struct {
 	CampaignPrice    *float64 `json:"campaignPrice,omitempty" bson:"campaignPrice,omitempty"`
 	CampaignDiscount *float64 `json:"campaignDiscount,omitempty" bson:"campaignDiscount,omitempty"`
 	OtvRate          *float64 `json:"otvRate,omitempty" bson:"otvRate,omitempty"`
+
+	Segment *Segment `json:"segment,omitempty" bson:"-"` // classified when serving
 }
 
 type BrandDropSummary struct {
@@ -257,6 +306,8 @@ type ModelYearTransition struct {
 	PriceDeltaPercent float64 `json:"priceDeltaPercent" bson:"priceDeltaPercent"`
 	TransitionDate   string  `json:"transitionDate" bson:"transitionDate"`
 	TrimCount        int     `json:"trimCount" bson:"trimCount"`
+
+	Segment *Segment `json:"segment,omitempty" bson:"-"` // classified when serving
 }
 
 type EntryPriceDelta struct {
@@ -271,6 +322,8 @@ type EntryPriceDelta struct {
 	CurrentDate        string  `json:"currentDate" bson:"currentDate"`
 	PreviousDate       string  `json:"previousDate" bson:"previousDate"`
 	DaysBetween        int     `json:"daysBetween" bson:"daysBetween"`
+
+	Segment *Segment `json:"segment,omitempty" bson:"-"` // classified when serving
 }
 
 type StaleModel struct {
@@ -282,6 +335,8 @@ type StaleModel struct {
 	DaysSinceUpdate  int     `json:"daysSinceUpdate" bson:"daysSinceUpdate"`
 	CurrentEntryPrice float64 `json:"currentEntryPrice" bson:"currentEntryPrice"`
 	TrimCount        int     `json:"trimCount" bson:"trimCount"`
+
+	Segment *Segment `json:"segment,omitempty" bson:"-"` // classified when serving
 }
 
 type ModelInfo struct {
@@ -293,6 +348,8 @@ type ModelInfo struct {
 	TrimCount   int      `json:"trimCount" bson:"trimCount"`
 	FuelTypes   []string `json:"fuelTypes" bson:"fuelTypes"`
 	LastUpdated string   `json:"lastUpdated" bson:"lastUpdated"`
+
+	Segment *Segment `json:"segment,omitempty" bson:"-"` // classified when serving
 }
 
 type LifecycleData struct {
@@ -310,24 +367,60 @@ type LifecycleData struct {
 	AllModels            []ModelInfo           `json:"allModels" bson:"allModels"`
 }
 
-// === Errors Data ===
+// === Insights Data ===
 
-type ErrorEntry struct {
-	Timestamp string `json:"timestamp" bson:"timestamp"`
-	Category  string `json:"category" bson:"category"`
-	Severity  string `json:"severity" bson:"severity"`
-	Source    string `json:"source" bson:"source"`
-	Message   string `json:"message" bson:"message"`
+type VehicleWithScore struct {
+	ID             string  `json:"id" bson:"id"`
+	Brand          string  `json:"brand" bson:"brand"`
+	BrandID        string  `json:"brandId" bson:"brandId"`
+	Model          string  `json:"model" bson:"model"`
+	Trim           string  `json:"trim" bson:"trim"`
+	Engine         string  `json:"engine" bson:"engine"`
+	Fuel           string  `json:"fuel" bson:"fuel"`
+	Transmission   string  `json:"transmission" bson:"transmission"`
+	VehicleClass   string  `json:"vehicleClass" bson:"vehicleClass"`
+	PriceBand      string  `json:"priceBand" bson:"priceBand"`
+	Price          float64 `json:"price" bson:"price"`
+	PriceFormatted string  `json:"priceFormatted" bson:"priceFormatted"`
+	DealScore      float64 `json:"dealScore" bson:"dealScore"`
+	ZScore         float64 `json:"zScore" bson:"zScore"`
+	Percentile     float64 `json:"percentile" bson:"percentile"`
+	SegmentAvg     float64 `json:"segmentAvg" bson:"segmentAvg"`
+	SegmentSize    int     `json:"segmentSize" bson:"segmentSize"`
+	IsOutlier      bool    `json:"isOutlier" bson:"isOutlier"`
+	OutlierType    *string `json:"outlierType" bson:"outlierType"` // cheap, expensive or null
+
+	CampaignDiscount   *float64    `json:"campaignDiscount,omitempty" bson:"campaignDiscount,omitempty"`
+	OtvRate            *float64    `json:"otvRate,omitempty" bson:"otvRate,omitempty"`
+	ModelYear          interface{} `json:"modelYear,omitempty" bson:"modelYear,omitempty"` // number | string
+	FuelConsumption    *string     `json:"fuelConsumption,omitempty" bson:"fuelConsumption,omitempty"`
+	MonthlyLease       *float64    `json:"monthlyLease,omitempty" bson:"monthlyLease,omitempty"`
+	PowerHP            *float64    `json:"powerHP,omitempty" bson:"powerHP,omitempty"`
+	PowerKW            *float64    `json:"powerKW,omitempty" bson:"powerKW,omitempty"`
+	EngineDisplacement *string     `json:"engineDisplacement,omitempty" bson:"engineDisplacement,omitempty"`
+	DriveType          *string     `json:"driveType,omitempty" bson:"driveType,omitempty"`
+	WltpRange          *float64    `json:"wltpRange,omitempty" bson:"wltpRange,omitempty"`
+	BatteryCapacity    *float64    `json:"batteryCapacity,omitempty" bson:"batteryCapacity,omitempty"`
+	HasLongRange       *bool       `json:"hasLongRange,omitempty" bson:"hasLongRange,omitempty"`
+	IsMildHybrid       *bool       `json:"isMildHybrid,omitempty" bson:"isMildHybrid,omitempty"`
+	IsPlugInHybrid     *bool       `json:"isPlugInHybrid,omitempty" bson:"isPlugInHybrid,omitempty"`
+	IsElectric         *bool       `json:"isElectric,omitempty" bson:"isElectric,omitempty"`
+	IsHybrid           *bool       `json:"isHybrid,omitempty" bson:"isHybrid,omitempty"`
+	TlPerHP            *float64    `json:"tlPerHP,omitempty" bson:"tlPerHP,omitempty"`
+	TlPerKm            *float64    `json:"tlPerKm,omitempty" bson:"tlPerKm,omitempty"`
+
+	Segment *Segment `json:"segment,omitempty" bson:"-"` // classified when serving
+
+	// Set by the Go scoring engine (/insights/score)
+	PeerKey    string           `json:"peerKey,omitempty" bson:"-"`
+	Components *ScoreComponents `json:"components,omitempty" bson:"-"`
 }
 
-type ErrorsData struct {
-	GeneratedAt string `json:"generatedAt" bson:"generatedAt"`
-	ClearedAt   string `json:"clearedAt" bson:"clearedAt"`
-	Errors      []ErrorEntry `json:"errors" bson:"errors"`
-	Summary     struct {
-		Total      int            `json:"total" bson:"total"`
-		ByCategory map[string]int `json:"byCategory" bson:"byCategory"`
-		BySeverity map[string]int `json:"bySeverity" bson:"bySeverity"`
-		BySource   map[string]int `json:"bySource" bson:"bySource"`
-	} `json:"summary" bson:"summary"`
+type InsightsData struct {
+	GeneratedAt       string             `json:"generatedAt" bson:"generatedAt"`
+	Date              string             `json:"date" bson:"date"`
+	TopDeals          []VehicleWithScore `json:"topDeals" bson:"topDeals"`
+	CheapOutliers     []VehicleWithScore `json:"cheapOutliers" bson:"cheapOutliers"`
+	ExpensiveOutliers []VehicleWithScore `json:"expensiveOutliers" bson:"expensiveOutliers"`
+	AllVehicles       []VehicleWithScore `json:"allVehicles" bson:"allVehicles"`
 }
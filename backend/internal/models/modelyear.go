@@ -0,0 +1,40 @@
+package models
+
+// ModelYearChange is a model's switch from one model year to the next,
+// detected over the snapshot history rather than the latest snapshot alone.
+// cmd/modelyears stores them in the model_year_changes collection.
+type ModelYearChange struct {
+	ID      string `json:"id" bson:"_id"` // brandId-model-oldYear-newYear
+	Brand   string `json:"brand" bson:"brand"`
+	BrandID string `json:"brandId" bson:"brandId"`
+	Model   string `json:"model" bson:"model"` // without the year
+	OldYear string `json:"oldYear" bson:"oldYear"`
+	NewYear string `json:"newYear" bson:"newYear"`
+
+	NewYearFirstSeen string `json:"newYearFirstSeen" bson:"newYearFirstSeen"` // first snapshot listing the new year
+	OldYearLastSeen  string `json:"oldYearLastSeen" bson:"oldYearLastSeen"`   // last snapshot listing the old year
+	OverlapDays      int    `json:"overlapDays" bson:"overlapDays"`           // days both years were listed, 0 for a clean cut-over
+	Ongoing          bool   `json:"ongoing" bson:"ongoing"`                   // both years in the brand's newest snapshot
+
+	OldEntryPrice     float64 `json:"oldEntryPrice" bson:"oldEntryPrice"` // old year's entry price just before the new year appeared
+	NewEntryPrice     float64 `json:"newEntryPrice" bson:"newEntryPrice"` // new year's entry price when it appeared
+	PriceDelta        float64 `json:"priceDelta" bson:"priceDelta"`
+	PriceDeltaPercent float64 `json:"priceDeltaPercent" bson:"priceDeltaPercent"`
+
+	// Run-out: how far the old year was cut while both years were listed,
+	// per variant against its price before the new year appeared
+	RunOutEntryPrice float64 `json:"runOutEntryPrice" bson:"runOutEntryPrice"` // lowest old-year entry price during the overlap
+	RunOutVariants   int     `json:"runOutVariants" bson:"runOutVariants"`     // old-year variants cut during the overlap
+	RunOutAvgPercent float64 `json:"runOutAvgPercent" bson:"runOutAvgPercent"` // average cut of those variants
+	RunOutMaxPercent float64 `json:"runOutMaxPercent" bson:"runOutMaxPercent"`
+	OldTrimCount     int     `json:"oldTrimCount" bson:"oldTrimCount"`
+	NewTrimCount     int     `json:"newTrimCount" bson:"newTrimCount"`
+
+	Segment *Segment `json:"segment,omitempty" bson:"segment,omitempty"`
+}
+
+// ModelYearChangesData is the model-year transition detector response
+type ModelYearChangesData struct {
+	Total       int               `json:"total"`
+	Transitions []ModelYearChange `json:"transitions"` // newest first
+}
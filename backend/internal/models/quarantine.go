@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// QuarantineEntry is a data file rejected at import time (quarantine collection).
+// The raw document is kept in MongoDB for inspection but not served by the API.
+type QuarantineEntry struct {
+	Path          string    `json:"path" bson:"path"` // relative to the data directory
+	BrandID       string    `json:"brandId" bson:"brandId"`
+	Date          string    `json:"date" bson:"date"`
+	Hash          string    `json:"hash" bson:"hash"`
+	Reasons       []string  `json:"reasons" bson:"reasons"`
+	QuarantinedAt time.Time `json:"quarantinedAt" bson:"quarantinedAt"`
+}
+
+// QuarantineResponse is the admin quarantine view
+type QuarantineResponse struct {
+	Total   int64             `json:"total"`
+	ByBrand map[string]int    `json:"byBrand"`
+	Entries []QuarantineEntry `json:"entries"`
+}
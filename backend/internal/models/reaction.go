@@ -0,0 +1,53 @@
+package models
+
+// PriceMove is a brand's repricing on one date: the price events of its
+// variants in scope, netted into one direction
+type PriceMove struct {
+	BrandID          string  `json:"brandId"`
+	Brand            string  `json:"brand"`
+	Date             string  `json:"date"`
+	Direction        string  `json:"direction"` // up or down
+	Variants         int     `json:"variants"`
+	AvgChangePercent float64 `json:"avgChangePercent"`
+	LagDays          int     `json:"lagDays,omitempty"` // responses: days after the move answered, 0 on the same day
+}
+
+// MoveReaction is one move of the analyzed vehicle with the first move of
+// every competitor within the window
+type MoveReaction struct {
+	Move      PriceMove   `json:"move"`
+	Event     PriceEvent  `json:"event"`
+	Responses []PriceMove `json:"responses"`
+}
+
+// ReactionCell is how one brand (follower) answered another's (leader) moves
+type ReactionCell struct {
+	Leader       string  `json:"leader"`
+	Follower     string  `json:"follower"`
+	LeaderMoves  int     `json:"leaderMoves"`
+	Followed     int     `json:"followed"`     // first response 1 to window days later in the same direction
+	Countered    int     `json:"countered"`    // first response 1 to window days later in the opposite direction
+	Simultaneous int     `json:"simultaneous"` // leader moves the follower also moved on the same day
+	FollowRate   float64 `json:"followRate"`
+	AvgLagDays   float64 `json:"avgLagDays"` // of the followed moves
+	// Correlation of the two brands' daily price index changes with the
+	// follower shifted by BestLagDays, the lag in [0, window] correlating best
+	Correlation float64 `json:"correlation"`
+	BestLagDays int     `json:"bestLagDays"`
+}
+
+// ReactionAnalysis is the competitor-reaction response. Moves are detailed
+// only for a chosen vehicle.
+type ReactionAnalysis struct {
+	From           string         `json:"from"`
+	To             string         `json:"to"`
+	VehicleID      string         `json:"vehicleId,omitempty"`
+	Segment        *Segment       `json:"segment,omitempty"` // the chosen vehicle's, which sets the competitors
+	WindowDays     int            `json:"windowDays"`
+	MinMovePercent float64        `json:"minMovePercent"`
+	Brands         []string       `json:"brands"`
+	Events         int            `json:"events"` // price events in scope
+	BrandMoves     int            `json:"brandMoves"`
+	Matrix         []ReactionCell `json:"matrix"`
+	Moves          []MoveReaction `json:"moves,omitempty"`
+}
@@ -0,0 +1,45 @@
+package models
+
+// Peer groups of the deal scoring engine: the vehicles a price is compared with
+const (
+	PeerGroupSegment     = "segment"      // same segment, e.g. C-SUV
+	PeerGroupSegmentFuel = "segment_fuel" // same segment and canonical fuel
+	PeerGroupPriceBand   = "price_band"   // same price band, any body
+)
+
+// PeerGroups lists the peer groups, the default first
+var PeerGroups = []string{PeerGroupSegmentFuel, PeerGroupSegment, PeerGroupPriceBand}
+
+// ScoreWeights weigh the optional deal score components against the price
+// score, which always weighs 1. Zero leaves a component out of the score.
+type ScoreWeights struct {
+	CampaignDiscount float64 `json:"campaignDiscount"`
+	TLPerHP          float64 `json:"tlPerHP"`
+	TLPerKm          float64 `json:"tlPerKm"`
+}
+
+// ScoreComponents are the 0–100 sub-scores a deal score blends; higher is a
+// better deal. Components a vehicle lacks data for are omitted.
+type ScoreComponents struct {
+	Price            float64  `json:"price"`
+	CampaignDiscount *float64 `json:"campaignDiscount,omitempty"` // discount rank among peers
+	TLPerHP          *float64 `json:"tlPerHP,omitempty"`          // inverse TL/HP rank among peers
+	TLPerKm          *float64 `json:"tlPerKm,omitempty"`          // inverse TL/km rank among electric peers
+}
+
+// ScoreResponse is the result of an on-demand scoring run. With a vehicleId
+// it holds that vehicle and its peer group, otherwise the market-wide lists
+// of the insights document.
+type ScoreResponse struct {
+	Date      string       `json:"date"` // market date: newest snapshot date used
+	PeerGroup string       `json:"peerGroup"`
+	Weights   ScoreWeights `json:"weights"`
+
+	Vehicle *VehicleWithScore  `json:"vehicle,omitempty"`
+	Peers   []VehicleWithScore `json:"peers,omitempty"` // by deal score, best first
+
+	TopDeals          []VehicleWithScore `json:"topDeals,omitempty"`
+	CheapOutliers     []VehicleWithScore `json:"cheapOutliers,omitempty"`
+	ExpensiveOutliers []VehicleWithScore `json:"expensiveOutliers,omitempty"`
+	AllVehicles       []VehicleWithScore `json:"allVehicles,omitempty"`
+}
@@ -0,0 +1,43 @@
+package models
+
+// Segment sizes: the European A (city car) to F (luxury) classes
+const (
+	SizeA = "A"
+	SizeB = "B"
+	SizeC = "C"
+	SizeD = "D"
+	SizeE = "E"
+	SizeF = "F"
+)
+
+// SegmentSizes lists the sizes from smallest to largest
+var SegmentSizes = []string{SizeA, SizeB, SizeC, SizeD, SizeE, SizeF}
+
+// Segment body types
+const (
+	BodyHatch  = "hatch"
+	BodySedan  = "sedan"
+	BodyWagon  = "wagon"
+	BodySUV    = "SUV"
+	BodyMPV    = "MPV"
+	BodyCoupe  = "coupe"
+	BodyCabrio = "cabrio"
+	BodyLCV    = "LCV" // light commercial: vans, pickups and their passenger versions; has no size
+)
+
+// SegmentBodies lists the body types in display order
+var SegmentBodies = []string{BodyHatch, BodySedan, BodyWagon, BodySUV, BodyMPV, BodyCoupe, BodyCabrio, BodyLCV}
+
+// Segment classification sources
+const (
+	SegmentSourceTable     = "table"     // curated model table
+	SegmentSourceHeuristic = "heuristic" // category, cargo, seating and price rules
+)
+
+// Segment is a row's market segment, e.g. B-SUV, C-hatch or LCV
+type Segment struct {
+	Code   string `json:"code"`           // size-body, or LCV
+	Size   string `json:"size,omitempty"` // A–F
+	Body   string `json:"body"`
+	Source string `json:"source"`
+}
@@ -0,0 +1,47 @@
+package models
+
+// ModelStaleness is how long a model's prices have stood still, the StaleModel
+// of the lifecycle data computed over the recent snapshot history
+type ModelStaleness struct {
+	Model             string  `json:"model"`
+	LastChangeDate    string  `json:"lastChangeDate"`  // last substantive price change or new listing of any variant, empty when none was seen
+	DaysSinceChange   int     `json:"daysSinceChange"` // to the brand's newest snapshot; counted from firstDate without a change
+	Variants          int     `json:"variants"`
+	UnchangedVariants int     `json:"unchangedVariants"` // same price as the comparison snapshot
+	UnchangedPercent  float64 `json:"unchangedPercent"`
+	EntryPrice        float64 `json:"entryPrice"`
+	Stale             bool    `json:"stale"` // no change within the window
+
+	Segment *Segment `json:"segment,omitempty"`
+}
+
+// BrandStaleness describes whether a brand's price list still moves. A source
+// is frozen when neither its prices nor its rows changed within the window
+// although snapshots kept arriving.
+type BrandStaleness struct {
+	BrandID            string  `json:"brandId"`
+	Brand              string  `json:"brand"`
+	FirstDate          string  `json:"firstDate"` // first snapshot read, at most staleness.HistoryDays back
+	LatestDate         string  `json:"latestDate"`
+	CompareDate        string  `json:"compareDate,omitempty"` // newest snapshot at least the window's days before latestDate
+	LastChangeDate     string  `json:"lastChangeDate"`        // last substantive price change, empty when none was seen
+	DaysSinceChange    int     `json:"daysSinceChange"`
+	LastListChangeDate string  `json:"lastListChangeDate"` // last snapshot adding or dropping variants
+	Rows               int     `json:"rows"`               // variants in the latest snapshot
+	ComparedRows       int     `json:"comparedRows"`       // of those, listed on compareDate too
+	UnchangedRows      int     `json:"unchangedRows"`
+	UnchangedPercent   float64 `json:"unchangedPercent"` // share of compared rows with the same price
+	StaleModels        int     `json:"staleModels"`
+	Frozen             bool    `json:"frozen"`
+
+	Models []ModelStaleness `json:"models"` // longest unchanged first
+}
+
+// StalenessData is the stale price list response
+type StalenessData struct {
+	To     string           `json:"to,omitempty"`
+	Days   int              `json:"days"`
+	Total  int              `json:"total"`
+	Frozen int              `json:"frozen"`
+	Brands []BrandStaleness `json:"brands"` // frozen first, then longest unchanged
+}
@@ -18,10 +18,45 @@ type BrandStats struct {
 	MedianPrice  float64 `json:"medianPrice" bson:"medianPrice"`
 }
 
-// StatsData represents the precomputed statistics document
+// StatsData represents the precomputed statistics document. Fuel and
+// transmission buckets use the canonical vocabulary values (models.FuelPetrol,
+// models.TransmissionManual, ...; models.VocabUnknown for unmapped labels).
 type StatsData struct {
-	GeneratedAt   string       `json:"generatedAt" bson:"generatedAt"`
-	TotalVehicles int          `json:"totalVehicles" bson:"totalVehicles"`
-	OverallStats  OverallStats `json:"overallStats" bson:"overallStats"`
-	BrandStats    []BrandStats `json:"brandStats" bson:"brandStats"`
+	GeneratedAt          string                `json:"generatedAt" bson:"generatedAt"`
+	TotalVehicles        int                   `json:"totalVehicles" bson:"totalVehicles"`
+	OverallStats         OverallStats          `json:"overallStats" bson:"overallStats"`
+	BrandStats           []BrandStats          `json:"brandStats" bson:"brandStats"`
+	FuelStats            []FuelStats           `json:"fuelStats,omitempty" bson:"fuelStats,omitempty"`
+	TransmissionStats    []TransmissionStats   `json:"transmissionStats,omitempty" bson:"transmissionStats,omitempty"`
+	FuelConsumptionStats *FuelConsumptionStats `json:"fuelConsumptionStats,omitempty" bson:"fuelConsumptionStats,omitempty"`
+}
+
+// FuelStats is the vehicle count and average price of a canonical fuel
+type FuelStats struct {
+	Fuel       string  `json:"fuel" bson:"fuel"`
+	Count      int     `json:"count" bson:"count"`
+	Percentage float64 `json:"percentage" bson:"percentage"`
+	AvgPrice   float64 `json:"avgPrice" bson:"avgPrice"`
+}
+
+// TransmissionStats is the vehicle count and average price of a canonical
+// transmission
+type TransmissionStats struct {
+	Transmission string  `json:"transmission" bson:"transmission"`
+	Count        int     `json:"count" bson:"count"`
+	Percentage   float64 `json:"percentage" bson:"percentage"`
+	AvgPrice     float64 `json:"avgPrice" bson:"avgPrice"`
+}
+
+// FuelConsumption is the average consumption of a canonical fuel over the
+// vehicles that list one
+type FuelConsumption struct {
+	Fuel           string  `json:"fuel" bson:"fuel"`
+	AvgConsumption float64 `json:"avgConsumption" bson:"avgConsumption"`
+	Count          int     `json:"count" bson:"count"`
+}
+
+// FuelConsumptionStats groups consumption figures
+type FuelConsumptionStats struct {
+	ByFuel []FuelConsumption `json:"byFuel" bson:"byFuel"`
 }
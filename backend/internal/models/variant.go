@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// VariantAlias maps a normalized variant key onto the key it was renamed from
+// (or to), so both resolve to one variant id. Keys are normalized
+// model|trim|engine strings as produced by variant.Key.
+type VariantAlias struct {
+	BrandID   string    `json:"brandId" bson:"brandId"`
+	FromKey   string    `json:"fromKey" bson:"fromKey"`
+	ToKey     string    `json:"toKey" bson:"toKey"`
+	Note      string    `json:"note,omitempty" bson:"note,omitempty"`
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+}
+
+// VariantName is one label a variant was listed under, with the dates it was seen
+type VariantName struct {
+	Model     string `json:"model" bson:"model"`
+	Trim      string `json:"trim" bson:"trim"`
+	Engine    string `json:"engine" bson:"engine"`
+	Key       string `json:"key" bson:"key"`
+	FirstSeen string `json:"firstSeen" bson:"firstSeen"`
+	LastSeen  string `json:"lastSeen" bson:"lastSeen"`
+}
@@ -73,6 +73,19 @@ type PriceListRow struct {
 
 	// Mercedes-specific
 	IsAMG *bool `json:"isAMG,omitempty" bson:"isAMG,omitempty"`
+
+	// Variant identity, assigned at import (see package variant)
+	VariantKey string `json:"variantKey,omitempty" bson:"variantKey,omitempty"` // normalized model|trim|engine
+	VariantID  string `json:"variantId,omitempty" bson:"variantId,omitempty"`   // stable across renames
+
+	// Normalized fuel, transmission and drive type, assigned at import (see package vocab)
+	Canonical *CanonicalAttributes `json:"canonical,omitempty" bson:"canonical,omitempty"`
+
+	// Market segment, classified when serving (see package segment)
+	Segment *Segment `json:"segment,omitempty" bson:"-"`
+
+	// Value-for-money metrics, computed when serving (see market.Metrics)
+	Metrics *ValueMetrics `json:"metrics,omitempty" bson:"-"`
 }
 
 // StoredData represents a brand's data for a specific date (MongoDB document)
@@ -121,3 +134,57 @@ type LatestBrandData struct {
 	Date     string         `json:"date"`
 	Vehicles []PriceListRow `json:"vehicles"`
 }
+
+// TrendPoint represents a single data point in a price trend
+type TrendPoint struct {
+	Date       string  `json:"date" bson:"date"`
+	Price      float64 `json:"price" bson:"price"`
+	Filled     bool    `json:"filled,omitempty" bson:"filled,omitempty"`         // carried forward (?fill=forward)
+	SourceDate string  `json:"sourceDate,omitempty" bson:"sourceDate,omitempty"` // observation date of a filled point
+}
+
+// TrendResponse represents the trend endpoint response
+type TrendResponse struct {
+	Points    []TrendPoint  `json:"points"`
+	VariantID string        `json:"variantId,omitempty"` // identity the points were matched on
+	Lineage   []VariantName `json:"lineage,omitempty"`   // labels the variant was listed under
+}
+
+// Value-for-money metrics, by their ValueMetrics JSON names
+const (
+	MetricTLPerHP    = "tlPerHP"
+	MetricTLPerKWh   = "tlPerKWh"
+	MetricTLPerKm    = "tlPerKm"
+	MetricTLPerLiter = "tlPerLiter"
+)
+
+// ValueMetricNames lists the value-for-money metrics
+var ValueMetricNames = []string{MetricTLPerHP, MetricTLPerKWh, MetricTLPerKm, MetricTLPerLiter}
+
+// ValueMetrics are a row's price per unit of what it offers, computed when
+// serving; a metric is omitted when the row lacks the attribute
+type ValueMetrics struct {
+	TLPerHP    *float64 `json:"tlPerHP,omitempty"`    // price per horsepower
+	TLPerKWh   *float64 `json:"tlPerKWh,omitempty"`   // price per kWh of battery
+	TLPerKm    *float64 `json:"tlPerKm,omitempty"`    // price per km of WLTP range, electric vehicles
+	TLPerLiter *float64 `json:"tlPerLiter,omitempty"` // price per liter of cargo volume, LCVs
+}
+
+// RankedVehicle is one entry of a value ranking
+type RankedVehicle struct {
+	Rank      int          `json:"rank"` // 1-based; ties share a rank
+	Value     float64      `json:"value"`
+	BrandID   string       `json:"brandId"`
+	VariantID string       `json:"variantId"`
+	Date      string       `json:"date"` // snapshot date
+	Vehicle   PriceListRow `json:"vehicle"`
+}
+
+// RankingsResponse ranks the market on a date by a value metric
+type RankingsResponse struct {
+	Date    string          `json:"date"` // market date: newest snapshot date used
+	Metric  string          `json:"metric"`
+	Order   string          `json:"order"` // asc ranks the lowest price per unit first
+	Total   int             `json:"total"` // vehicles with the metric, before limit
+	Entries []RankedVehicle `json:"entries"`
+}
@@ -0,0 +1,84 @@
+package models
+
+import "time"
+
+// Vocabulary fields: raw row attributes with a canonical enum
+const (
+	VocabFuel         = "fuel"
+	VocabTransmission = "transmission"
+	VocabDriveType    = "driveType"
+)
+
+// VocabularyFields lists the vocabulary fields in display order
+var VocabularyFields = []string{VocabFuel, VocabTransmission, VocabDriveType}
+
+// Canonical fuel types
+const (
+	FuelPetrol       = "petrol"
+	FuelDiesel       = "diesel"
+	FuelLPG          = "lpg"
+	FuelHybrid       = "hybrid"
+	FuelMildHybrid   = "mild_hybrid"
+	FuelPlugInHybrid = "plug_in_hybrid"
+	FuelElectric     = "electric"
+	FuelOther        = "other"
+)
+
+// Canonical transmissions
+const (
+	TransmissionManual     = "manual"
+	TransmissionAutomatic  = "automatic"
+	TransmissionDualClutch = "dual_clutch"
+	TransmissionCVT        = "cvt"
+)
+
+// Canonical drive types
+const (
+	DriveFWD = "fwd"
+	DriveRWD = "rwd"
+	DriveAWD = "awd"
+)
+
+// VocabUnknown is the canonical value of raw values missing from the table
+const VocabUnknown = "unknown"
+
+// VocabularyValues lists the canonical values allowed per field
+var VocabularyValues = map[string][]string{
+	VocabFuel:         {FuelPetrol, FuelDiesel, FuelLPG, FuelHybrid, FuelMildHybrid, FuelPlugInHybrid, FuelElectric, FuelOther},
+	VocabTransmission: {TransmissionManual, TransmissionAutomatic, TransmissionDualClutch, TransmissionCVT},
+	VocabDriveType:    {DriveFWD, DriveRWD, DriveAWD},
+}
+
+// CanonicalAttributes holds the normalized fuel, transmission and drive type
+// of a row; the raw values stay in the row's own fields
+type CanonicalAttributes struct {
+	Fuel         string `json:"fuel,omitempty" bson:"fuel,omitempty"`
+	Transmission string `json:"transmission,omitempty" bson:"transmission,omitempty"`
+	DriveType    string `json:"driveType,omitempty" bson:"driveType,omitempty"`
+}
+
+// VocabularyEntry maps one raw value (normalized, see variant.Normalize) of a
+// field to its canonical value
+type VocabularyEntry struct {
+	Field     string     `json:"field" bson:"field"`
+	Raw       string     `json:"raw" bson:"raw"`
+	Canonical string     `json:"canonical" bson:"canonical"`
+	Source    string     `json:"source,omitempty" bson:"-"` // default or override
+	UpdatedAt *time.Time `json:"updatedAt,omitempty" bson:"updatedAt,omitempty"`
+}
+
+// VocabularyUpdate is the request body of PUT /admin/vocabulary
+type VocabularyUpdate struct {
+	Field     string `json:"field"`
+	Raw       string `json:"raw"`
+	Canonical string `json:"canonical"`
+}
+
+// VocabularyResponse is the effective mapping table with the raw values in
+// stored data that no entry covers
+type VocabularyResponse struct {
+	Entries   []VocabularyEntry   `json:"entries"`
+	Unmapped  []VocabularyEntry   `json:"unmapped"`
+	Values    map[string][]string `json:"values"`
+	Restamped *int64              `json:"restamped,omitempty"` // rows updated by an edit
+}
@@ -0,0 +1,245 @@
+// Package modelyear detects model-year transitions in the snapshot history: a
+// model whose rows start carrying a newer model year. For every transition it
+// measures the entry-price step between the years, how long both years were
+// listed side by side, and how deep the old year was cut to run it out.
+package modelyear
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spehlivan/price-list/backend/internal/market"
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"github.com/spehlivan/price-list/backend/internal/segment"
+	"github.com/spehlivan/price-list/backend/internal/variant"
+)
+
+var yearPattern = regexp.MustCompile(`\b(20\d{2})\b`)
+
+// Year returns a row's model year: the modelYear field when set, otherwise a
+// year in the model name as the lifecycle generator reads it. Name years more
+// than one year away from the snapshot date are model numbers (Peugeot 2008),
+// not years.
+func Year(row models.PriceListRow, date string) string {
+	if row.ModelYear != nil {
+		if m := yearPattern.FindString(fmt.Sprint(row.ModelYear)); m != "" {
+			return m
+		}
+	}
+	if m := yearPattern.FindString(row.Model); m != "" && nameYear(m, date) {
+		return m
+	}
+	return ""
+}
+
+// nameYear reports whether a year found in a model name is a model year for
+// a snapshot taken on date
+func nameYear(year, date string) bool {
+	y, err := strconv.Atoi(year)
+	if err != nil || len(date) < 4 {
+		return false
+	}
+	d, err := strconv.Atoi(date[:4])
+	return err == nil && y >= d-1 && y <= d+1
+}
+
+// baseModel strips model years from a model name
+func baseModel(model, date string) string {
+	name := yearPattern.ReplaceAllStringFunc(model, func(m string) string {
+		if nameYear(m, date) {
+			return ""
+		}
+		return m
+	})
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// listing is one model year as listed in one snapshot
+type listing struct {
+	entry  float64
+	prices map[string]float64 // by year-free variant key
+}
+
+// history is a model's listings by date and year
+type history struct {
+	name   string
+	brand  string
+	last   models.PriceListRow // newest row, for the segment
+	dates  []string
+	byDate map[string]map[string]*listing
+}
+
+// Detect finds the transitions in one brand's snapshots, sorted by date
+func Detect(snapshots []models.VehicleDocument) []models.ModelYearChange {
+	if len(snapshots) == 0 {
+		return nil
+	}
+	brandID := snapshots[0].BrandID
+	newest := snapshots[len(snapshots)-1].Date
+
+	histories := make(map[string]*history)
+	var order []string
+	for _, snap := range snapshots {
+		for _, row := range snap.Rows {
+			year := Year(row, snap.Date)
+			if year == "" || row.PriceNumeric <= 0 {
+				continue
+			}
+			name := baseModel(row.Model, snap.Date)
+			key := variant.Normalize(name)
+			h, ok := histories[key]
+			if !ok {
+				h = &history{byDate: make(map[string]map[string]*listing)}
+				histories[key] = h
+				order = append(order, key)
+			}
+			h.name, h.brand, h.last = name, snap.Brand, row
+			years, ok := h.byDate[snap.Date]
+			if !ok {
+				years = make(map[string]*listing)
+				h.byDate[snap.Date] = years
+				h.dates = append(h.dates, snap.Date)
+			}
+			l, ok := years[year]
+			if !ok {
+				l = &listing{entry: row.PriceNumeric, prices: make(map[string]float64)}
+				years[year] = l
+			}
+			l.entry = min(l.entry, row.PriceNumeric)
+			vkey := variant.Key(name, row.Trim, row.Engine)
+			if p, ok := l.prices[vkey]; !ok || row.PriceNumeric < p {
+				l.prices[vkey] = row.PriceNumeric
+			}
+		}
+	}
+
+	var changes []models.ModelYearChange
+	for _, key := range order {
+		h := histories[key]
+		years := h.years()
+		seg := segment.Classify(segment.FromRow(brandID, h.last))
+		for i := 1; i < len(years); i++ {
+			if c, ok := h.transition(years[i-1], years[i], newest); ok {
+				c.ID = fmt.Sprintf("%s-%s-%s-%s", brandID, strings.ReplaceAll(key, " ", "-"), c.OldYear, c.NewYear)
+				c.BrandID = brandID
+				c.Segment = &seg
+				changes = append(changes, c)
+			}
+		}
+	}
+	return changes
+}
+
+// years returns the model years the model was ever listed with, oldest first
+func (h *history) years() []string {
+	seen := make(map[string]bool)
+	var years []string
+	for _, date := range h.dates {
+		for y := range h.byDate[date] {
+			if !seen[y] {
+				seen[y] = true
+				years = append(years, y)
+			}
+		}
+	}
+	sort.Strings(years)
+	return years
+}
+
+// transition measures the switch from old to new. It needs a snapshot listing
+// the old year before the new one appeared; transitions older than the
+// history are skipped.
+func (h *history) transition(oldYear, newYear, newest string) (models.ModelYearChange, bool) {
+	var before, firstNew, lastOld string
+	var overlap []string
+	for _, date := range h.dates {
+		years := h.byDate[date]
+		hasOld, hasNew := years[oldYear] != nil, years[newYear] != nil
+		if hasNew && firstNew == "" {
+			firstNew = date
+		}
+		if hasOld {
+			lastOld = date
+			if firstNew == "" {
+				before = date
+			}
+		}
+		if hasOld && hasNew {
+			overlap = append(overlap, date)
+		}
+	}
+	if before == "" || firstNew == "" {
+		return models.ModelYearChange{}, false
+	}
+
+	old, fresh := h.byDate[before][oldYear], h.byDate[firstNew][newYear]
+	c := models.ModelYearChange{
+		Brand:            h.brand,
+		Model:            h.name,
+		OldYear:          oldYear,
+		NewYear:          newYear,
+		NewYearFirstSeen: firstNew,
+		OldYearLastSeen:  lastOld,
+		Ongoing:          len(overlap) > 0 && overlap[len(overlap)-1] == newest,
+		OldEntryPrice:    old.entry,
+		NewEntryPrice:    fresh.entry,
+		PriceDelta:       market.Round(fresh.entry-old.entry, 2),
+		OldTrimCount:     len(old.prices),
+		NewTrimCount:     len(fresh.prices),
+	}
+	c.PriceDeltaPercent = market.Round(percent(fresh.entry-old.entry, old.entry), 2)
+	if len(overlap) == 0 {
+		return c, true
+	}
+	c.OverlapDays = days(overlap[0], overlap[len(overlap)-1]) + 1
+
+	// Each old-year variant's lowest price while both years were listed
+	lowest := make(map[string]float64)
+	c.RunOutEntryPrice = h.byDate[overlap[0]][oldYear].entry
+	for _, date := range overlap {
+		l := h.byDate[date][oldYear]
+		c.RunOutEntryPrice = min(c.RunOutEntryPrice, l.entry)
+		for vkey, p := range l.prices {
+			if low, ok := lowest[vkey]; !ok || p < low {
+				lowest[vkey] = p
+			}
+		}
+	}
+	var sum float64
+	for vkey, p := range old.prices {
+		low, ok := lowest[vkey]
+		if !ok || low >= p {
+			continue
+		}
+		cut := percent(p-low, p)
+		sum += cut
+		c.RunOutVariants++
+		c.RunOutMaxPercent = max(c.RunOutMaxPercent, cut)
+	}
+	if c.RunOutVariants > 0 {
+		c.RunOutAvgPercent = market.Round(sum/float64(c.RunOutVariants), 2)
+		c.RunOutMaxPercent = market.Round(c.RunOutMaxPercent, 2)
+	}
+	return c, true
+}
+
+func percent(delta, base float64) float64 {
+	if base <= 0 {
+		return 0
+	}
+	return delta / base * 100
+}
+
+// days returns the number of days from a to b
+func days(a, b string) int {
+	ta, err1 := time.Parse("2006-01-02", a)
+	tb, err2 := time.Parse("2006-01-02", b)
+	if err1 != nil || err2 != nil {
+		return 0
+	}
+	return int(tb.Sub(ta).Hours() / 24)
+}
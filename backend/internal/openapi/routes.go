@@ -0,0 +1,429 @@
+package openapi
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/spehlivan/price-list/backend/internal/models"
+)
+
+// Parameter locations
+const (
+	InQuery  = "query"
+	InPath   = "path"
+	InHeader = "header"
+)
+
+// Param describes a single request parameter
+type Param struct {
+	Name        string
+	In          string // defaults to query
+	Type        string // string (default), integer, number or boolean
+	Description string
+	Required    bool
+	Enum        []string
+}
+
+func (p Param) in() string {
+	if p.In == "" {
+		return InQuery
+	}
+	return p.In
+}
+
+func (p Param) schema() *Schema {
+	t := p.Type
+	if t == "" {
+		t = "string"
+	}
+	return &Schema{Type: t, Enum: p.Enum}
+}
+
+// segmentParam restricts vehicle rows or intel items to market segments
+var segmentParam = Param{Name: "segment", Description: "Comma-separated segment codes (C-SUV), sizes (A-F) or body types (hatch, sedan, wagon, SUV, MPV, coupe, cabrio, LCV)"}
+
+// Operation documents one route registered by internal/server
+type Operation struct {
+	ID       string
+	Method   string
+	Path     string // gin-style path
+	Tag      string
+	Summary  string
+	Scope    string // API key scope required by the route, empty for public routes
+	Cached   bool   // supports conditional requests (ETag / Last-Modified)
+	Params   []Param
+	Request  any   // zero value of the JSON request body model, if any
+	Response any   // zero value of the response model; nil for free-form JSON
+	Variants []any // alternative response models selected by query parameters
+	Errors   []int // documented error statuses, all using ErrorResponse
+}
+
+// Operations returns every documented API route. Keep this in sync with the
+// routes registered by internal/server; Verify fails on any mismatch.
+func Operations() []Operation {
+	return []Operation{
+		{
+			ID: "getHealth", Method: http.MethodGet, Path: "/api/v1/health", Tag: "system",
+			Summary:  "Liveness check",
+			Response: map[string]string{},
+		},
+		{
+			ID: "getOpenAPI", Method: http.MethodGet, Path: "/api/v1/openapi.json", Tag: "system",
+			Summary: "This OpenAPI document",
+		},
+		{
+			ID: "getIndex", Method: http.MethodGet, Path: "/api/v1/index", Tag: "vehicles",
+			Scope:    models.ScopeReadVehicles,
+			Cached:   true,
+			Summary:  "Available dates per brand",
+			Response: models.IndexData{},
+			Errors:   []int{http.StatusInternalServerError},
+		},
+		{
+			ID: "getLatest", Method: http.MethodGet, Path: "/api/v1/latest", Tag: "vehicles",
+			Scope:    models.ScopeReadVehicles,
+			Cached:   true,
+			Summary:  "Latest price list for every brand",
+			Params:   []Param{segmentParam},
+			Response: models.LatestData{},
+			Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+		},
+		{
+			ID: "getVehicles", Method: http.MethodGet, Path: "/api/v1/vehicles", Tag: "vehicles",
+			Scope:   models.ScopeReadVehicles,
+			Cached:  true,
+			Summary: "Price list of a brand on a date",
+			Params: []Param{
+				{Name: "brand", Description: "Brand id, e.g. volkswagen", Required: true},
+				{Name: "date", Description: "Date in YYYY-MM-DD format", Required: true},
+				{Name: "fill", Description: "forward returns the forward-filled daily series (DailyDocument)", Enum: []string{"none", "forward"}},
+				segmentParam,
+			},
+			Response: models.StoredData{},
+			Variants: []any{models.DailyDocument{}},
+			Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+		},
+		{
+			ID: "getTrend", Method: http.MethodGet, Path: "/api/v1/trend", Tag: "vehicles",
+			Scope:   models.ScopeReadVehicles,
+			Cached:  true,
+			Summary: "Price history of a single vehicle",
+			Params: []Param{
+				{Name: "variantId", Description: "Variant id; replaces brand, model, trim and engine"},
+				{Name: "brand", Description: "Brand id, required without variantId"},
+				{Name: "model", Description: "Required without variantId"},
+				{Name: "trim", Description: "Required without variantId"},
+				{Name: "engine", Description: "Required without variantId"},
+				{Name: "lineage", Description: "false matches only the exact labels instead of following renames", Enum: []string{"true", "false"}},
+				{Name: "days", Type: "integer", Description: "Look-back window in days (max 3650)"},
+				{Name: "fill", Description: "forward carries prices over days without a snapshot", Enum: []string{"none", "forward"}},
+			},
+			Response: models.TrendResponse{},
+			Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+		},
+		{
+			ID: "getForecast", Method: http.MethodGet, Path: "/api/v1/forecast", Tag: "vehicles",
+			Scope:   models.ScopeReadVehicles,
+			Cached:  true,
+			Summary: "Price forecast of a single vehicle with intervals and backtest error",
+			Params: []Param{
+				{Name: "vehicleId", Description: "Variant id", Required: true},
+				{Name: "horizon", Type: "integer", Description: "Days to forecast (default 30, max 180)"},
+				{Name: "level", Type: "integer", Description: "Prediction interval coverage in percent (default 80)", Enum: []string{"80", "90", "95"}},
+			},
+			Response: models.ForecastResponse{},
+			Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity, http.StatusInternalServerError},
+		},
+		{
+			ID: "getStats", Method: http.MethodGet, Path: "/api/v1/stats", Tag: "stats",
+			Scope:    models.ScopeReadVehicles,
+			Summary:  "Latest precomputed statistics",
+			Response: models.StatsData{},
+			Errors:   []int{http.StatusNotFound, http.StatusInternalServerError},
+		},
+		{
+			ID: "getCoverage", Method: http.MethodGet, Path: "/api/v1/coverage", Tag: "vehicles",
+			Scope:   models.ScopeReadVehicles,
+			Cached:  true,
+			Summary: "Per-brand calendar of present and missing snapshot dates",
+			Params: []Param{
+				{Name: "days", Type: "integer", Description: "Window length in days (default 90, max 730)"},
+				{Name: "to", Description: "Last date of the window (YYYY-MM-DD), defaults to the newest snapshot"},
+				{Name: "brand", Description: "Restrict to a brand id"},
+			},
+			Response: models.CoverageData{},
+			Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+		},
+		{
+			ID: "getStaleness", Method: http.MethodGet, Path: "/api/v1/coverage/staleness", Tag: "vehicles",
+			Scope:   models.ScopeReadVehicles,
+			Cached:  true,
+			Summary: "Last substantive price change per brand and model over the last 400 days, flagging frozen sources",
+			Params: []Param{
+				{Name: "days", Type: "integer", Description: "Days without a price or list change that make a model stale and a source frozen (default 30, max 365)"},
+				{Name: "to", Description: "Ignore snapshots after this date (YYYY-MM-DD), defaults to the newest snapshot"},
+				{Name: "brand", Description: "Restrict to a brand id"},
+			},
+			Response: models.StalenessData{},
+			Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+		},
+		{
+			ID: "getRankings", Method: http.MethodGet, Path: "/api/v1/rankings", Tag: "vehicles",
+			Scope:   models.ScopeReadVehicles,
+			Cached:  true,
+			Summary: "Market ranked by a value-for-money metric",
+			Params: []Param{
+				{Name: "metric", Description: "Price per unit to rank by", Required: true, Enum: models.ValueMetricNames},
+				{Name: "order", Description: "asc ranks the lowest price per unit first (default)", Enum: []string{"asc", "desc"}},
+				{Name: "date", Description: "Market date (YYYY-MM-DD): each brand's newest snapshot on or before it; defaults to the newest data"},
+				{Name: "brand", Description: "Restrict to a brand id"},
+				{Name: "fuel", Description: "Restrict to a canonical fuel, e.g. electric"},
+				{Name: "limit", Type: "integer", Description: "Maximum entries (default 50, max 500)"},
+				segmentParam,
+			},
+			Response: models.RankingsResponse{},
+			Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+		},
+		{
+			ID: "getEvents", Method: http.MethodGet, Path: "/api/v1/intel/events", Tag: "intel",
+			Scope:    models.ScopeReadIntel,
+			Summary:  "Latest price change events",
+			Params:   []Param{segmentParam},
+			Response: models.EventsData{},
+			Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+		},
+		{
+			ID: "getArchitecture", Method: http.MethodGet, Path: "/api/v1/intel/architecture", Tag: "intel",
+			Scope:    models.ScopeReadIntel,
+			Summary:  "Latest trim ladders and cross-brand comparison",
+			Params:   []Param{segmentParam},
+			Response: models.ArchitectureData{},
+			Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+		},
+		{
+			ID: "getModelArchitecture", Method: http.MethodGet, Path: "/api/v1/intel/architecture/:brand/:model", Tag: "intel",
+			Scope:   models.ScopeReadIntel,
+			Cached:  true,
+			Summary: "Trim ladder of a model computed live, optionally compared with another date",
+			Params: []Param{
+				{Name: "brand", In: InPath, Description: "Brand id, e.g. volkswagen"},
+				{Name: "model", In: InPath, Description: "Model name or slug, e.g. T-Roc or id-4"},
+				{Name: "date", Description: "Market date (YYYY-MM-DD): the brand's newest snapshot on or before it; defaults to the newest data"},
+				{Name: "compare", Description: "Second date (YYYY-MM-DD) to compare the ladder with"},
+			},
+			Response: models.ModelArchitecture{},
+			Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+		},
+		{
+			ID: "getGaps", Method: http.MethodGet, Path: "/api/v1/intel/gaps", Tag: "intel",
+			Scope:    models.ScopeReadIntel,
+			Summary:  "Latest market gap heatmap",
+			Response: models.GapsData{},
+			Errors:   []int{http.StatusNotFound, http.StatusInternalServerError},
+		},
+		{
+			ID: "getGapAnalysis", Method: http.MethodGet, Path: "/api/v1/intel/gaps/analyze", Tag: "intel",
+			Scope:   models.ScopeReadIntel,
+			Cached:  true,
+			Summary: "Gap heatmap over custom dimensions and price bands, computed live",
+			Params: []Param{
+				{Name: "date", Description: "Market date (YYYY-MM-DD): each brand's newest snapshot on or before it; defaults to the newest data"},
+				{Name: "dimensions", Description: "Comma-separated cell dimensions, at most 4 (default segment,fuel,transmission): " + strings.Join(models.GapDimensions, ", ")},
+				{Name: "priceEdges", Description: "Comma-separated ascending price band edges in TL; the last band is open (default 0,500000,1000000,1500000,2000000,3000000,5000000)"},
+				{Name: "rangeEdges", Description: "Comma-separated ascending EV range bucket edges in km for the evRange dimension (default 0,300,400,500,600)"},
+				segmentParam,
+			},
+			Response: models.GapAnalysis{},
+			Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+		},
+		{
+			ID: "getPromos", Method: http.MethodGet, Path: "/api/v1/intel/promos", Tag: "intel",
+			Scope:    models.ScopeReadIntel,
+			Summary:  "Latest price drops and promotions",
+			Params:   []Param{segmentParam},
+			Response: models.PromosData{},
+			Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+		},
+		{
+			ID: "getLifecycle", Method: http.MethodGet, Path: "/api/v1/intel/lifecycle", Tag: "intel",
+			Scope:    models.ScopeReadIntel,
+			Summary:  "Latest model lifecycle data",
+			Params:   []Param{segmentParam},
+			Response: models.LifecycleData{},
+			Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+		},
+		{
+			ID: "getModelYearChanges", Method: http.MethodGet, Path: "/api/v1/intel/lifecycle/transitions", Tag: "intel",
+			Scope:   models.ScopeReadIntel,
+			Summary: "Model-year transitions detected over the snapshot history, precomputed by cmd/modelyears",
+			Params: []Param{
+				{Name: "brand", Description: "Restrict to a brand id"},
+				{Name: "from", Description: "Earliest date the new model year first appeared (YYYY-MM-DD)"},
+				{Name: "to", Description: "Latest date the new model year first appeared (YYYY-MM-DD)"},
+				segmentParam,
+			},
+			Response: models.ModelYearChangesData{},
+			Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+		},
+		{
+			ID: "getReactions", Method: http.MethodGet, Path: "/api/v1/intel/reactions", Tag: "intel",
+			Scope:   models.ScopeReadIntel,
+			Cached:  true,
+			Summary: "Competitor reaction matrix and lead/lag price index correlation, computed live",
+			Params: []Param{
+				{Name: "vehicleId", Description: "Analyze this variant's segment and detail the variant's own moves; excludes segment"},
+				{Name: "window", Type: "integer", Description: "Days after a move in which competitor moves count as reactions, also the largest correlation lag (default 14, max 60)"},
+				{Name: "minMove", Type: "number", Description: "Smallest variant price change in percent that counts as a move (default 0.5)"},
+				{Name: "from", Description: "First snapshot date (YYYY-MM-DD), defaults to 180 days before to; at most 730 days before to"},
+				{Name: "to", Description: "Last snapshot date (YYYY-MM-DD), defaults to the newest snapshot"},
+				segmentParam,
+			},
+			Response: models.ReactionAnalysis{},
+			Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+		},
+		{
+			ID: "getErrors", Method: http.MethodGet, Path: "/api/v1/errors", Tag: "intel",
+			Scope:   models.ScopeReadIntel,
+			Summary: "Collector error log with counts over time",
+			Params: []Param{
+				{Name: "category", Description: "Restrict to a category, e.g. HTTP_ERROR"},
+				{Name: "severity", Description: "Restrict to a severity", Enum: []string{"error", "warning", "info"}},
+				{Name: "source", Description: "Restrict to a source, e.g. collection"},
+				{Name: "brand", Description: "Restrict to a brand id"},
+				{Name: "from", Description: "First date (YYYY-MM-DD), defaults to 6 days ago"},
+				{Name: "to", Description: "Last date (YYYY-MM-DD), defaults to today"},
+				{Name: "page", Type: "integer", Description: "1-based page (default 1, max 10000)"},
+				{Name: "pageSize", Type: "integer", Description: "Errors per page (default 100, max 500)"},
+			},
+			Response: models.ErrorLogResponse{},
+			Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+		},
+		{
+			ID: "getAnomalies", Method: http.MethodGet, Path: "/api/v1/anomalies", Tag: "intel",
+			Scope:   models.ScopeReadIntel,
+			Summary: "Day-over-day anomaly verdicts of imported snapshots",
+			Params: []Param{
+				{Name: "brand", Description: "Restrict to a brand id"},
+				{Name: "verdict", Description: "Restrict to one verdict", Enum: []string{models.VerdictFlagged, models.VerdictRejected}},
+				{Name: "from", Description: "First snapshot date (YYYY-MM-DD)"},
+				{Name: "to", Description: "Last snapshot date (YYYY-MM-DD)"},
+				{Name: "limit", Type: "integer", Description: "Maximum verdicts (default 100, max 1000)"},
+			},
+			Response: models.AnomaliesData{},
+			Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+		},
+		{
+			ID: "getCampaigns", Method: http.MethodGet, Path: "/api/v1/campaigns", Tag: "intel",
+			Scope:   models.ScopeReadIntel,
+			Summary: "Detected campaign episodes with duration and depth per brand",
+			Params: []Param{
+				{Name: "brand", Description: "Restrict to a brand id"},
+				{Name: "active", Type: "boolean", Description: "true for running campaigns only, false for ended ones"},
+				{Name: "kind", Description: "Restrict to one detection kind", Enum: []string{models.CampaignKindListed, models.CampaignKindDip}},
+				{Name: "limit", Type: "integer", Description: "Maximum episodes (default 100, max 1000)"},
+			},
+			Response: models.CampaignsData{},
+			Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+		},
+		{
+			ID: "getCollectionHealth", Method: http.MethodGet, Path: "/api/v1/health/collection", Tag: "intel",
+			Scope:   models.ScopeReadIntel,
+			Summary: "Per-brand collector health over a window of days",
+			Params: []Param{
+				{Name: "days", Type: "integer", Description: "Window length in days (default 30, max 365)"},
+				{Name: "to", Description: "Last date of the window (YYYY-MM-DD), defaults to today"},
+				{Name: "brand", Description: "Restrict to a brand id"},
+			},
+			Response: models.CollectionHealthData{},
+			Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+		},
+		{
+			ID: "getInsights", Method: http.MethodGet, Path: "/api/v1/insights", Tag: "intel",
+			Scope:    models.ScopeReadIntel,
+			Summary:  "Latest deal scores and outliers",
+			Params:   []Param{segmentParam},
+			Response: models.InsightsData{},
+			Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+		},
+		{
+			ID: "getScore", Method: http.MethodGet, Path: "/api/v1/insights/score", Tag: "intel",
+			Scope:   models.ScopeReadIntel,
+			Cached:  true,
+			Summary: "Deal scores recomputed for a market date with selectable peer groups and weights",
+			Params: []Param{
+				{Name: "vehicleId", Description: "Variant id; returns that vehicle and its peer group instead of the market lists"},
+				{Name: "peerGroup", Description: "Vehicles a price is compared with (default segment_fuel)", Enum: models.PeerGroups},
+				{Name: "date", Description: "Market date (YYYY-MM-DD): each brand's newest snapshot on or before it; defaults to the newest data"},
+				{Name: "campaignWeight", Type: "number", Description: "Weight of the campaign discount rank against the price rank (weight 1), 0-10, default 0"},
+				{Name: "hpWeight", Type: "number", Description: "Weight of the TL/HP rank, 0-10, default 0"},
+				{Name: "rangeWeight", Type: "number", Description: "Weight of the TL per km of WLTP range rank (electric vehicles), 0-10, default 0"},
+				segmentParam,
+			},
+			Response: models.ScoreResponse{},
+			Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+		},
+		{
+			ID: "listAPIKeys", Method: http.MethodGet, Path: "/api/v1/admin/keys", Tag: "admin",
+			Scope:    models.ScopeAdmin,
+			Summary:  "API keys with today's usage",
+			Response: models.APIKeyListResponse{},
+			Errors:   []int{http.StatusInternalServerError},
+		},
+		{
+			ID: "getAPIKeyUsage", Method: http.MethodGet, Path: "/api/v1/admin/usage", Tag: "admin",
+			Scope:   models.ScopeAdmin,
+			Summary: "Daily request counters per API key",
+			Params: []Param{
+				{Name: "from", Description: "Start date (YYYY-MM-DD), defaults to 29 days ago"},
+				{Name: "to", Description: "End date (YYYY-MM-DD), defaults to today"},
+				{Name: "key", Description: "Restrict to a single key id"},
+			},
+			Response: models.APIKeyUsageResponse{},
+			Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+		},
+		{
+			ID: "getQuarantine", Method: http.MethodGet, Path: "/api/v1/admin/quarantine", Tag: "admin",
+			Scope:   models.ScopeAdmin,
+			Summary: "Data files rejected at import time",
+			Params: []Param{
+				{Name: "brand", Description: "Restrict to a brand id"},
+				{Name: "limit", Type: "integer", Description: "Maximum entries (default 100, max 1000)"},
+			},
+			Response: models.QuarantineResponse{},
+			Errors:   []int{http.StatusInternalServerError},
+		},
+		{
+			ID: "getVocabulary", Method: http.MethodGet, Path: "/api/v1/admin/vocabulary", Tag: "admin",
+			Scope:    models.ScopeAdmin,
+			Summary:  "Fuel, transmission and drive type mapping table with unmapped raw values",
+			Response: models.VocabularyResponse{},
+			Errors:   []int{http.StatusInternalServerError},
+		},
+		{
+			ID: "putVocabulary", Method: http.MethodPut, Path: "/api/v1/admin/vocabulary", Tag: "admin",
+			Scope:    models.ScopeAdmin,
+			Summary:  "Map a raw value onto a canonical value and restamp stored rows",
+			Request:  models.VocabularyUpdate{},
+			Response: models.VocabularyResponse{},
+			Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+		},
+		{
+			ID: "deleteVocabulary", Method: http.MethodDelete, Path: "/api/v1/admin/vocabulary", Tag: "admin",
+			Scope:   models.ScopeAdmin,
+			Summary: "Remove a vocabulary override and restamp stored rows",
+			Params: []Param{
+				{Name: "field", Required: true, Enum: models.VocabularyFields},
+				{Name: "raw", Description: "Raw value of the override", Required: true},
+			},
+			Response: models.VocabularyResponse{},
+			Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+		},
+		{
+			ID: "restampVocabulary", Method: http.MethodPost, Path: "/api/v1/admin/vocabulary/restamp", Tag: "admin",
+			Scope:    models.ScopeAdmin,
+			Summary:  "Rewrite the canonical values of every stored row from the current table",
+			Response: models.VocabularyResponse{},
+			Errors:   []int{http.StatusInternalServerError},
+		},
+	}
+}
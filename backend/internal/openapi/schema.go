@@ -0,0 +1,155 @@
+package openapi
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const refPrefix = "#/components/schemas/"
+
+// Schema is the subset of the OpenAPI schema object used by this API
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+}
+
+// registry collects named component schemas while walking model types
+type registry struct {
+	schemas map[string]*Schema
+	types   map[string]reflect.Type
+	errs    []string
+}
+
+func newRegistry() *registry {
+	return &registry{
+		schemas: make(map[string]*Schema),
+		types:   make(map[string]reflect.Type),
+	}
+}
+
+// schemaFor returns the schema for the dynamic type of v
+func (r *registry) schemaFor(v any) *Schema {
+	return r.schemaOf(reflect.TypeOf(v))
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (r *registry) schemaOf(t reflect.Type) *Schema {
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := r.schemaOf(t.Elem())
+		if s.Ref != "" {
+			return s
+		}
+		s.Nullable = true
+		return s
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.Interface:
+		// Free-form value (e.g. modelYear is number | string)
+		return &Schema{}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: r.schemaOf(t.Elem())}
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			r.errs = append(r.errs, fmt.Sprintf("unsupported map key type %s", t.Key()))
+		}
+		return &Schema{Type: "object", AdditionalProperties: r.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.structSchema(t)
+		}
+		return r.named(t)
+	default:
+		r.errs = append(r.errs, fmt.Sprintf("unsupported type %s", t))
+		return &Schema{}
+	}
+}
+
+// named registers a named struct as a component schema and returns a reference to it
+func (r *registry) named(t reflect.Type) *Schema {
+	name := t.Name()
+	if existing, ok := r.types[name]; ok {
+		if existing != t {
+			r.errs = append(r.errs, fmt.Sprintf("schema name %s used by %s and %s", name, existing, t))
+		}
+		return &Schema{Ref: refPrefix + name}
+	}
+	r.types[name] = t
+	// Reserve the name before recursing so self-referencing types terminate
+	r.schemas[name] = &Schema{}
+	*r.schemas[name] = *r.structSchema(t)
+	return &Schema{Ref: refPrefix + name}
+}
+
+func (r *registry) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	r.addFields(s, t)
+	return s
+}
+
+func (r *registry) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, omitEmpty, skip := jsonField(f)
+		if skip {
+			continue
+		}
+		// Embedded structs without a json name are flattened like encoding/json does
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			r.addFields(s, f.Type)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		s.Properties[name] = r.schemaOf(f.Type)
+		if !omitEmpty && f.Type.Kind() != reflect.Interface {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// jsonField parses the json struct tag of a field
+func jsonField(f reflect.StructField) (name string, omitEmpty bool, skip bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	parts := strings.Split(tag, ",")
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" || opt == "omitzero" {
+			omitEmpty = true
+		}
+	}
+	return parts[0], omitEmpty, false
+}
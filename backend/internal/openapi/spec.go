@@ -0,0 +1,238 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Document is the root of an OpenAPI 3 specification
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Servers    []Server            `json:"servers,omitempty"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type Server struct {
+	URL string `json:"url"`
+}
+
+// PathItem maps lower-case HTTP methods to operations
+type PathItem map[string]*OperationObject
+
+type OperationObject struct {
+	OperationID string                    `json:"operationId"`
+	Summary     string                    `json:"summary,omitempty"`
+	Description string                    `json:"description,omitempty"`
+	Tags        []string                  `json:"tags,omitempty"`
+	Parameters  []ParameterObject         `json:"parameters,omitempty"`
+	RequestBody *RequestBodyObject        `json:"requestBody,omitempty"`
+	Security    []map[string][]string     `json:"security,omitempty"`
+	Responses   map[string]ResponseObject `json:"responses"`
+}
+
+type RequestBodyObject struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type ParameterObject struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema"`
+}
+
+type ResponseObject struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	In          string `json:"in,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Scheme      string `json:"scheme,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+const (
+	errorSchemaName = "ErrorResponse"
+	apiKeyScheme    = "ApiKeyAuth"
+	bearerScheme    = "BearerAuth"
+)
+
+// Build generates the OpenAPI document from the documented operations,
+// deriving response schemas from the model structs via reflection
+func Build(version string) (*Document, error) {
+	reg := newRegistry()
+	reg.schemas[errorSchemaName] = &Schema{
+		Type:       "object",
+		Properties: map[string]*Schema{"error": {Type: "string"}},
+		Required:   []string{"error"},
+	}
+
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info: Info{
+			Title:       "Price List API",
+			Description: "Vehicle price lists, trends, statistics and market intelligence",
+			Version:     version,
+		},
+		Servers: []Server{{URL: "/"}},
+		Paths:   make(map[string]PathItem),
+		Components: Components{
+			SecuritySchemes: map[string]SecurityScheme{
+				apiKeyScheme: {Type: "apiKey", In: "header", Name: "X-API-Key", Description: "API key issued with cmd/apikeys"},
+				bearerScheme: {Type: "http", Scheme: "bearer", Description: "API key sent as a bearer token"},
+			},
+		},
+	}
+
+	for _, op := range Operations() {
+		path := specPath(op.Path)
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = make(PathItem)
+			doc.Paths[path] = item
+		}
+
+		obj := &OperationObject{
+			OperationID: op.ID,
+			Summary:     op.Summary,
+			Tags:        []string{op.Tag},
+			Responses:   make(map[string]ResponseObject),
+		}
+		errs := op.Errors
+		if op.Scope != "" {
+			obj.Description = "Requires an API key with scope " + op.Scope + "."
+			obj.Security = []map[string][]string{{apiKeyScheme: {}}, {bearerScheme: {}}}
+			errs = append(errs, http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests)
+		}
+		if op.Cached {
+			obj.Parameters = append(obj.Parameters, ParameterObject{
+				Name:        "If-None-Match",
+				In:          InHeader,
+				Description: "ETag of a previous response; answered with 304 when the data is unchanged",
+				Schema:      &Schema{Type: "string"},
+			})
+			obj.Responses["304"] = ResponseObject{Description: "Not Modified"}
+		}
+		for _, p := range op.Params {
+			obj.Parameters = append(obj.Parameters, ParameterObject{
+				Name:        p.Name,
+				In:          p.in(),
+				Description: p.Description,
+				Required:    p.Required || p.In == InPath,
+				Schema:      p.schema(),
+			})
+		}
+
+		if op.Request != nil {
+			obj.RequestBody = &RequestBodyObject{
+				Required: true,
+				Content:  map[string]MediaType{"application/json": {Schema: reg.schemaFor(op.Request)}},
+			}
+		}
+
+		var body *Schema
+		if op.Response != nil {
+			body = reg.schemaFor(op.Response)
+			if len(op.Variants) > 0 {
+				body = &Schema{OneOf: []*Schema{body}}
+				for _, v := range op.Variants {
+					body.OneOf = append(body.OneOf, reg.schemaFor(v))
+				}
+			}
+		} else {
+			body = &Schema{Type: "object", AdditionalProperties: &Schema{}}
+		}
+		obj.Responses["200"] = ResponseObject{
+			Description: "Successful response",
+			Content:     map[string]MediaType{"application/json": {Schema: body}},
+		}
+		for _, status := range errs {
+			obj.Responses[fmt.Sprint(status)] = ResponseObject{
+				Description: http.StatusText(status),
+				Content: map[string]MediaType{"application/json": {
+					Schema: &Schema{Ref: refPrefix + errorSchemaName},
+				}},
+			}
+		}
+
+		item[strings.ToLower(op.Method)] = obj
+	}
+
+	if len(reg.errs) > 0 {
+		return nil, fmt.Errorf("openapi: %s", strings.Join(reg.errs, "; "))
+	}
+
+	doc.Components.Schemas = reg.schemas
+	return doc, nil
+}
+
+// Verify reports drift between the routes registered on the router and the
+// documented operations. Every registered route must be documented and every
+// documented operation must be registered.
+func Verify(routes gin.RoutesInfo) error {
+	registered := make(map[string]bool, len(routes))
+	for _, r := range routes {
+		registered[r.Method+" "+r.Path] = true
+	}
+
+	documented := make(map[string]bool)
+	for _, op := range Operations() {
+		documented[op.Method+" "+op.Path] = true
+	}
+
+	var problems []string
+	for key := range registered {
+		if !documented[key] {
+			problems = append(problems, "undocumented route "+key)
+		}
+	}
+	for key := range documented {
+		if !registered[key] {
+			problems = append(problems, "documented route not registered "+key)
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+
+	_, err := Build("verify")
+	return err
+}
+
+// specPath converts a gin route path (/brands/:id) to OpenAPI form (/brands/{id})
+func specPath(path string) string {
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		if strings.HasPrefix(part, ":") || strings.HasPrefix(part, "*") {
+			parts[i] = "{" + part[1:] + "}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,274 @@
+package openapi_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spehlivan/price-list/backend/config"
+	"github.com/spehlivan/price-list/backend/internal/openapi"
+	"github.com/spehlivan/price-list/backend/internal/server"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+var update = flag.Bool("update", false, "rewrite testdata/openapi.json from the current spec")
+
+const goldenFile = "openapi.json"
+
+func buildSpec(t *testing.T) *openapi.Document {
+	t.Helper()
+	doc, err := openapi.Build("test")
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	return doc
+}
+
+// TestRoutesDocumented builds the router main serves and checks it against
+// the documented operations
+func TestRoutesDocumented(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// The driver connects lazily; registering routes never reaches the server
+	client, err := mongo.Connect(options.Client().ApplyURI("mongodb://127.0.0.1:1"))
+	if err != nil {
+		t.Fatalf("mongo.Connect: %v", err)
+	}
+	defer client.Disconnect(context.Background())
+
+	r, err := server.NewRouter(&config.Config{CORSOrigins: "http://localhost:5173"}, client.Database("test"), buildSpec(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := openapi.Verify(r.Routes()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestSpecGolden pins the generated document, so a model change that alters
+// a response shows up as a reviewed diff of testdata/openapi.json. Run with
+// -update after an intended change.
+func TestSpecGolden(t *testing.T) {
+	got, err := json.MarshalIndent(buildSpec(t), "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = append(got, '\n')
+
+	path := filepath.Join("testdata", goldenFile)
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test ./internal/openapi -update)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("spec differs from %s; run go test ./internal/openapi -update and review the diff", path)
+	}
+}
+
+// TestSchemasMatchEncoding encodes a populated value of every documented
+// request and response model with encoding/json, as the handlers do, and
+// validates the JSON against the operation's schema
+func TestSchemasMatchEncoding(t *testing.T) {
+	doc := buildSpec(t)
+	ops := make(map[string]*openapi.OperationObject)
+	for _, item := range doc.Paths {
+		for _, op := range item {
+			ops[op.OperationID] = op
+		}
+	}
+
+	for _, op := range openapi.Operations() {
+		obj, ok := ops[op.ID]
+		if !ok {
+			t.Errorf("%s: missing from the document", op.ID)
+			continue
+		}
+		if op.Request != nil {
+			check(t, doc, op.ID+" request", obj.RequestBody.Content["application/json"].Schema, op.Request)
+		}
+		if op.Response == nil {
+			continue
+		}
+		body := obj.Responses["200"].Content["application/json"].Schema
+		if len(op.Variants) == 0 {
+			check(t, doc, op.ID+" response", body, op.Response)
+			continue
+		}
+		for i, model := range append([]any{op.Response}, op.Variants...) {
+			check(t, doc, fmt.Sprintf("%s response variant %d", op.ID, i), body.OneOf[i], model)
+		}
+	}
+}
+
+func check(t *testing.T, doc *openapi.Document, name string, schema *openapi.Schema, model any) {
+	t.Helper()
+	v := reflect.New(reflect.TypeOf(model)).Elem()
+	fill(v, 0)
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		t.Errorf("%s: %v", name, err)
+		return
+	}
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Errorf("%s: %v", name, err)
+		return
+	}
+	for _, problem := range validate(doc, schema, decoded, "$") {
+		t.Errorf("%s: %s", name, problem)
+	}
+}
+
+// maxDepth stops filling self-referencing models
+const maxDepth = 8
+
+// fill sets every exported field, pointer, slice and map so the encoding
+// shows each documented property at least once
+func fill(v reflect.Value, depth int) {
+	if depth > maxDepth {
+		return
+	}
+	if v.Type() == reflect.TypeOf(time.Time{}) {
+		v.Set(reflect.ValueOf(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)))
+		return
+	}
+	switch v.Kind() {
+	case reflect.Pointer:
+		p := reflect.New(v.Type().Elem())
+		fill(p.Elem(), depth+1)
+		v.Set(p)
+	case reflect.String:
+		v.SetString("x")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(1)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1.5)
+	case reflect.Slice:
+		s := reflect.MakeSlice(v.Type(), 1, 1)
+		fill(s.Index(0), depth+1)
+		v.Set(s)
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			fill(v.Index(i), depth+1)
+		}
+	case reflect.Map:
+		m := reflect.MakeMap(v.Type())
+		key := reflect.New(v.Type().Key()).Elem()
+		fill(key, depth+1)
+		elem := reflect.New(v.Type().Elem()).Elem()
+		fill(elem, depth+1)
+		m.SetMapIndex(key, elem)
+		v.Set(m)
+	case reflect.Interface:
+		if v.NumMethod() == 0 {
+			v.Set(reflect.ValueOf("x"))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				fill(v.Field(i), depth+1)
+			}
+		}
+	}
+}
+
+// validate checks decoded JSON against a schema, resolving component refs
+func validate(doc *openapi.Document, s *openapi.Schema, value any, path string) []string {
+	if s.Ref != "" {
+		name := strings.TrimPrefix(s.Ref, "#/components/schemas/")
+		ref, ok := doc.Components.Schemas[name]
+		if !ok {
+			return []string{path + ": unresolved " + s.Ref}
+		}
+		return validate(doc, ref, value, path)
+	}
+	if value == nil {
+		if s.Nullable || s.Type == "" {
+			return nil
+		}
+		return []string{path + ": null for non-nullable " + s.Type}
+	}
+
+	var problems []string
+	switch s.Type {
+	case "":
+		// Free-form value
+	case "string":
+		if _, ok := value.(string); !ok {
+			problems = append(problems, fmt.Sprintf("%s: %T for string", path, value))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			problems = append(problems, fmt.Sprintf("%s: %T for boolean", path, value))
+		}
+	case "integer", "number":
+		n, ok := value.(float64)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s: %T for %s", path, value, s.Type))
+		} else if s.Type == "integer" && n != float64(int64(n)) {
+			problems = append(problems, fmt.Sprintf("%s: %v for integer", path, n))
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return append(problems, fmt.Sprintf("%s: %T for array", path, value))
+		}
+		for i, item := range items {
+			problems = append(problems, validate(doc, s.Items, item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return append(problems, fmt.Sprintf("%s: %T for object", path, value))
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				problems = append(problems, path+": missing required "+name)
+			}
+		}
+		for name, v := range obj {
+			prop, ok := s.Properties[name]
+			switch {
+			case ok:
+				problems = append(problems, validate(doc, prop, v, path+"."+name)...)
+			case s.AdditionalProperties != nil:
+				problems = append(problems, validate(doc, s.AdditionalProperties, v, path+"."+name)...)
+			default:
+				problems = append(problems, path+": undocumented property "+name)
+			}
+		}
+		for name := range s.Properties {
+			if _, ok := obj[name]; !ok && !slices.Contains(s.Required, name) {
+				// Optional properties are omitted only when empty; fill sets them all
+				problems = append(problems, path+": documented property never encoded "+name)
+			}
+		}
+	default:
+		problems = append(problems, path+": unknown schema type "+s.Type)
+	}
+	return problems
+}
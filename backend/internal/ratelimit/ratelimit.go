@@ -0,0 +1,118 @@
+// Package ratelimit implements token bucket rate limiting with pluggable bucket storage
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit configures a token bucket: it holds at most Burst tokens and refills at PerSecond
+type Limit struct {
+	Burst     float64
+	PerSecond float64
+}
+
+// Result is the outcome of taking tokens from a bucket
+type Result struct {
+	Allowed    bool
+	Remaining  float64       // tokens left after the request
+	RetryAfter time.Duration // wait until the request would be allowed (denied requests only)
+	Reset      time.Duration // time until the bucket is full again
+}
+
+// Store keeps token buckets. Implementations must be safe for concurrent use.
+type Store interface {
+	// Take removes cost tokens from the bucket identified by key if enough are available
+	Take(ctx context.Context, key string, cost float64, limit Limit) (Result, error)
+}
+
+// NewResult derives the result fields from the bucket state after a take attempt
+func NewResult(allowed bool, tokens, cost float64, limit Limit) Result {
+	res := Result{Allowed: allowed, Remaining: tokens}
+	if limit.PerSecond <= 0 {
+		return res
+	}
+	res.Reset = seconds((limit.Burst - tokens) / limit.PerSecond)
+	if !allowed {
+		res.RetryAfter = seconds((cost - tokens) / limit.PerSecond)
+	}
+	return res
+}
+
+func seconds(s float64) time.Duration {
+	if s <= 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	limit   Limit // of the last take, so sweeps judge each bucket by its own limit
+}
+
+// MemoryStore keeps buckets in process memory. Suitable for a single replica.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// sweepInterval controls how often idle (full) buckets are dropped
+const sweepInterval = time.Minute
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Take implements Store
+func (s *MemoryStore) Take(_ context.Context, key string, cost float64, limit Limit) (Result, error) {
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: limit.Burst, updated: now}
+		s.buckets[key] = b
+	}
+	b.tokens = refill(b.tokens, now.Sub(b.updated), limit)
+	b.updated = now
+	b.limit = limit
+
+	allowed := b.tokens >= cost
+	if allowed {
+		b.tokens -= cost
+	}
+
+	if now.Sub(s.lastSweep) > sweepInterval {
+		s.sweep(now)
+		s.lastSweep = now
+	}
+
+	return NewResult(allowed, b.tokens, cost, limit), nil
+}
+
+// sweep removes buckets that have refilled completely; they are equivalent to new ones
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		if refill(b.tokens, now.Sub(b.updated), b.limit) >= b.limit.Burst {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+func refill(tokens float64, elapsed time.Duration, limit Limit) float64 {
+	if elapsed > 0 {
+		tokens += elapsed.Seconds() * limit.PerSecond
+	}
+	return math.Min(tokens, limit.Burst)
+}
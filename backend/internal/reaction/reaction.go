@@ -0,0 +1,366 @@
+// Package reaction measures how brands answer each other's price moves. Price
+// events are extracted per variant from the snapshot history, netted into one
+// move per brand and date, and each brand's moves are matched against the
+// first move of every competitor within a window. Daily price indexes per
+// brand give the lead/lag correlation behind the follow counts.
+package reaction
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/spehlivan/price-list/backend/internal/market"
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"github.com/spehlivan/price-list/backend/internal/segment"
+	"github.com/spehlivan/price-list/backend/internal/variant"
+)
+
+const (
+	// DefaultWindowDays is how long after a move competitor moves count as
+	// reactions
+	DefaultWindowDays = 14
+	// MaxWindowDays bounds the window and the correlation lags
+	MaxWindowDays = 60
+	// DefaultMinMovePercent is the smallest variant price change that makes
+	// a price event
+	DefaultMinMovePercent = 0.5
+	// DefaultHistoryDays is the period analyzed when no start date is given
+	DefaultHistoryDays = 180
+	// MaxHistoryDays bounds the analyzed period
+	MaxHistoryDays = 730
+)
+
+const dateLayout = "2006-01-02"
+
+// ErrVehicleNotFound is returned when Config.VehicleID is not in the history
+var ErrVehicleNotFound = errors.New("vehicle not found")
+
+// Config selects the scope and sensitivity of an analysis. With VehicleID the
+// competitors are the vehicle's segment and its own moves are detailed;
+// otherwise Segments restricts the market.
+type Config struct {
+	VehicleID      string
+	Segments       segment.Filter
+	WindowDays     int
+	MinMovePercent float64
+}
+
+// DefaultConfig returns the whole market with the default window
+func DefaultConfig() Config {
+	return Config{WindowDays: DefaultWindowDays, MinMovePercent: DefaultMinMovePercent}
+}
+
+// brandHistory is one brand's price events and daily index changes in scope
+type brandHistory struct {
+	id      string
+	name    string
+	events  []models.PriceEvent
+	returns map[int]float64 // day -> index change
+	moves   []models.PriceMove
+}
+
+// Analyze runs the analysis over snapshots sorted by brand and date
+func Analyze(snapshots []models.VehicleDocument, cfg Config) (models.ReactionAnalysis, error) {
+	res := models.ReactionAnalysis{
+		VehicleID:      cfg.VehicleID,
+		WindowDays:     cfg.WindowDays,
+		MinMovePercent: cfg.MinMovePercent,
+		Brands:         []string{},
+		Matrix:         []models.ReactionCell{},
+	}
+
+	segments := make(map[string]models.Segment) // per variant id
+	classify := func(brandID, id string, row models.PriceListRow) models.Segment {
+		seg, ok := segments[id]
+		if !ok {
+			seg = segment.Classify(segment.FromRow(brandID, row))
+			segments[id] = seg
+		}
+		return seg
+	}
+	inScope := cfg.Segments.Match
+	if cfg.VehicleID != "" {
+		seg, ok := findVehicle(snapshots, cfg.VehicleID, classify)
+		if !ok {
+			return res, ErrVehicleNotFound
+		}
+		res.Segment = &seg
+		inScope = func(s models.Segment) bool { return s.Code == seg.Code }
+	}
+
+	var brands []*brandHistory
+	for start := 0; start < len(snapshots); {
+		end := start
+		for end < len(snapshots) && snapshots[end].BrandID == snapshots[start].BrandID {
+			end++
+		}
+		b := scan(snapshots[start:end], cfg, classify, inScope)
+		start = end
+		if len(b.returns) == 0 {
+			continue
+		}
+		brands = append(brands, b)
+		res.Brands = append(res.Brands, b.id)
+		res.Events += len(b.events)
+		res.BrandMoves += len(b.moves)
+	}
+	for _, snap := range snapshots {
+		if res.From == "" || snap.Date < res.From {
+			res.From = snap.Date
+		}
+		res.To = max(res.To, snap.Date)
+	}
+	sort.Strings(res.Brands)
+	sort.Slice(brands, func(i, j int) bool { return brands[i].id < brands[j].id })
+
+	lo, hi := day(res.From), day(res.To)
+	for _, leader := range brands {
+		if len(leader.moves) == 0 {
+			continue
+		}
+		for _, follower := range brands {
+			if follower != leader {
+				res.Matrix = append(res.Matrix, cell(leader, follower, cfg.WindowDays, lo, hi))
+			}
+		}
+	}
+
+	if cfg.VehicleID != "" {
+		res.Moves = vehicleMoves(brands, cfg)
+	}
+	return res, nil
+}
+
+// findVehicle returns the segment of a variant's newest row
+func findVehicle(snapshots []models.VehicleDocument, vehicleID string, classify func(string, string, models.PriceListRow) models.Segment) (models.Segment, bool) {
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		for _, row := range snapshots[i].Rows {
+			if rowID(snapshots[i].BrandID, row) == vehicleID {
+				return classify(snapshots[i].BrandID, vehicleID, row), true
+			}
+		}
+	}
+	return models.Segment{}, false
+}
+
+// scan extracts a brand's price events and daily index changes: the mean
+// price ratio of the variants seen in both a snapshot and their previous one
+func scan(snapshots []models.VehicleDocument, cfg Config, classify func(string, string, models.PriceListRow) models.Segment, inScope func(models.Segment) bool) *brandHistory {
+	b := &brandHistory{id: snapshots[0].BrandID, name: snapshots[len(snapshots)-1].Brand, returns: make(map[int]float64)}
+	type observation struct {
+		date  string
+		price float64
+	}
+	prev := make(map[string]observation)
+	for _, snap := range snapshots {
+		var ratios float64
+		var compared int
+		seen := make(map[string]bool)
+		for _, row := range snap.Rows {
+			if row.PriceNumeric <= 0 {
+				continue
+			}
+			id := rowID(b.id, row)
+			if seen[id] || !inScope(classify(b.id, id, row)) {
+				continue
+			}
+			seen[id] = true
+			before, ok := prev[id]
+			prev[id] = observation{snap.Date, row.PriceNumeric}
+			if !ok {
+				continue
+			}
+			ratios += row.PriceNumeric / before.price
+			compared++
+			if pct := (row.PriceNumeric - before.price) / before.price * 100; math.Abs(pct) >= cfg.MinMovePercent {
+				b.events = append(b.events, event(b, id, row, before.date, snap.Date, before.price))
+			}
+		}
+		if compared > 0 {
+			b.returns[day(snap.Date)] = ratios/float64(compared) - 1
+		}
+	}
+	b.moves = moves(b)
+	return b
+}
+
+// event builds the PriceEvent of a variant's change between two snapshots
+func event(b *brandHistory, id string, row models.PriceListRow, previousDate, date string, oldPrice float64) models.PriceEvent {
+	newPrice := row.PriceNumeric
+	change := newPrice - oldPrice
+	pct := market.Round(change/oldPrice*100, 2)
+	oldFormatted, newFormatted := market.FormatTL(oldPrice), market.FormatTL(newPrice)
+	kind := "price_increase"
+	if change < 0 {
+		kind = "price_decrease"
+	}
+	return models.PriceEvent{
+		ID:                 fmt.Sprintf("%s-%s", id, date),
+		Type:               kind,
+		VehicleID:          id,
+		Brand:              b.name,
+		BrandID:            b.id,
+		Model:              row.Model,
+		Trim:               row.Trim,
+		Engine:             row.Engine,
+		Fuel:               row.Fuel,
+		Transmission:       row.Transmission,
+		OldPrice:           &oldPrice,
+		NewPrice:           &newPrice,
+		OldPriceFormatted:  &oldFormatted,
+		NewPriceFormatted:  &newFormatted,
+		PriceChange:        &change,
+		PriceChangePercent: &pct,
+		Date:               date,
+		PreviousDate:       &previousDate,
+	}
+}
+
+// moves nets a brand's events into one move per date
+func moves(b *brandHistory) []models.PriceMove {
+	var out []models.PriceMove
+	for _, e := range b.events {
+		if n := len(out); n == 0 || out[n-1].Date != e.Date {
+			out = append(out, models.PriceMove{BrandID: b.id, Brand: b.name, Date: e.Date})
+		}
+		m := &out[len(out)-1]
+		m.Variants++
+		m.AvgChangePercent += *e.PriceChangePercent
+	}
+	for i := range out {
+		out[i].AvgChangePercent = market.Round(out[i].AvgChangePercent/float64(out[i].Variants), 2)
+		out[i].Direction = direction(out[i].AvgChangePercent)
+	}
+	return out
+}
+
+func direction(pct float64) string {
+	if pct < 0 {
+		return "down"
+	}
+	return "up"
+}
+
+// response returns the follower's first move between minLag and window days
+// after a date
+func response(follower *brandHistory, date string, minLag, window int) (models.PriceMove, bool) {
+	d := day(date)
+	for _, m := range follower.moves {
+		lag := day(m.Date) - d
+		if lag >= minLag && lag <= window {
+			m.LagDays = lag
+			return m, true
+		}
+	}
+	return models.PriceMove{}, false
+}
+
+// cell matches the leader's moves against the follower's responses and
+// correlates their indexes. Same-day moves are counted apart: they say
+// nothing about who leads and would count both ways.
+func cell(leader, follower *brandHistory, window, lo, hi int) models.ReactionCell {
+	c := models.ReactionCell{Leader: leader.id, Follower: follower.id, LeaderMoves: len(leader.moves)}
+	var lags int
+	for _, m := range leader.moves {
+		if _, ok := response(follower, m.Date, 0, 0); ok {
+			c.Simultaneous++
+		}
+		r, ok := response(follower, m.Date, 1, window)
+		switch {
+		case !ok:
+		case r.Direction == m.Direction:
+			c.Followed++
+			lags += r.LagDays
+		default:
+			c.Countered++
+		}
+	}
+	c.FollowRate = market.Round(float64(c.Followed)/float64(c.LeaderMoves)*100, 1)
+	if c.Followed > 0 {
+		c.AvgLagDays = market.Round(float64(lags)/float64(c.Followed), 1)
+	}
+
+	best := math.Inf(-1)
+	for lag := 0; lag <= window; lag++ {
+		r, ok := correlation(leader.returns, follower.returns, lag, lo, hi)
+		if ok && r > best {
+			best, c.BestLagDays = r, lag
+		}
+	}
+	if !math.IsInf(best, -1) {
+		c.Correlation = market.Round(best, 3)
+	}
+	return c
+}
+
+// correlation is the Pearson correlation of the leader's daily index changes
+// with the follower's lag days later, over the days both brands were priced
+func correlation(leader, follower map[int]float64, lag, lo, hi int) (float64, bool) {
+	var n, sx, sy, sxx, syy, sxy float64
+	for d := lo; d+lag <= hi; d++ {
+		x, ok1 := leader[d]
+		y, ok2 := follower[d+lag]
+		if !ok1 || !ok2 {
+			continue
+		}
+		n++
+		sx, sy = sx+x, sy+y
+		sxx, syy, sxy = sxx+x*x, syy+y*y, sxy+x*y
+	}
+	if n < 3 {
+		return 0, false
+	}
+	vx, vy := sxx-sx*sx/n, syy-sy*sy/n
+	if vx <= 0 || vy <= 0 {
+		return 0, false
+	}
+	return (sxy - sx*sy/n) / math.Sqrt(vx*vy), true
+}
+
+// vehicleMoves details the chosen vehicle's events with competitor responses
+func vehicleMoves(brands []*brandHistory, cfg Config) []models.MoveReaction {
+	out := []models.MoveReaction{}
+	for _, b := range brands {
+		for _, e := range b.events {
+			if e.VehicleID != cfg.VehicleID {
+				continue
+			}
+			pct := *e.PriceChangePercent
+			r := models.MoveReaction{
+				Move:      models.PriceMove{BrandID: b.id, Brand: b.name, Date: e.Date, Direction: direction(pct), Variants: 1, AvgChangePercent: pct},
+				Event:     e,
+				Responses: []models.PriceMove{},
+			}
+			for _, other := range brands {
+				if other == b {
+					continue
+				}
+				if m, ok := response(other, e.Date, 0, cfg.WindowDays); ok {
+					r.Responses = append(r.Responses, m)
+				}
+			}
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// rowID identifies a row's variant the way the importer does
+func rowID(brandID string, row models.PriceListRow) string {
+	if row.VariantID != "" {
+		return row.VariantID
+	}
+	return variant.ID(brandID, variant.RowKey(row))
+}
+
+// day returns a date as days since the epoch
+func day(date string) int {
+	t, err := time.Parse(dateLayout, date)
+	if err != nil {
+		return 0
+	}
+	return int(t.Unix() / 86400)
+}
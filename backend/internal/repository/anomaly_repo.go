@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type AnomalyRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAnomalyRepository(db *mongo.Database) *AnomalyRepository {
+	return &AnomalyRepository{
+		collection: db.Collection("import_verdicts"),
+	}
+}
+
+// EnsureIndexes creates the required MongoDB indexes for anomaly verdicts
+func (r *AnomalyRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "path", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "brandId", Value: 1}, {Key: "date", Value: -1}}},
+	})
+	return err
+}
+
+// List returns anomaly guard verdicts, newest snapshot first, with totals per
+// verdict and brand. Empty filters match everything; from and to are
+// inclusive snapshot dates.
+func (r *AnomalyRepository) List(ctx context.Context, brandID, verdict, from, to string, limit int) (*models.AnomaliesData, error) {
+	filter := bson.D{}
+	if brandID != "" {
+		filter = append(filter, bson.E{Key: "brandId", Value: brandID})
+	}
+	if verdict != "" {
+		filter = append(filter, bson.E{Key: "verdict", Value: verdict})
+	}
+	dateRange := bson.D{}
+	if from != "" {
+		dateRange = append(dateRange, bson.E{Key: "$gte", Value: from})
+	}
+	if to != "" {
+		dateRange = append(dateRange, bson.E{Key: "$lte", Value: to})
+	}
+	if len(dateRange) > 0 {
+		filter = append(filter, bson.E{Key: "date", Value: dateRange})
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "date", Value: -1}, {Key: "brandId", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	verdicts := []models.SnapshotVerdict{}
+	if err := cursor.All(ctx, &verdicts); err != nil {
+		return nil, err
+	}
+
+	pipeline := bson.A{
+		bson.D{{Key: "$match", Value: filter}},
+		bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{{Key: "brandId", Value: "$brandId"}, {Key: "verdict", Value: "$verdict"}}},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+	}
+	aggCursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer aggCursor.Close(ctx)
+
+	var counts []struct {
+		ID struct {
+			BrandID string `bson:"brandId"`
+			Verdict string `bson:"verdict"`
+		} `bson:"_id"`
+		Count int `bson:"count"`
+	}
+	if err := aggCursor.All(ctx, &counts); err != nil {
+		return nil, err
+	}
+
+	data := &models.AnomaliesData{
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+		Verdicts:    verdicts,
+	}
+	data.Summary.ByVerdict = make(map[string]int)
+	data.Summary.ByBrand = make(map[string]int)
+	for _, c := range counts {
+		data.Summary.ByVerdict[c.ID.Verdict] += c.Count
+		data.Summary.ByBrand[c.ID.BrandID] += c.Count
+		data.Summary.Total += c.Count
+	}
+	return data, nil
+}
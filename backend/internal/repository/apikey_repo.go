@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type APIKeyRepository struct {
+	keys  *mongo.Collection
+	usage *mongo.Collection
+}
+
+func NewAPIKeyRepository(db *mongo.Database) *APIKeyRepository {
+	return &APIKeyRepository{
+		keys:  db.Collection("api_keys"),
+		usage: db.Collection("api_key_usage"),
+	}
+}
+
+// EnsureIndexes creates the required MongoDB indexes for API keys and usage counters
+func (r *APIKeyRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.keys.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "keyId", Value: 1}}, Options: options.Index().SetUnique(true)},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = r.usage.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "keyId", Value: 1},
+			{Key: "date", Value: -1},
+		},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+// Create stores a new API key
+func (r *APIKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	_, err := r.keys.InsertOne(ctx, key)
+	return err
+}
+
+// FindByHash returns the key with the given hash (revoked keys included)
+func (r *APIKeyRepository) FindByHash(ctx context.Context, hash string) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.keys.FindOne(ctx, bson.D{{Key: "hash", Value: hash}}).Decode(&key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// Revoke marks a key as revoked. Returns mongo.ErrNoDocuments if no active key matches.
+func (r *APIKeyRepository) Revoke(ctx context.Context, keyID string) error {
+	filter := bson.D{
+		{Key: "keyId", Value: keyID},
+		{Key: "revokedAt", Value: bson.D{{Key: "$exists", Value: false}}},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "revokedAt", Value: time.Now().UTC()}}}}
+	res, err := r.keys.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// List returns all keys ordered by creation time
+func (r *APIKeyRepository) List(ctx context.Context) ([]models.APIKey, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	cursor, err := r.keys.Find(ctx, bson.D{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	keys := []models.APIKey{}
+	if err := cursor.All(ctx, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// IncrementUsage atomically bumps the key's counter for date and returns the new count
+func (r *APIKeyRepository) IncrementUsage(ctx context.Context, keyID, date string) (int64, error) {
+	filter := bson.D{
+		{Key: "keyId", Value: keyID},
+		{Key: "date", Value: date},
+	}
+	update := bson.D{{Key: "$inc", Value: bson.D{{Key: "count", Value: int64(1)}}}}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var usage models.APIKeyUsage
+	if err := r.usage.FindOneAndUpdate(ctx, filter, update, opts).Decode(&usage); err != nil {
+		return 0, err
+	}
+	return usage.Count, nil
+}
+
+// GetUsage returns daily counters between from and to (inclusive, YYYY-MM-DD).
+// An empty keyID returns counters for all keys.
+func (r *APIKeyRepository) GetUsage(ctx context.Context, keyID, from, to string) ([]models.APIKeyUsage, error) {
+	filter := bson.D{{Key: "date", Value: bson.D{
+		{Key: "$gte", Value: from},
+		{Key: "$lte", Value: to},
+	}}}
+	if keyID != "" {
+		filter = append(filter, bson.E{Key: "keyId", Value: keyID})
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: -1}, {Key: "keyId", Value: 1}})
+
+	cursor, err := r.usage.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	usage := []models.APIKeyUsage{}
+	if err := cursor.All(ctx, &usage); err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
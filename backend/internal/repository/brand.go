@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// replaceBrand upserts a brand's freshly derived documents by id and removes
+// the brand's documents that were not derived again. The collections written
+// by the cmd/ rebuild tools key their documents by _id and carry a brandId.
+func replaceBrand[T any](ctx context.Context, col *mongo.Collection, brandID string, docs []T, id func(T) string) (removed int64, err error) {
+	ids := make([]string, 0, len(docs))
+	writes := make([]mongo.WriteModel, 0, len(docs))
+	for _, doc := range docs {
+		ids = append(ids, id(doc))
+		writes = append(writes, mongo.NewReplaceOneModel().SetFilter(bson.D{{Key: "_id", Value: id(doc)}}).SetReplacement(doc).SetUpsert(true))
+	}
+	if len(writes) > 0 {
+		if _, err := col.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+			return 0, err
+		}
+	}
+
+	stale := bson.D{{Key: "brandId", Value: brandID}, {Key: "_id", Value: bson.D{{Key: "$nin", Value: ids}}}}
+	res, err := col.DeleteMany(ctx, stale)
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/spehlivan/price-list/backend/internal/campaign"
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// CampaignRepository stores the campaign episodes detected by cmd/campaigns
+type CampaignRepository struct {
+	collection *mongo.Collection
+}
+
+func NewCampaignRepository(db *mongo.Database) *CampaignRepository {
+	return &CampaignRepository{
+		collection: db.Collection("campaigns"),
+	}
+}
+
+// EnsureIndexes creates the required MongoDB indexes for campaigns
+func (r *CampaignRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "brandId", Value: 1}, {Key: "start", Value: -1}}},
+		{Keys: bson.D{{Key: "active", Value: 1}, {Key: "start", Value: -1}}},
+	})
+	return err
+}
+
+// CampaignQuery filters campaign episodes; empty fields match everything
+type CampaignQuery struct {
+	BrandID string
+	Kind    string
+	Active  *bool
+	Limit   int
+}
+
+// List returns campaign episodes, newest start first, with duration and depth
+// statistics per brand over every matching episode
+func (r *CampaignRepository) List(ctx context.Context, q CampaignQuery) (*models.CampaignsData, error) {
+	filter := bson.D{}
+	if q.BrandID != "" {
+		filter = append(filter, bson.E{Key: "brandId", Value: q.BrandID})
+	}
+	if q.Kind != "" {
+		filter = append(filter, bson.E{Key: "kind", Value: q.Kind})
+	}
+	if q.Active != nil {
+		filter = append(filter, bson.E{Key: "active", Value: *q.Active})
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "start", Value: -1}, {Key: "brandId", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	campaigns := []models.Campaign{}
+	if err := cursor.All(ctx, &campaigns); err != nil {
+		return nil, err
+	}
+	return &models.CampaignsData{
+		Total:     len(campaigns),
+		Brands:    campaign.Summarize(campaigns),
+		Campaigns: campaigns[:min(q.Limit, len(campaigns))],
+	}, nil
+}
+
+// ReplaceBrand stores a brand's freshly detected episodes and removes the
+// brand's episodes that were not detected again
+func (r *CampaignRepository) ReplaceBrand(ctx context.Context, brandID string, campaigns []models.Campaign) (removed int64, err error) {
+	return replaceBrand(ctx, r.collection, brandID, campaigns, func(c models.Campaign) string { return c.ID })
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type ErrorLogRepository struct {
+	collection *mongo.Collection
+}
+
+func NewErrorLogRepository(db *mongo.Database) *ErrorLogRepository {
+	return &ErrorLogRepository{
+		collection: db.Collection("error_log"),
+	}
+}
+
+// EnsureIndexes creates the required MongoDB indexes for the error log,
+// including the TTL index that enforces retention
+func (r *ErrorLogRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "loggedAt", Value: -1}}},
+		{Keys: bson.D{{Key: "brandId", Value: 1}, {Key: "loggedAt", Value: -1}}},
+		{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	return err
+}
+
+// ErrorLogQuery filters the error log. Empty fields match everything; the
+// time range is [From, To).
+type ErrorLogQuery struct {
+	Category string
+	Severity string
+	Source   string
+	BrandID  string
+	From     time.Time
+	To       time.Time
+	Page     int // 1-based
+	PageSize int
+}
+
+// Query returns one page of errors, newest first, with counts per category,
+// severity, source and brand and a daily timeline over all matching errors
+func (r *ErrorLogRepository) Query(ctx context.Context, q ErrorLogQuery) (*models.ErrorLogResponse, error) {
+	filter := bson.D{{Key: "loggedAt", Value: bson.D{{Key: "$gte", Value: q.From}, {Key: "$lt", Value: q.To}}}}
+	for _, f := range []struct{ key, value string }{
+		{"category", q.Category},
+		{"severity", q.Severity},
+		{"source", q.Source},
+		{"brandId", q.BrandID},
+	} {
+		if f.value != "" {
+			filter = append(filter, bson.E{Key: f.key, Value: f.value})
+		}
+	}
+
+	countBy := func(field any) bson.A {
+		return bson.A{bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: field},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}}}
+	}
+
+	pipeline := bson.A{
+		bson.D{{Key: "$match", Value: filter}},
+		bson.D{{Key: "$facet", Value: bson.D{
+			{Key: "errors", Value: bson.A{
+				bson.D{{Key: "$sort", Value: bson.D{{Key: "loggedAt", Value: -1}, {Key: "id", Value: 1}}}},
+				bson.D{{Key: "$skip", Value: int64((q.Page - 1) * q.PageSize)}},
+				bson.D{{Key: "$limit", Value: int64(q.PageSize)}},
+			}},
+			{Key: "byCategory", Value: countBy("$category")},
+			{Key: "bySeverity", Value: countBy("$severity")},
+			{Key: "bySource", Value: countBy("$source")},
+			{Key: "byBrand", Value: countBy(bson.D{{Key: "$ifNull", Value: bson.A{"$brandId", ""}}})},
+			{Key: "timeline", Value: bson.A{
+				bson.D{{Key: "$group", Value: bson.D{
+					{Key: "_id", Value: bson.D{
+						{Key: "date", Value: bson.D{{Key: "$dateToString", Value: bson.D{
+							{Key: "format", Value: "%Y-%m-%d"},
+							{Key: "date", Value: "$loggedAt"},
+						}}}},
+						{Key: "severity", Value: "$severity"},
+					}},
+					{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+				}}},
+				bson.D{{Key: "$sort", Value: bson.D{{Key: "_id.date", Value: 1}}}},
+			}},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	type bucket struct {
+		Key   any `bson:"_id"`
+		Count int `bson:"count"`
+	}
+	var facets []struct {
+		Errors     []models.LoggedError `bson:"errors"`
+		ByCategory []bucket             `bson:"byCategory"`
+		BySeverity []bucket             `bson:"bySeverity"`
+		BySource   []bucket             `bson:"bySource"`
+		ByBrand    []bucket             `bson:"byBrand"`
+		Timeline   []struct {
+			ID struct {
+				Date     string `bson:"date"`
+				Severity string `bson:"severity"`
+			} `bson:"_id"`
+			Count int `bson:"count"`
+		} `bson:"timeline"`
+	}
+	if err := cursor.All(ctx, &facets); err != nil {
+		return nil, err
+	}
+
+	resp := &models.ErrorLogResponse{
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+		ClearedAt:   q.From.UTC().Format(time.RFC3339),
+		From:        q.From.UTC().Format(time.RFC3339),
+		To:          q.To.UTC().Format(time.RFC3339),
+		Page:        q.Page,
+		PageSize:    q.PageSize,
+		Errors:      []models.LoggedError{},
+		Timeline:    []models.ErrorCount{},
+	}
+	resp.Summary.ByCategory = make(map[string]int)
+	resp.Summary.BySeverity = make(map[string]int)
+	resp.Summary.BySource = make(map[string]int)
+	resp.Summary.ByBrand = make(map[string]int)
+	if len(facets) == 0 {
+		return resp, nil
+	}
+
+	f := facets[0]
+	if f.Errors != nil {
+		resp.Errors = f.Errors
+	}
+	fill := func(dst map[string]int, buckets []bucket) {
+		for _, b := range buckets {
+			key, _ := b.Key.(string)
+			dst[key] += b.Count
+		}
+	}
+	fill(resp.Summary.ByCategory, f.ByCategory)
+	fill(resp.Summary.BySeverity, f.BySeverity)
+	fill(resp.Summary.BySource, f.BySource)
+	fill(resp.Summary.ByBrand, f.ByBrand)
+	for _, b := range f.BySeverity {
+		resp.Summary.Total += b.Count
+	}
+
+	for _, t := range f.Timeline {
+		n := len(resp.Timeline)
+		if n == 0 || resp.Timeline[n-1].Date != t.ID.Date {
+			resp.Timeline = append(resp.Timeline, models.ErrorCount{Date: t.ID.Date, BySeverity: make(map[string]int)})
+			n++
+		}
+		resp.Timeline[n-1].Total += t.Count
+		resp.Timeline[n-1].BySeverity[t.ID.Severity] += t.Count
+	}
+	return resp, nil
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type CollectionHealthRepository struct {
+	collection *mongo.Collection
+}
+
+func NewCollectionHealthRepository(db *mongo.Database) *CollectionHealthRepository {
+	return &CollectionHealthRepository{
+		collection: db.Collection("collection_health"),
+	}
+}
+
+// EnsureIndexes creates the required MongoDB indexes for collection health
+func (r *CollectionHealthRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "date", Value: -1}, {Key: "brand", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "brand", Value: 1}, {Key: "date", Value: -1}}},
+	})
+	return err
+}
+
+// Summarize computes per-brand collection health for the dates from..to
+// (inclusive, YYYY-MM-DD). An empty brand summarizes every brand reported in
+// the window.
+func (r *CollectionHealthRepository) Summarize(ctx context.Context, from, to, brand string) (*models.CollectionHealthData, error) {
+	filter := bson.D{{Key: "date", Value: bson.D{{Key: "$gte", Value: from}, {Key: "$lte", Value: to}}}}
+	if brand != "" {
+		filter = append(filter, bson.E{Key: "brand", Value: brand})
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "brand", Value: 1}, {Key: "date", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []models.CollectionHealthEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+
+	lastSuccess, err := r.lastSuccess(ctx, to, brand)
+	if err != nil {
+		return nil, err
+	}
+
+	days := windowDays(from, to)
+	data := &models.CollectionHealthData{From: from, To: to, Days: days, Brands: []models.BrandCollectionHealth{}}
+	for start := 0; start < len(entries); {
+		end := start
+		for end < len(entries) && entries[end].Brand == entries[start].Brand {
+			end++
+		}
+		data.Brands = append(data.Brands, summarizeBrand(entries[start:end], days, lastSuccess))
+		start = end
+	}
+	return data, nil
+}
+
+// lastSuccess returns the last date with fresh data per brand, up to and including to
+func (r *CollectionHealthRepository) lastSuccess(ctx context.Context, to, brand string) (map[string]string, error) {
+	match := bson.D{
+		{Key: "success", Value: true},
+		{Key: "usedFallback", Value: false},
+		{Key: "date", Value: bson.D{{Key: "$lte", Value: to}}},
+	}
+	if brand != "" {
+		match = append(match, bson.E{Key: "brand", Value: brand})
+	}
+	pipeline := bson.A{
+		bson.D{{Key: "$match", Value: match}},
+		bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$brand"},
+			{Key: "date", Value: bson.D{{Key: "$max", Value: "$date"}}},
+		}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		Brand string `bson:"_id"`
+		Date  string `bson:"date"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+	last := make(map[string]string, len(rows))
+	for _, row := range rows {
+		last[row.Brand] = row.Date
+	}
+	return last, nil
+}
+
+// summarizeBrand aggregates one brand's entries, sorted by date
+func summarizeBrand(entries []models.CollectionHealthEntry, days int, lastSuccess map[string]string) models.BrandCollectionHealth {
+	h := models.BrandCollectionHealth{
+		Brand:          entries[0].Brand,
+		Reports:        len(entries),
+		MissingReports: max(days-len(entries), 0),
+	}
+
+	var elapsed []int64
+	for _, e := range entries {
+		if e.Success {
+			h.Successes++
+		}
+		if e.UsedFallback {
+			h.Fallbacks++
+		}
+		if e.Elapsed > 0 {
+			elapsed = append(elapsed, e.Elapsed)
+		}
+		if e.Error != nil && *e.Error != "" {
+			h.LastError = e.Error
+		}
+	}
+	h.SuccessRate = float64(h.Successes) / float64(h.Reports)
+	h.FallbackRate = float64(h.Fallbacks) / float64(h.Reports)
+
+	for i := len(entries) - 1; i >= 0 && !(entries[i].Success && !entries[i].UsedFallback); i-- {
+		h.FailureStreak++
+	}
+	if date, ok := lastSuccess[h.Brand]; ok {
+		h.LastSuccess = &date
+	}
+
+	sort.Slice(elapsed, func(i, j int) bool { return elapsed[i] < elapsed[j] })
+	if len(elapsed) > 0 {
+		h.Latency = models.LatencyPercentiles{
+			P50: percentile(elapsed, 50),
+			P90: percentile(elapsed, 90),
+			P99: percentile(elapsed, 99),
+			Max: elapsed[len(elapsed)-1],
+		}
+	}
+	return h
+}
+
+// percentile returns the nearest-rank percentile p of sorted values
+func percentile(sorted []int64, p int) int64 {
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
+
+// windowDays counts the calendar days from..to, inclusive
+func windowDays(from, to string) int {
+	start, err1 := time.Parse("2006-01-02", from)
+	end, err2 := time.Parse("2006-01-02", to)
+	if err1 != nil || err2 != nil || end.Before(start) {
+		return 0
+	}
+	return int(end.Sub(start).Hours()/24) + 1
+}
@@ -14,7 +14,6 @@ type IntelRepository struct {
 	gaps         *mongo.Collection
 	promos       *mongo.Collection
 	lifecycle    *mongo.Collection
-	errors       *mongo.Collection
 	insights     *mongo.Collection
 }
 
@@ -25,7 +24,6 @@ func NewIntelRepository(db *mongo.Database) *IntelRepository {
 		gaps:         db.Collection("intel_gaps"),
 		promos:       db.Collection("intel_promos"),
 		lifecycle:    db.Collection("intel_lifecycle"),
-		errors:       db.Collection("errors"),
 		insights:     db.Collection("insights"),
 	}
 }
@@ -35,19 +33,12 @@ func (r *IntelRepository) EnsureIndexes(ctx context.Context) error {
 	dateIndex := mongo.IndexModel{
 		Keys: bson.D{{Key: "date", Value: -1}},
 	}
-	generatedAtIndex := mongo.IndexModel{
-		Keys: bson.D{{Key: "generatedAt", Value: -1}},
-	}
 
 	for _, col := range []*mongo.Collection{r.events, r.architecture, r.gaps, r.promos, r.lifecycle, r.insights} {
 		if _, err := col.Indexes().CreateOne(ctx, dateIndex); err != nil {
 			return err
 		}
 	}
-	// errors collection uses generatedAt
-	if _, err := r.errors.Indexes().CreateOne(ctx, generatedAtIndex); err != nil {
-		return err
-	}
 	return nil
 }
 
@@ -86,10 +77,6 @@ func (r *IntelRepository) GetLifecycle(ctx context.Context) (bson.M, error) {
 	return getLatestRaw(ctx, r.lifecycle, latestSort)
 }
 
-func (r *IntelRepository) GetErrors(ctx context.Context) (bson.M, error) {
-	return getLatestRaw(ctx, r.errors, options.FindOne().SetSort(bson.D{{Key: "generatedAt", Value: -1}}))
-}
-
 func (r *IntelRepository) GetInsights(ctx context.Context) (bson.M, error) {
 	return getLatestRaw(ctx, r.insights, latestSort)
 }
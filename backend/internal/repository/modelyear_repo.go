@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// ModelYearRepository stores the model-year transitions detected by
+// cmd/modelyears
+type ModelYearRepository struct {
+	collection *mongo.Collection
+}
+
+func NewModelYearRepository(db *mongo.Database) *ModelYearRepository {
+	return &ModelYearRepository{
+		collection: db.Collection("model_year_changes"),
+	}
+}
+
+// EnsureIndexes creates the required MongoDB indexes for model-year transitions
+func (r *ModelYearRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "brandId", Value: 1}, {Key: "newYearFirstSeen", Value: -1}}},
+		{Keys: bson.D{{Key: "newYearFirstSeen", Value: -1}}},
+	})
+	return err
+}
+
+// ModelYearQuery filters transitions by brand and by the date the new year
+// first appeared, inclusive; empty fields match everything
+type ModelYearQuery struct {
+	BrandID string
+	From    string
+	To      string
+}
+
+// List returns the matching transitions, newest first
+func (r *ModelYearRepository) List(ctx context.Context, q ModelYearQuery) ([]models.ModelYearChange, error) {
+	filter := bson.D{}
+	if q.BrandID != "" {
+		filter = append(filter, bson.E{Key: "brandId", Value: q.BrandID})
+	}
+	dateRange := bson.D{}
+	if q.From != "" {
+		dateRange = append(dateRange, bson.E{Key: "$gte", Value: q.From})
+	}
+	if q.To != "" {
+		dateRange = append(dateRange, bson.E{Key: "$lte", Value: q.To})
+	}
+	if len(dateRange) > 0 {
+		filter = append(filter, bson.E{Key: "newYearFirstSeen", Value: dateRange})
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "newYearFirstSeen", Value: -1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	changes := []models.ModelYearChange{}
+	if err := cursor.All(ctx, &changes); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// ReplaceBrand stores a brand's freshly detected transitions and removes the
+// brand's transitions that were not detected again
+func (r *ModelYearRepository) ReplaceBrand(ctx context.Context, brandID string, changes []models.ModelYearChange) (removed int64, err error) {
+	return replaceBrand(ctx, r.collection, brandID, changes, func(c models.ModelYearChange) string { return c.ID })
+}
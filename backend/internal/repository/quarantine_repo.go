@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type QuarantineRepository struct {
+	collection *mongo.Collection
+}
+
+func NewQuarantineRepository(db *mongo.Database) *QuarantineRepository {
+	return &QuarantineRepository{
+		collection: db.Collection("quarantine"),
+	}
+}
+
+// EnsureIndexes creates the required MongoDB indexes for quarantined files
+func (r *QuarantineRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "path", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "brandId", Value: 1}, {Key: "date", Value: -1}}},
+	})
+	return err
+}
+
+// List returns quarantined files, newest first, with totals per brand.
+// An empty brandID lists all brands. The raw documents are not returned.
+func (r *QuarantineRepository) List(ctx context.Context, brandID string, limit int) (*models.QuarantineResponse, error) {
+	filter := bson.D{}
+	if brandID != "" {
+		filter = append(filter, bson.E{Key: "brandId", Value: brandID})
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "date", Value: -1}, {Key: "brandId", Value: 1}}).
+		SetProjection(bson.D{{Key: "document", Value: 0}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	entries := []models.QuarantineEntry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+
+	pipeline := bson.A{
+		bson.D{{Key: "$match", Value: filter}},
+		bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$brandId"},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+	}
+	aggCursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer aggCursor.Close(ctx)
+
+	var counts []struct {
+		BrandID string `bson:"_id"`
+		Count   int    `bson:"count"`
+	}
+	if err := aggCursor.All(ctx, &counts); err != nil {
+		return nil, err
+	}
+
+	resp := &models.QuarantineResponse{ByBrand: make(map[string]int), Entries: entries}
+	for _, c := range counts {
+		resp.ByBrand[c.BrandID] = c.Count
+		resp.Total += int64(c.Count)
+	}
+	return resp, nil
+}
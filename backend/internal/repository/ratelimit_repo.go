@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"math"
+
+	"github.com/spehlivan/price-list/backend/internal/ratelimit"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// RateLimitRepository is a ratelimit.Store shared by all replicas through MongoDB.
+// Each bucket is one document updated atomically with an aggregation pipeline,
+// using the server clock ($$NOW) so replicas with skewed clocks agree.
+type RateLimitRepository struct {
+	collection *mongo.Collection
+}
+
+func NewRateLimitRepository(db *mongo.Database) *RateLimitRepository {
+	return &RateLimitRepository{
+		collection: db.Collection("rate_limits"),
+	}
+}
+
+// EnsureIndexes creates the TTL index that expires idle buckets
+func (r *RateLimitRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	return err
+}
+
+// Take implements ratelimit.Store
+func (r *RateLimitRepository) Take(ctx context.Context, key string, cost float64, limit ratelimit.Limit) (ratelimit.Result, error) {
+	elapsedSeconds := bson.D{{Key: "$divide", Value: bson.A{
+		bson.D{{Key: "$subtract", Value: bson.A{"$$NOW", bson.D{{Key: "$ifNull", Value: bson.A{"$updatedAt", "$$NOW"}}}}}},
+		1000,
+	}}}
+	// A bucket is full again after Burst/PerSecond seconds; keep it at least a minute
+	ttlMillis := int64(60_000)
+	if limit.PerSecond > 0 {
+		ttlMillis = max(ttlMillis, int64(math.Ceil(limit.Burst/limit.PerSecond*1000)))
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$set", Value: bson.D{
+			{Key: "tokens", Value: bson.D{{Key: "$min", Value: bson.A{
+				limit.Burst,
+				bson.D{{Key: "$add", Value: bson.A{
+					bson.D{{Key: "$ifNull", Value: bson.A{"$tokens", limit.Burst}}},
+					bson.D{{Key: "$multiply", Value: bson.A{elapsedSeconds, limit.PerSecond}}},
+				}}},
+			}}}},
+			{Key: "updatedAt", Value: "$$NOW"},
+		}}},
+		{{Key: "$set", Value: bson.D{
+			{Key: "allowed", Value: bson.D{{Key: "$gte", Value: bson.A{"$tokens", cost}}}},
+		}}},
+		{{Key: "$set", Value: bson.D{
+			{Key: "tokens", Value: bson.D{{Key: "$cond", Value: bson.A{
+				"$allowed",
+				bson.D{{Key: "$subtract", Value: bson.A{"$tokens", cost}}},
+				"$tokens",
+			}}}},
+			{Key: "expiresAt", Value: bson.D{{Key: "$add", Value: bson.A{"$$NOW", ttlMillis}}}},
+		}}},
+	}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	var doc struct {
+		Tokens  float64 `bson:"tokens"`
+		Allowed bool    `bson:"allowed"`
+	}
+	err := r.collection.FindOneAndUpdate(ctx, bson.D{{Key: "_id", Value: key}}, pipeline, opts).Decode(&doc)
+	if err != nil {
+		return ratelimit.Result{}, err
+	}
+	return ratelimit.NewResult(doc.Allowed, doc.Tokens, cost, limit), nil
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"github.com/spehlivan/price-list/backend/internal/variant"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// variantAliasCollection holds the manual rename table; VehicleRepository
+// reads it too, so alias edits invalidate cached trends
+const variantAliasCollection = "variant_aliases"
+
+// VariantRepository maintains the manual alias table of the variant identity
+// service (see package variant)
+type VariantRepository struct {
+	collection *mongo.Collection
+}
+
+func NewVariantRepository(db *mongo.Database) *VariantRepository {
+	return &VariantRepository{
+		collection: db.Collection(variantAliasCollection),
+	}
+}
+
+// EnsureIndexes creates the required MongoDB indexes for variant aliases
+func (r *VariantRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "brandId", Value: 1}, {Key: "fromKey", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			// Used by VehicleRepository.DataVersion
+			Keys: bson.D{{Key: "createdAt", Value: -1}},
+		},
+	})
+	return err
+}
+
+// Aliases returns the alias table of a brand, or of every brand when brandID is empty
+func (r *VariantRepository) Aliases(ctx context.Context, brandID string) ([]models.VariantAlias, error) {
+	filter := bson.D{}
+	if brandID != "" {
+		filter = append(filter, bson.E{Key: "brandId", Value: brandID})
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "brandId", Value: 1}, {Key: "fromKey", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	aliases := []models.VariantAlias{}
+	if err := cursor.All(ctx, &aliases); err != nil {
+		return nil, err
+	}
+	return aliases, nil
+}
+
+// Resolver loads the alias table of a brand (or all brands) into a resolver
+func (r *VariantRepository) Resolver(ctx context.Context, brandID string) (*variant.Resolver, error) {
+	aliases, err := r.Aliases(ctx, brandID)
+	if err != nil {
+		return nil, err
+	}
+	return variant.NewResolver(aliases), nil
+}
+
+// SetAlias records that alias.FromKey is the same variant as alias.ToKey,
+// replacing any earlier alias of FromKey
+func (r *VariantRepository) SetAlias(ctx context.Context, alias *models.VariantAlias) error {
+	if alias.FromKey == alias.ToKey {
+		return errors.New("an alias must map a key onto a different key")
+	}
+	if alias.CreatedAt.IsZero() {
+		alias.CreatedAt = time.Now().UTC()
+	}
+	filter := bson.D{
+		{Key: "brandId", Value: alias.BrandID},
+		{Key: "fromKey", Value: alias.FromKey},
+	}
+	_, err := r.collection.ReplaceOne(ctx, filter, alias, options.Replace().SetUpsert(true))
+	return err
+}
+
+// RemoveAlias deletes the alias of fromKey. It returns mongo.ErrNoDocuments
+// when there is none.
+func (r *VariantRepository) RemoveAlias(ctx context.Context, brandID, fromKey string) error {
+	res, err := r.collection.DeleteOne(ctx, bson.D{
+		{Key: "brandId", Value: brandID},
+		{Key: "fromKey", Value: fromKey},
+	})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
@@ -2,9 +2,14 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math"
 	"sort"
+	"strings"
 	"time"
 
+	"github.com/spehlivan/price-list/backend/internal/cache"
 	"github.com/spehlivan/price-list/backend/internal/models"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -13,11 +18,19 @@ import (
 
 type VehicleRepository struct {
 	collection *mongo.Collection
+	daily      *mongo.Collection // forward-filled series built by cmd/materialize
+	manifest   *mongo.Collection // one entry per imported file, written by cmd/migrate
+	aliases    *mongo.Collection // variant renames, maintained by cmd/variants
+	vocabulary *mongo.Collection // vocabulary overrides, edited by admins
 }
 
 func NewVehicleRepository(db *mongo.Database) *VehicleRepository {
 	return &VehicleRepository{
 		collection: db.Collection("vehicles"),
+		daily:      db.Collection("vehicles_daily"),
+		manifest:   db.Collection("import_manifest"),
+		aliases:    db.Collection(variantAliasCollection),
+		vocabulary: db.Collection(vocabularyCollection),
 	}
 }
 
@@ -30,6 +43,7 @@ func (r *VehicleRepository) GetIndex(ctx context.Context) (*models.IndexData, er
 			{Key: "name", Value: bson.D{{Key: "$first", Value: "$brand"}}},
 			{Key: "dates", Value: bson.D{{Key: "$addToSet", Value: "$date"}}},
 			{Key: "totalRecords", Value: bson.D{{Key: "$first", Value: "$rowCount"}}},
+			{Key: "lastCollectedAt", Value: bson.D{{Key: "$max", Value: "$collectedAt"}}},
 		}}},
 		bson.D{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
 	}
@@ -41,18 +55,23 @@ func (r *VehicleRepository) GetIndex(ctx context.Context) (*models.IndexData, er
 	defer cursor.Close(ctx)
 
 	type aggResult struct {
-		ID           string   `bson:"_id"`
-		Name         string   `bson:"name"`
-		Dates        []string `bson:"dates"`
-		TotalRecords int      `bson:"totalRecords"`
+		ID              string   `bson:"_id"`
+		Name            string   `bson:"name"`
+		Dates           []string `bson:"dates"`
+		TotalRecords    int      `bson:"totalRecords"`
+		LastCollectedAt string   `bson:"lastCollectedAt"`
 	}
 
 	brands := make(map[string]models.BrandIndexData)
+	lastUpdated := ""
 	for cursor.Next(ctx) {
 		var result aggResult
 		if err := cursor.Decode(&result); err != nil {
 			continue
 		}
+		if result.LastCollectedAt > lastUpdated {
+			lastUpdated = result.LastCollectedAt
+		}
 
 		// Sort dates descending
 		sort.Sort(sort.Reverse(sort.StringSlice(result.Dates)))
@@ -74,14 +93,176 @@ func (r *VehicleRepository) GetIndex(ctx context.Context) (*models.IndexData, er
 	}
 
 	return &models.IndexData{
-		LastUpdated: time.Now().UTC().Format(time.RFC3339),
+		LastUpdated: lastUpdated,
 		Brands:      brands,
 	}, nil
 }
 
+// GetCoverage builds per-brand coverage calendars for the days days ending at
+// to (YYYY-MM-DD). An empty to means the newest snapshot date; an empty
+// brandID covers all brands.
+func (r *VehicleRepository) GetCoverage(ctx context.Context, brandID, to string, days int) (*models.CoverageData, error) {
+	if to == "" {
+		var newest struct {
+			Date string `bson:"date"`
+		}
+		opts := options.FindOne().SetSort(bson.D{{Key: "date", Value: -1}}).SetProjection(bson.D{{Key: "date", Value: 1}})
+		if err := r.collection.FindOne(ctx, bson.D{}, opts).Decode(&newest); err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return &models.CoverageData{Brands: map[string]models.BrandCoverage{}}, nil
+			}
+			return nil, err
+		}
+		to = newest.Date
+	}
+	end, err := time.Parse("2006-01-02", to)
+	if err != nil {
+		return nil, err
+	}
+	from := end.AddDate(0, 0, -(days - 1)).Format("2006-01-02")
+
+	match := bson.D{{Key: "date", Value: bson.D{{Key: "$lte", Value: to}}}}
+	if brandID != "" {
+		match = append(match, bson.E{Key: "brandId", Value: brandID})
+	}
+	inWindow := bson.D{{Key: "$gte", Value: bson.A{"$date", from}}}
+	pipeline := bson.A{
+		bson.D{{Key: "$match", Value: match}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "date", Value: -1}}}},
+		bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$brandId"},
+			{Key: "name", Value: bson.D{{Key: "$first", Value: "$brand"}}},
+			{Key: "firstDate", Value: bson.D{{Key: "$min", Value: "$date"}}},
+			{Key: "snapshots", Value: bson.D{{Key: "$push", Value: bson.D{{Key: "$cond", Value: bson.A{
+				inWindow,
+				bson.D{{Key: "date", Value: "$date"}, {Key: "rowCount", Value: "$rowCount"}},
+				"$$REMOVE",
+			}}}}}},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		ID        string `bson:"_id"`
+		Name      string `bson:"name"`
+		FirstDate string `bson:"firstDate"`
+		Snapshots []struct {
+			Date     string `bson:"date"`
+			RowCount int    `bson:"rowCount"`
+		} `bson:"snapshots"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	data := &models.CoverageData{From: from, To: to, Brands: make(map[string]models.BrandCoverage, len(results))}
+	for _, result := range results {
+		rowCounts := make(map[string]int, len(result.Snapshots))
+		for _, s := range result.Snapshots {
+			rowCounts[s.Date] = s.RowCount
+		}
+		start := max(from, result.FirstDate)
+		data.Brands[result.ID] = buildCoverage(result.Name, result.FirstDate, start, to, rowCounts)
+	}
+	return data, nil
+}
+
+// buildCoverage walks the calendar from..to and collects present days and gaps
+func buildCoverage(name, firstDate, from, to string, rowCounts map[string]int) models.BrandCoverage {
+	cov := models.BrandCoverage{
+		Name:         name,
+		FirstDate:    firstDate,
+		From:         from,
+		To:           to,
+		Gaps:         []models.CoverageGap{},
+		MissingDates: []string{},
+		Calendar:     []models.CoverageDay{},
+	}
+
+	start, _ := time.Parse("2006-01-02", from)
+	end, _ := time.Parse("2006-01-02", to)
+	var gap *models.CoverageGap
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		rowCount, present := rowCounts[date]
+		cov.Calendar = append(cov.Calendar, models.CoverageDay{Date: date, Present: present, RowCount: rowCount})
+		cov.Days++
+
+		if present {
+			cov.PresentDays++
+			gap = nil
+			continue
+		}
+		cov.MissingDays++
+		cov.MissingDates = append(cov.MissingDates, date)
+		if gap == nil {
+			cov.Gaps = append(cov.Gaps, models.CoverageGap{From: date})
+			gap = &cov.Gaps[len(cov.Gaps)-1]
+		}
+		gap.To = date
+		gap.Days++
+	}
+
+	for i := range cov.Gaps {
+		if cov.Gaps[i].To == to {
+			cov.Gaps[i].Ongoing = true
+		}
+		if cov.LongestGap == nil || cov.Gaps[i].Days > cov.LongestGap.Days {
+			longest := cov.Gaps[i]
+			cov.LongestGap = &longest
+		}
+	}
+	if cov.Days > 0 {
+		cov.CoveragePct = math.Round(float64(cov.PresentDays)/float64(cov.Days)*1000) / 10
+	}
+	return cov
+}
+
 // GetLatest returns the latest data for all brands (single aggregation query)
 func (r *VehicleRepository) GetLatest(ctx context.Context) (*models.LatestData, error) {
-	pipeline := bson.A{
+	docs, err := r.GetMarket(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	brands := make(map[string]models.LatestBrandData)
+	totalVehicles := 0
+	generatedAt := ""
+
+	for _, doc := range docs {
+		brands[doc.BrandID] = models.LatestBrandData{
+			Name:     doc.Brand,
+			Date:     doc.Date,
+			Vehicles: doc.Rows,
+		}
+		totalVehicles += len(doc.Rows)
+		if doc.CollectedAt > generatedAt {
+			generatedAt = doc.CollectedAt
+		}
+	}
+
+	return &models.LatestData{
+		GeneratedAt:   generatedAt,
+		TotalVehicles: totalVehicles,
+		Brands:        brands,
+	}, nil
+}
+
+// GetMarket returns every brand's newest snapshot on or before date (the
+// newest overall when date is empty), i.e. the market as it stood that day
+func (r *VehicleRepository) GetMarket(ctx context.Context, date string) ([]models.VehicleDocument, error) {
+	pipeline := bson.A{}
+	if date != "" {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.D{
+			{Key: "date", Value: bson.D{{Key: "$lte", Value: date}}},
+		}}})
+	}
+	pipeline = append(pipeline,
 		bson.D{{Key: "$sort", Value: bson.D{
 			{Key: "brandId", Value: 1},
 			{Key: "date", Value: -1},
@@ -93,7 +274,8 @@ func (r *VehicleRepository) GetLatest(ctx context.Context) (*models.LatestData,
 		bson.D{{Key: "$replaceRoot", Value: bson.D{
 			{Key: "newRoot", Value: "$doc"},
 		}}},
-	}
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "brandId", Value: 1}}}},
+	)
 
 	cursor, err := r.collection.Aggregate(ctx, pipeline)
 	if err != nil {
@@ -101,31 +283,70 @@ func (r *VehicleRepository) GetLatest(ctx context.Context) (*models.LatestData,
 	}
 	defer cursor.Close(ctx)
 
-	brands := make(map[string]models.LatestBrandData)
-	totalVehicles := 0
-
+	docs := []models.VehicleDocument{}
 	for cursor.Next(ctx) {
 		var doc models.VehicleDocument
 		if err := cursor.Decode(&doc); err != nil {
 			continue
 		}
-
-		brands[doc.BrandID] = models.LatestBrandData{
-			Name:     doc.Brand,
-			Date:     doc.Date,
-			Vehicles: doc.Rows,
-		}
-		totalVehicles += len(doc.Rows)
+		docs = append(docs, doc)
 	}
 	if err := cursor.Err(); err != nil {
 		return nil, err
 	}
+	return docs, nil
+}
 
-	return &models.LatestData{
-		GeneratedAt:   time.Now().UTC().Format(time.RFC3339),
-		TotalVehicles: totalVehicles,
-		Brands:        brands,
-	}, nil
+// GetHistory returns the snapshots of a brand (every brand when empty)
+// between from and to inclusive, either of which may be empty, ordered by
+// brand and date
+func (r *VehicleRepository) GetHistory(ctx context.Context, brandID, from, to string) ([]models.VehicleDocument, error) {
+	filter := bson.D{}
+	if brandID != "" {
+		filter = append(filter, bson.E{Key: "brandId", Value: brandID})
+	}
+	dateRange := bson.D{}
+	if from != "" {
+		dateRange = append(dateRange, bson.E{Key: "$gte", Value: from})
+	}
+	if to != "" {
+		dateRange = append(dateRange, bson.E{Key: "$lte", Value: to})
+	}
+	if len(dateRange) > 0 {
+		filter = append(filter, bson.E{Key: "date", Value: dateRange})
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "brandId", Value: 1}, {Key: "date", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	docs := []models.VehicleDocument{}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// BrandIDs returns the ids in the comma-separated list, or every brand id
+// with snapshots when the list is empty
+func (r *VehicleRepository) BrandIDs(ctx context.Context, list string) ([]string, error) {
+	var brandIDs []string
+	for _, b := range strings.Split(list, ",") {
+		if b = strings.TrimSpace(b); b != "" {
+			brandIDs = append(brandIDs, b)
+		}
+	}
+	if len(brandIDs) > 0 {
+		return brandIDs, nil
+	}
+
+	if err := r.collection.Distinct(ctx, "brandId", bson.D{}).Decode(&brandIDs); err != nil {
+		return nil, err
+	}
+	return brandIDs, nil
 }
 
 // GetByBrandAndDate returns vehicle data for a specific brand and date
@@ -150,50 +371,144 @@ func (r *VehicleRepository) GetByBrandAndDate(ctx context.Context, brandID, date
 	}, nil
 }
 
-// TrendPoint represents a single data point in a price trend
-type TrendPoint struct {
-	Date  string  `json:"date" bson:"date"`
-	Price float64 `json:"price" bson:"price"`
+// GetFilledByBrandAndDate returns a brand's forward-filled rows for a calendar day
+func (r *VehicleRepository) GetFilledByBrandAndDate(ctx context.Context, brandID, date string) (*models.DailyDocument, error) {
+	filter := bson.D{
+		{Key: "brandId", Value: brandID},
+		{Key: "date", Value: date},
+	}
+
+	var doc models.DailyDocument
+	if err := r.daily.FindOne(ctx, filter).Decode(&doc); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// TrendQuery selects the rows of a trend. Rows match when their variantKey is
+// one of Keys (the variant's lineage) or, for snapshots imported before
+// variant keys existed, when model, trim and engine match exactly.
+type TrendQuery struct {
+	BrandID string
+	Model   string
+	Trim    string
+	Engine  string
+	Keys    []string
+	Limit   int
+	Days    int
+	Fill    bool
 }
 
 // GetTrend returns price history for a specific vehicle across recent dates.
 // If days > 0, filters to documents within that many days from today.
 // Otherwise uses the limit parameter to cap the number of documents.
-func (r *VehicleRepository) GetTrend(ctx context.Context, brandID, model, trim, engine string, limit int, days int) ([]TrendPoint, error) {
+// With fill, points come from the forward-filled daily series and carry
+// filled/sourceDate markers.
+func (r *VehicleRepository) GetTrend(ctx context.Context, q TrendQuery) ([]models.TrendPoint, error) {
+	limit := q.Limit
 	if limit <= 0 || limit > 365 {
 		limit = 10
 	}
 
-	matchFilter := bson.D{{Key: "brandId", Value: brandID}}
-	if days > 0 {
-		cutoff := time.Now().AddDate(0, 0, -days).Format("2006-01-02")
+	matchFilter := bson.D{{Key: "brandId", Value: q.BrandID}}
+	if q.Days > 0 {
+		cutoff := time.Now().AddDate(0, 0, -q.Days).Format("2006-01-02")
 		matchFilter = append(matchFilter, bson.E{Key: "date", Value: bson.D{{Key: "$gte", Value: cutoff}}})
 	}
 
+	rowFilter := bson.D{
+		{Key: "rows.model", Value: q.Model},
+		{Key: "rows.trim", Value: q.Trim},
+		{Key: "rows.engine", Value: q.Engine},
+	}
+	if len(q.Keys) > 0 {
+		rowFilter = bson.D{{Key: "$or", Value: bson.A{
+			bson.D{{Key: "rows.variantKey", Value: bson.D{{Key: "$in", Value: q.Keys}}}},
+			rowFilter,
+		}}}
+	}
+
+	group := bson.D{
+		{Key: "_id", Value: "$date"},
+		{Key: "price", Value: bson.D{{Key: "$max", Value: "$rows.priceNumeric"}}},
+	}
+	project := bson.D{
+		{Key: "date", Value: "$_id"},
+		{Key: "price", Value: 1},
+		{Key: "_id", Value: 0},
+	}
+	if q.Fill {
+		// A day counts as observed when any matching row was observed
+		group = append(group,
+			bson.E{Key: "filled", Value: bson.D{{Key: "$min", Value: "$rows.filled"}}},
+			bson.E{Key: "sourceDate", Value: bson.D{{Key: "$max", Value: "$rows.sourceDate"}}},
+		)
+		project = append(project, bson.E{Key: "filled", Value: 1}, bson.E{Key: "sourceDate", Value: bson.D{{Key: "$cond", Value: bson.A{
+			"$filled", "$sourceDate", "$$REMOVE",
+		}}}})
+	}
+
 	pipeline := bson.A{
 		bson.D{{Key: "$match", Value: matchFilter}},
 		bson.D{{Key: "$sort", Value: bson.D{{Key: "date", Value: -1}}}},
 		bson.D{{Key: "$limit", Value: int64(limit)}},
 		bson.D{{Key: "$unwind", Value: "$rows"}},
-		bson.D{{Key: "$match", Value: bson.D{
-			{Key: "rows.model", Value: model},
-			{Key: "rows.trim", Value: trim},
-			{Key: "rows.engine", Value: engine},
-		}}},
+		bson.D{{Key: "$match", Value: rowFilter}},
 		bson.D{{Key: "$match", Value: bson.D{
 			{Key: "rows.priceNumeric", Value: bson.D{{Key: "$gt", Value: 0}}},
 		}}},
 		// Group by date to get one price point per day (handles duplicate rows e.g. different model years)
+		bson.D{{Key: "$group", Value: group}},
+		bson.D{{Key: "$project", Value: project}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "date", Value: 1}}}},
+	}
+
+	collection := r.collection
+	if q.Fill {
+		collection = r.daily
+	}
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var points []models.TrendPoint
+	if err := cursor.All(ctx, &points); err != nil {
+		return nil, err
+	}
+
+	return points, nil
+}
+
+// GetVariantNames returns the labels rows with the given variant keys were
+// listed under, oldest first, with the dates each label was seen
+func (r *VehicleRepository) GetVariantNames(ctx context.Context, brandID string, keys []string) ([]models.VariantName, error) {
+	match := bson.D{{Key: "rows.variantKey", Value: bson.D{{Key: "$in", Value: keys}}}}
+	pipeline := bson.A{
+		bson.D{{Key: "$match", Value: append(bson.D{{Key: "brandId", Value: brandID}}, match...)}},
+		bson.D{{Key: "$unwind", Value: "$rows"}},
+		bson.D{{Key: "$match", Value: match}},
 		bson.D{{Key: "$group", Value: bson.D{
-			{Key: "_id", Value: "$date"},
-			{Key: "price", Value: bson.D{{Key: "$max", Value: "$rows.priceNumeric"}}},
+			{Key: "_id", Value: bson.D{
+				{Key: "model", Value: "$rows.model"},
+				{Key: "trim", Value: "$rows.trim"},
+				{Key: "engine", Value: "$rows.engine"},
+			}},
+			{Key: "key", Value: bson.D{{Key: "$first", Value: "$rows.variantKey"}}},
+			{Key: "firstSeen", Value: bson.D{{Key: "$min", Value: "$date"}}},
+			{Key: "lastSeen", Value: bson.D{{Key: "$max", Value: "$date"}}},
 		}}},
 		bson.D{{Key: "$project", Value: bson.D{
-			{Key: "date", Value: "$_id"},
-			{Key: "price", Value: 1},
 			{Key: "_id", Value: 0},
+			{Key: "model", Value: "$_id.model"},
+			{Key: "trim", Value: "$_id.trim"},
+			{Key: "engine", Value: "$_id.engine"},
+			{Key: "key", Value: 1},
+			{Key: "firstSeen", Value: 1},
+			{Key: "lastSeen", Value: 1},
 		}}},
-		bson.D{{Key: "$sort", Value: bson.D{{Key: "date", Value: 1}}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "firstSeen", Value: 1}, {Key: "lastSeen", Value: 1}}}},
 	}
 
 	cursor, err := r.collection.Aggregate(ctx, pipeline)
@@ -202,22 +517,150 @@ func (r *VehicleRepository) GetTrend(ctx context.Context, brandID, model, trim,
 	}
 	defer cursor.Close(ctx)
 
-	var points []TrendPoint
-	if err := cursor.All(ctx, &points); err != nil {
+	names := []models.VariantName{}
+	if err := cursor.All(ctx, &names); err != nil {
 		return nil, err
 	}
+	return names, nil
+}
 
-	return points, nil
+// FindVariant returns the brand and the most recent row stamped with a
+// variant id. It returns mongo.ErrNoDocuments for unknown ids.
+func (r *VehicleRepository) FindVariant(ctx context.Context, variantID string) (string, *models.PriceListRow, error) {
+	filter := bson.D{{Key: "rows.variantId", Value: variantID}}
+	opts := options.FindOne().
+		SetSort(bson.D{{Key: "date", Value: -1}}).
+		SetProjection(bson.D{{Key: "brandId", Value: 1}, {Key: "rows.$", Value: 1}})
+
+	var doc struct {
+		BrandID string                `bson:"brandId"`
+		Rows    []models.PriceListRow `bson:"rows"`
+	}
+	if err := r.collection.FindOne(ctx, filter, opts).Decode(&doc); err != nil {
+		return "", nil, err
+	}
+	if len(doc.Rows) == 0 {
+		return "", nil, mongo.ErrNoDocuments
+	}
+	return doc.BrandID, &doc.Rows[0], nil
+}
+
+// DataVersion identifies the state of the collection for response caching. It
+// combines the newest collectedAt with the snapshot count, which together
+// change whenever a new snapshot is imported, the last import_manifest entry,
+// which also moves when an existing snapshot is re-imported with changes, the
+// last vehicles_daily build and the state of the variant alias and vocabulary
+// tables.
+func (r *VehicleRepository) DataVersion(ctx context.Context) (cache.Version, error) {
+	opts := options.FindOne().
+		SetSort(bson.D{{Key: "collectedAt", Value: -1}}).
+		SetProjection(bson.D{{Key: "collectedAt", Value: 1}})
+
+	var doc struct {
+		CollectedAt string `bson:"collectedAt"`
+	}
+	if err := r.collection.FindOne(ctx, bson.D{}, opts).Decode(&doc); err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return cache.Version{}, err
+	}
+
+	count, err := r.collection.EstimatedDocumentCount(ctx)
+	if err != nil {
+		return cache.Version{}, err
+	}
+
+	lastModified, _ := time.Parse(time.RFC3339, doc.CollectedAt)
+	tag := fmt.Sprintf("%s/%d", doc.CollectedAt, count)
+
+	// Derived and editable state: the newest timestamp of each collection,
+	// plus its count for the tables where removals leave the newest in place
+	for _, dep := range []struct {
+		col     *mongo.Collection
+		field   string
+		counted bool
+	}{
+		{r.manifest, "importedAt", false},
+		{r.daily, "builtAt", false},
+		{r.aliases, "createdAt", true},
+		{r.vocabulary, "updatedAt", true},
+	} {
+		newest, err := newestTime(ctx, dep.col, dep.field)
+		if err != nil {
+			return cache.Version{}, err
+		}
+		if newest.After(lastModified) {
+			lastModified = newest
+		}
+		tag += fmt.Sprintf("/%d", newest.Unix())
+		if dep.counted {
+			n, err := dep.col.EstimatedDocumentCount(ctx)
+			if err != nil {
+				return cache.Version{}, err
+			}
+			tag += fmt.Sprintf(".%d", n)
+		}
+	}
+
+	return cache.Version{
+		Tag:          tag,
+		LastModified: lastModified,
+	}, nil
+}
+
+// newestTime returns the largest value of a time field in a collection, or
+// the zero time when it is empty
+func newestTime(ctx context.Context, col *mongo.Collection, field string) (time.Time, error) {
+	opts := options.FindOne().
+		SetSort(bson.D{{Key: field, Value: -1}}).
+		SetProjection(bson.D{{Key: field, Value: 1}})
+
+	var doc bson.M
+	if err := col.FindOne(ctx, bson.D{}, opts).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+	if dt, ok := doc[field].(bson.DateTime); ok {
+		return dt.Time(), nil
+	}
+	return time.Time{}, nil
 }
 
 // EnsureIndexes creates the required MongoDB indexes
 func (r *VehicleRepository) EnsureIndexes(ctx context.Context) error {
-	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
-		Keys: bson.D{
-			{Key: "brandId", Value: 1},
-			{Key: "date", Value: -1},
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "brandId", Value: 1},
+				{Key: "date", Value: -1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			// Used by DataVersion
+			Keys: bson.D{{Key: "collectedAt", Value: -1}},
+		},
+		{
+			// Used by FindVariant
+			Keys: bson.D{{Key: "rows.variantId", Value: 1}},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.daily.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "brandId", Value: 1},
+				{Key: "date", Value: -1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			// Used by DataVersion
+			Keys: bson.D{{Key: "builtAt", Value: -1}},
 		},
-		Options: options.Index().SetUnique(true),
 	})
 	return err
 }
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"github.com/spehlivan/price-list/backend/internal/vocab"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// vocabularyCollection holds the admin overrides of the vocabulary table;
+// VehicleRepository reads it too, so edits invalidate cached responses
+const vocabularyCollection = "vocabulary"
+
+// VocabularyRepository maintains the fuel, transmission and drive type
+// mapping table and the canonical values stamped on stored rows
+type VocabularyRepository struct {
+	collection *mongo.Collection
+	vehicles   *mongo.Collection
+	daily      *mongo.Collection
+}
+
+func NewVocabularyRepository(db *mongo.Database) *VocabularyRepository {
+	return &VocabularyRepository{
+		collection: db.Collection(vocabularyCollection),
+		vehicles:   db.Collection("vehicles"),
+		daily:      db.Collection("vehicles_daily"),
+	}
+}
+
+// EnsureIndexes creates the required MongoDB indexes for vocabulary overrides
+func (r *VocabularyRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "field", Value: 1}, {Key: "raw", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			// Used by VehicleRepository.DataVersion
+			Keys: bson.D{{Key: "updatedAt", Value: -1}},
+		},
+	})
+	return err
+}
+
+// Overrides returns the stored overrides of the built-in table
+func (r *VocabularyRepository) Overrides(ctx context.Context) ([]models.VocabularyEntry, error) {
+	cursor, err := r.collection.Find(ctx, bson.D{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	entries := []models.VocabularyEntry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Table loads the effective mapping table
+func (r *VocabularyRepository) Table(ctx context.Context) (*vocab.Table, error) {
+	overrides, err := r.Overrides(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return vocab.New(overrides), nil
+}
+
+// Set stores an override; the raw value is stored normalized
+func (r *VocabularyRepository) Set(ctx context.Context, entry models.VocabularyEntry) error {
+	now := time.Now().UTC()
+	entry.Raw = vocab.Key(entry.Raw)
+	entry.UpdatedAt = &now
+	filter := bson.D{{Key: "field", Value: entry.Field}, {Key: "raw", Value: entry.Raw}}
+	_, err := r.collection.ReplaceOne(ctx, filter, entry, options.Replace().SetUpsert(true))
+	return err
+}
+
+// Remove deletes an override, reverting the raw value to the built-in table.
+// It returns mongo.ErrNoDocuments when there is no override.
+func (r *VocabularyRepository) Remove(ctx context.Context, field, raw string) error {
+	res, err := r.collection.DeleteOne(ctx, bson.D{{Key: "field", Value: field}, {Key: "raw", Value: vocab.Key(raw)}})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// rawSources lists the row fields holding raw values of a vocabulary field,
+// in order of precedence
+func rawSources(field string) []string {
+	if field == models.VocabTransmission {
+		return []string{"transmission", "transmissionType"}
+	}
+	return []string{field}
+}
+
+// RawValues returns the distinct raw values of a field in stored snapshots
+func (r *VocabularyRepository) RawValues(ctx context.Context, field string) ([]string, error) {
+	seen := make(map[string]bool)
+	for _, source := range rawSources(field) {
+		var values []string
+		if err := r.vehicles.Distinct(ctx, "rows."+source, bson.D{}).Decode(&values); err != nil {
+			return nil, err
+		}
+		for _, v := range values {
+			seen[v] = true
+		}
+	}
+	values := make([]string, 0, len(seen))
+	for v := range seen {
+		values = append(values, v)
+	}
+	sort.Strings(values)
+	return values, nil
+}
+
+// Unmapped returns the raw values in stored snapshots that the table does not cover
+func (r *VocabularyRepository) Unmapped(ctx context.Context, table *vocab.Table) ([]models.VocabularyEntry, error) {
+	unmapped := []models.VocabularyEntry{}
+	for _, field := range models.VocabularyFields {
+		values, err := r.RawValues(ctx, field)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range values {
+			if vocab.Key(v) == "" {
+				continue
+			}
+			if _, ok := table.Lookup(field, v); !ok {
+				unmapped = append(unmapped, models.VocabularyEntry{Field: field, Raw: v, Canonical: models.VocabUnknown})
+			}
+		}
+	}
+	return unmapped, nil
+}
+
+// Restamp rewrites canonical.<field> on every stored row, in vehicles and
+// vehicles_daily, from the given table. Rows are grouped by canonical value
+// so each group is one array-filtered update per collection. It returns the
+// number of documents modified.
+func (r *VocabularyRepository) Restamp(ctx context.Context, table *vocab.Table, field string) (int64, error) {
+	sources := rawSources(field)
+	target := "rows.$[r].canonical." + field
+
+	var modified int64
+	for i, source := range sources {
+		var values []string
+		if err := r.vehicles.Distinct(ctx, "rows."+source, bson.D{}).Decode(&values); err != nil {
+			return modified, err
+		}
+		groups := make(map[string][]string)
+		for _, v := range values {
+			canonical := table.Canonical(field, v)
+			if canonical == "" {
+				// Empty fallbacks leave the primary source's value; like
+				// vocab.Table.Row, only fuel and transmission are required
+				if i > 0 || field == models.VocabDriveType {
+					continue
+				}
+				canonical = models.VocabUnknown
+			}
+			groups[canonical] = append(groups[canonical], v)
+		}
+
+		for canonical, raws := range groups {
+			elem := bson.D{{Key: "r." + source, Value: bson.D{{Key: "$in", Value: raws}}}}
+			if i > 0 {
+				// Fallback sources only apply where the primary one is empty
+				elem = append(elem, bson.E{Key: "r." + sources[0], Value: bson.D{{Key: "$in", Value: bson.A{"", nil}}}})
+			}
+			filter := bson.D{{Key: "rows." + source, Value: bson.D{{Key: "$in", Value: raws}}}}
+			update := bson.D{{Key: "$set", Value: bson.D{{Key: target, Value: canonical}}}}
+			opts := options.UpdateMany().SetArrayFilters([]any{elem})
+
+			for _, col := range []*mongo.Collection{r.vehicles, r.daily} {
+				res, err := col.UpdateMany(ctx, filter, update, opts)
+				if err != nil {
+					return modified, err
+				}
+				modified += res.ModifiedCount
+			}
+		}
+	}
+	return modified, nil
+}
@@ -0,0 +1,269 @@
+// Package scoring ports the insights generator's deal scoring to Go so it can
+// run on demand, for any market date, with configurable peer groups and
+// weights. A vehicle's price is ranked against its peers: by percentile in
+// groups of at least MinPeers, by z-score below that. Optional components
+// (campaign discount, TL/HP, TL/km of range) are blended in by weight.
+package scoring
+
+import (
+	"math"
+	"slices"
+	"sort"
+
+	"github.com/spehlivan/price-list/backend/internal/market"
+	"github.com/spehlivan/price-list/backend/internal/models"
+)
+
+// MinPeers is the smallest peer group ranked by percentile and checked for outliers
+const MinPeers = 5
+
+// outlierZ is the z-score beyond which a price is an outlier
+const outlierZ = 2.0
+
+// Config selects the peer group and component weights
+type Config struct {
+	PeerGroup string
+	Weights   models.ScoreWeights
+}
+
+// DefaultConfig compares within segment and fuel on price alone, the closest
+// match to the generator's class-fuel-band groups
+func DefaultConfig() Config {
+	return Config{PeerGroup: models.PeerGroupSegmentFuel}
+}
+
+// ValidPeerGroup reports whether g is a known peer group
+func ValidPeerGroup(g string) bool {
+	return slices.Contains(models.PeerGroups, g)
+}
+
+// PeerKey returns the peer group key of a vehicle
+func PeerKey(v market.Vehicle, group string) string {
+	switch group {
+	case models.PeerGroupSegment:
+		return v.Segment().Code
+	case models.PeerGroupPriceBand:
+		return market.PriceBand(v.Price())
+	default:
+		return v.Segment().Code + "/" + v.Fuel()
+	}
+}
+
+// peerStats are the statistics of one peer group
+type peerStats struct {
+	size     int
+	price    market.Stats
+	discount market.Stats // campaign discounts, 0 without a campaign
+	perHP    market.Stats
+	perKm    market.Stats
+	anyDisc  bool
+}
+
+// Score scores every vehicle against its peers; the result is in input order
+func Score(vehicles []market.Vehicle, cfg Config) []models.VehicleWithScore {
+	groups := make(map[string][]int)
+	for i, v := range vehicles {
+		key := PeerKey(v, cfg.PeerGroup)
+		groups[key] = append(groups[key], i)
+	}
+
+	stats := make(map[string]peerStats, len(groups))
+	for key, members := range groups {
+		var prices, discounts, perHP, perKm []float64
+		anyDisc := false
+		for _, i := range members {
+			v := vehicles[i]
+			prices = append(prices, v.Price())
+			d := campaignDiscount(v.Row)
+			discounts = append(discounts, d)
+			anyDisc = anyDisc || d > 0
+			if hp := tlPerHP(v); hp > 0 {
+				perHP = append(perHP, hp)
+			}
+			if km := tlPerKm(v); km > 0 {
+				perKm = append(perKm, km)
+			}
+		}
+		stats[key] = peerStats{
+			size:     len(members),
+			price:    market.NewStats(prices),
+			discount: market.NewStats(discounts),
+			perHP:    market.NewStats(perHP),
+			perKm:    market.NewStats(perKm),
+			anyDisc:  anyDisc,
+		}
+	}
+
+	scored := make([]models.VehicleWithScore, len(vehicles))
+	for i, v := range vehicles {
+		key := PeerKey(v, cfg.PeerGroup)
+		scored[i] = score(v, key, stats[key], cfg.Weights)
+	}
+	return scored
+}
+
+func score(v market.Vehicle, key string, ps peerStats, w models.ScoreWeights) models.VehicleWithScore {
+	row := v.Row
+	price := v.Price()
+	small := ps.size < MinPeers
+
+	z := ps.price.ZScore(price)
+	percentile := ps.price.Percentile(price)
+	isOutlier := !small && math.Abs(z) > outlierZ
+	var outlierType *string
+	if isOutlier {
+		t := "expensive"
+		if z < 0 {
+			t = "cheap"
+		}
+		outlierType = &t
+	}
+
+	// Small groups map z in [-2, 2] onto [100, 0]; larger ones use the percentile
+	components := models.ScoreComponents{Price: clamp(math.Round(100 - percentile))}
+	if small {
+		components.Price = clamp(math.Round(50 - z*25))
+	}
+	total, weight := components.Price, 1.0
+
+	discount := campaignDiscount(row)
+	if ps.anyDisc {
+		c := ps.discount.Percentile(discount)
+		components.CampaignDiscount = &c
+		total, weight = total+w.CampaignDiscount*c, weight+w.CampaignDiscount
+	}
+	perHP := tlPerHP(v)
+	if perHP > 0 {
+		c := 100 - ps.perHP.Percentile(perHP)
+		components.TLPerHP = &c
+		total, weight = total+w.TLPerHP*c, weight+w.TLPerHP
+	}
+	perKm := tlPerKm(v)
+	if perKm > 0 {
+		c := 100 - ps.perKm.Percentile(perKm)
+		components.TLPerKm = &c
+		total, weight = total+w.TLPerKm*c, weight+w.TLPerKm
+	}
+
+	seg := v.Segment()
+	out := models.VehicleWithScore{
+		ID:             v.VariantID,
+		Brand:          row.Brand,
+		BrandID:        v.BrandID,
+		Model:          row.Model,
+		Trim:           row.Trim,
+		Engine:         row.Engine,
+		Fuel:           row.Canonical.Fuel,
+		Transmission:   row.Canonical.Transmission,
+		VehicleClass:   seg.Code,
+		PriceBand:      market.PriceBand(price),
+		Price:          price,
+		PriceFormatted: market.FormatTL(price),
+		DealScore:      clamp(math.Round(total / weight)),
+		ZScore:         market.Round(z, 2),
+		Percentile:     percentile,
+		SegmentAvg:     math.Round(ps.price.Mean),
+		SegmentSize:    ps.size,
+		IsOutlier:      isOutlier,
+		OutlierType:    outlierType,
+
+		OtvRate:            row.OtvRate,
+		ModelYear:          row.ModelYear,
+		FuelConsumption:    row.FuelConsumption,
+		MonthlyLease:       row.MonthlyLease,
+		PowerHP:            row.PowerHP,
+		PowerKW:            row.PowerKW,
+		EngineDisplacement: row.EngineDisplacement,
+		DriveType:          row.DriveType,
+		WltpRange:          row.WltpRange,
+		BatteryCapacity:    row.BatteryCapacity,
+		HasLongRange:       row.HasLongRange,
+		IsMildHybrid:       row.IsMildHybrid,
+		IsPlugInHybrid:     row.IsPlugInHybrid,
+		IsElectric:         row.IsElectric,
+		IsHybrid:           row.IsHybrid,
+
+		Segment:    &seg,
+		PeerKey:    key,
+		Components: &components,
+	}
+	if discount > 0 {
+		out.CampaignDiscount = &discount
+	}
+	if row.Metrics != nil {
+		out.TlPerHP, out.TlPerKm = row.Metrics.TLPerHP, row.Metrics.TLPerKm
+	}
+	return out
+}
+
+// campaignDiscount returns the campaign discount off the list price in
+// percent (one decimal), 0 without a campaign
+func campaignDiscount(row models.PriceListRow) float64 {
+	if row.PriceListNumeric == nil || row.PriceCampaignNumeric == nil {
+		return 0
+	}
+	list, campaign := *row.PriceListNumeric, *row.PriceCampaignNumeric
+	if campaign <= 0 || list <= campaign {
+		return 0
+	}
+	return market.Round((list-campaign)/list*100, 1)
+}
+
+// tlPerHP returns price per horsepower, 0 when the power is unknown
+func tlPerHP(v market.Vehicle) float64 {
+	perHP, _ := market.Metric(v.Row.Metrics, models.MetricTLPerHP)
+	return perHP
+}
+
+// tlPerKm returns an electric vehicle's price per km of WLTP range, 0 otherwise
+func tlPerKm(v market.Vehicle) float64 {
+	perKm, _ := market.Metric(v.Row.Metrics, models.MetricTLPerKm)
+	return perKm
+}
+
+func clamp(score float64) float64 {
+	return math.Max(0, math.Min(100, score))
+}
+
+// Summary holds the market-wide lists of the insights document
+type Summary struct {
+	TopDeals          []models.VehicleWithScore
+	CheapOutliers     []models.VehicleWithScore
+	ExpensiveOutliers []models.VehicleWithScore
+}
+
+// Summarize picks the 20 best deals and the 10 strongest outliers each way
+func Summarize(scored []models.VehicleWithScore) Summary {
+	var s Summary
+	s.TopDeals = append([]models.VehicleWithScore(nil), scored...)
+	sort.SliceStable(s.TopDeals, func(i, j int) bool { return s.TopDeals[i].DealScore > s.TopDeals[j].DealScore })
+	s.TopDeals = s.TopDeals[:min(20, len(s.TopDeals))]
+
+	for _, v := range scored {
+		if v.OutlierType == nil {
+			continue
+		}
+		if *v.OutlierType == "cheap" {
+			s.CheapOutliers = append(s.CheapOutliers, v)
+		} else {
+			s.ExpensiveOutliers = append(s.ExpensiveOutliers, v)
+		}
+	}
+	sort.SliceStable(s.CheapOutliers, func(i, j int) bool { return s.CheapOutliers[i].ZScore < s.CheapOutliers[j].ZScore })
+	sort.SliceStable(s.ExpensiveOutliers, func(i, j int) bool { return s.ExpensiveOutliers[i].ZScore > s.ExpensiveOutliers[j].ZScore })
+	s.CheapOutliers = s.CheapOutliers[:min(10, len(s.CheapOutliers))]
+	s.ExpensiveOutliers = s.ExpensiveOutliers[:min(10, len(s.ExpensiveOutliers))]
+	return s
+}
+
+// Peers returns the vehicles sharing a peer key, best deal first
+func Peers(scored []models.VehicleWithScore, key string) []models.VehicleWithScore {
+	var peers []models.VehicleWithScore
+	for _, v := range scored {
+		if v.PeerKey == key {
+			peers = append(peers, v)
+		}
+	}
+	sort.SliceStable(peers, func(i, j int) bool { return peers[i].DealScore > peers[j].DealScore })
+	return peers
+}
@@ -0,0 +1,256 @@
+// Package segment classifies price list rows into market segments: a size
+// class (A–F) and a body type, written B-SUV, C-hatch, D-sedan, or LCV for
+// light commercials. Known models come from a curated table; anything else,
+// typically a launch the table has not caught up with, falls back to
+// heuristics on the vehicle category, cargo volume, seating and price.
+package segment
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"github.com/spehlivan/price-list/backend/internal/variant"
+)
+
+// Input is what the classifier looks at. Rows carry their brand only as a
+// display name, so the brand id is passed alongside.
+type Input struct {
+	BrandID         string
+	Model           string
+	Trim            string
+	VehicleCategory string
+	CargoVolume     float64 // m³
+	SeatingCapacity string
+	Price           float64 // the model's entry price when known, so all trims share a size
+}
+
+// FromRow builds the classifier input of a row
+func FromRow(brandID string, row models.PriceListRow) Input {
+	in := Input{BrandID: brandID, Model: row.Model, Trim: row.Trim, Price: row.PriceNumeric}
+	if row.VehicleCategory != nil {
+		in.VehicleCategory = *row.VehicleCategory
+	}
+	if row.CargoVolume != nil {
+		in.CargoVolume = *row.CargoVolume
+	}
+	if row.SeatingCapacity != nil {
+		in.SeatingCapacity = *row.SeatingCapacity
+	}
+	return in
+}
+
+// Code returns the segment code of a size and body, e.g. "C-SUV" or "LCV"
+func Code(size, body string) string {
+	if body == models.BodyLCV || size == "" {
+		return body
+	}
+	return size + "-" + body
+}
+
+// Classify assigns a segment to a vehicle
+func Classify(in Input) models.Segment {
+	tokens := strings.Fields(variant.Normalize(in.Model))
+	if e, ok := lookup(in.BrandID, tokens); ok {
+		return models.Segment{Code: Code(e.size, e.body), Size: e.size, Body: e.body, Source: models.SegmentSourceTable}
+	}
+	return heuristic(in, tokens)
+}
+
+// Annotate sets the segment of every row of a brand, pricing each model at
+// its entry price among the rows
+func Annotate(brandID string, rows []models.PriceListRow) {
+	entry := make(map[string]float64)
+	for _, row := range rows {
+		if p, ok := entry[row.Model]; row.PriceNumeric > 0 && (!ok || row.PriceNumeric < p) {
+			entry[row.Model] = row.PriceNumeric
+		}
+	}
+	for i := range rows {
+		in := FromRow(brandID, rows[i])
+		in.Price = entry[rows[i].Model]
+		s := Classify(in)
+		rows[i].Segment = &s
+	}
+}
+
+// lookup finds the longest table family matching the model tokens; ties go
+// to the family that matches earliest, so "C 63 S E" is a C-Class. Without a
+// brand id every brand's families are tried.
+func lookup(brandID string, tokens []string) (entry, bool) {
+	var candidates [][]entry
+	if brandID != "" {
+		candidates = append(candidates, table[brandID])
+	} else {
+		for _, entries := range table {
+			candidates = append(candidates, entries)
+		}
+	}
+
+	var best entry
+	bestLen, bestPos := 0, 0
+	for _, entries := range candidates {
+		for _, e := range entries {
+			pattern := strings.Fields(e.family)
+			pos, ok := match(pattern, tokens)
+			if !ok {
+				continue
+			}
+			if len(pattern) > bestLen || (len(pattern) == bestLen && pos < bestPos) {
+				best, bestLen, bestPos = e, len(pattern), pos
+			}
+		}
+	}
+	return best, bestLen > 0
+}
+
+// match reports whether pattern is an in-order subsequence of tokens and
+// where its first token matched
+func match(pattern, tokens []string) (int, bool) {
+	first, i := -1, 0
+	for j, t := range tokens {
+		if i == len(pattern) {
+			break
+		}
+		p := pattern[i]
+		if t == p || (strings.HasSuffix(p, "*") && strings.HasPrefix(t, strings.TrimSuffix(p, "*"))) {
+			if i == 0 {
+				first = j
+			}
+			i++
+		}
+	}
+	return first, i == len(pattern)
+}
+
+// Keywords in model and trim names, normalized
+var (
+	lcvWords    = []string{"van", "panelvan", "cargo", "kargo", "kamyonet", "kamyon", "pickup", "pick", "chassis", "sasi", "minibus"}
+	sedanWords  = []string{"sedan", "limousine"}
+	wagonWords  = []string{"touring", "estate", "sw", "wagon", "variant", "avant", "sportstourer", "sportswagon"}
+	coupeWords  = []string{"coupe", "fastback"}
+	cabrioWords = []string{"cabrio", "cabriolet", "convertible", "roadster", "spider", "spyder"}
+	suvWords    = []string{"suv", "cross", "aircross", "crossback", "stepway", "allroad"}
+	mpvWords    = []string{"mpv", "tourer", "traveller", "kombi"}
+)
+
+// sizeBands are the upper price bounds (TL) of sizes A–E; anything above is F
+var sizeBands = []float64{1_500_000, 2_200_000, 3_200_000, 5_000_000, 10_000_000}
+
+// heuristic classifies a model missing from the table. Commercial markers win
+// over body markers; the size follows from the price.
+func heuristic(in Input, tokens []string) models.Segment {
+	words := slices.Concat(tokens, strings.Fields(variant.Normalize(in.Trim)))
+	has := func(list []string) bool {
+		return slices.ContainsFunc(words, func(w string) bool { return slices.Contains(list, w) })
+	}
+	seg := models.Segment{Source: models.SegmentSourceHeuristic}
+
+	category := variant.Normalize(in.VehicleCategory)
+	switch {
+	case category == "ticari", in.CargoVolume >= 2, strings.HasPrefix(in.SeatingCapacity, "8+"), has(lcvWords):
+		seg.Body = models.BodyLCV
+	case has(cabrioWords):
+		seg.Body = models.BodyCabrio
+	case has(coupeWords):
+		seg.Body = models.BodyCoupe
+	case has(wagonWords):
+		seg.Body = models.BodyWagon
+	case category == "suv", has(suvWords):
+		seg.Body = models.BodySUV
+	case category == "sedan", has(sedanWords):
+		seg.Body = models.BodySedan
+	case category == "kombi", has(mpvWords):
+		seg.Body = models.BodyMPV
+	default:
+		seg.Body = models.BodyHatch
+	}
+
+	if seg.Body != models.BodyLCV {
+		seg.Size = models.SizeF
+		for i, bound := range sizeBands {
+			if in.Price > 0 && in.Price < bound {
+				seg.Size = models.SegmentSizes[i]
+				break
+			}
+		}
+	}
+	seg.Code = Code(seg.Size, seg.Body)
+	return seg
+}
+
+// Filter selects segments by code ("C-SUV"), size ("C") or body ("SUV"),
+// case-insensitively; terms are alternatives
+type Filter struct {
+	terms []string
+}
+
+// ParseFilter parses a comma-separated list of segment codes, sizes and body
+// types. An empty string yields a filter that matches everything.
+func ParseFilter(s string) (Filter, error) {
+	var f Filter
+	for _, term := range strings.Split(s, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		if !valid(term) {
+			return Filter{}, fmt.Errorf("unknown segment %q", term)
+		}
+		f.terms = append(f.terms, strings.ToLower(term))
+	}
+	return f, nil
+}
+
+// valid reports whether a term names a size, a body type or a segment code
+func valid(term string) bool {
+	for _, size := range models.SegmentSizes {
+		if strings.EqualFold(term, size) {
+			return true
+		}
+	}
+	for _, body := range models.SegmentBodies {
+		if strings.EqualFold(term, body) {
+			return true
+		}
+		for _, size := range models.SegmentSizes {
+			if body != models.BodyLCV && strings.EqualFold(term, Code(size, body)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Empty reports whether the filter matches everything
+func (f Filter) Empty() bool {
+	return len(f.terms) == 0
+}
+
+// Match reports whether a segment is selected
+func (f Filter) Match(s models.Segment) bool {
+	if f.Empty() {
+		return true
+	}
+	for _, term := range f.terms {
+		if term == strings.ToLower(s.Code) || term == strings.ToLower(s.Size) || term == strings.ToLower(s.Body) {
+			return true
+		}
+	}
+	return false
+}
+
+// Rows returns the rows whose segment is selected; rows must be annotated
+func (f Filter) Rows(rows []models.PriceListRow) []models.PriceListRow {
+	if f.Empty() {
+		return rows
+	}
+	kept := make([]models.PriceListRow, 0, len(rows))
+	for _, row := range rows {
+		if row.Segment != nil && f.Match(*row.Segment) {
+			kept = append(kept, row)
+		}
+	}
+	return kept
+}
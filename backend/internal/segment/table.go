@@ -0,0 +1,309 @@
+package segment
+
+import "github.com/spehlivan/price-list/backend/internal/models"
+
+// entry assigns a segment to a model family. Family is a normalized token
+// pattern (see variant.Normalize) matched as an in-order subsequence of the
+// model's tokens; a trailing * matches any token with that prefix, so
+// "4* gran coupe" covers "BMW 420i Gran Coupé".
+type entry struct {
+	family string
+	size   string
+	body   string
+}
+
+const (
+	a = models.SizeA
+	b = models.SizeB
+	c = models.SizeC
+	d = models.SizeD
+	e = models.SizeE
+	f = models.SizeF
+
+	hatch  = models.BodyHatch
+	sedan  = models.BodySedan
+	wagon  = models.BodyWagon
+	suv    = models.BodySUV
+	mpv    = models.BodyMPV
+	coupe  = models.BodyCoupe
+	cabrio = models.BodyCabrio
+	lcv    = models.BodyLCV
+)
+
+// table is the curated model table per brand id. The longest matching family
+// wins, so specific body styles ("m3 touring") override the base model.
+var table = map[string][]entry{
+	"bmw": {
+		{"1*", c, hatch},
+		{"2* gran coupe", c, sedan},
+		{"2* active tourer", c, mpv},
+		{"3*", d, sedan},
+		{"4* coupe", d, coupe},
+		{"4* gran coupe", d, sedan},
+		{"4* cabrio", d, cabrio},
+		{"5*", e, sedan},
+		{"5* touring", e, wagon},
+		{"7*", f, sedan},
+		{"m2", c, coupe},
+		{"m3", d, sedan},
+		{"m3 touring", d, wagon},
+		{"m4", d, coupe},
+		{"m4 cabrio", d, cabrio},
+		{"m5", e, sedan},
+		{"m5 touring", e, wagon},
+		{"i4", d, sedan},
+		{"i5", e, sedan},
+		{"i5 touring", e, wagon},
+		{"i7", f, sedan},
+		{"x1", c, suv},
+		{"ix1", c, suv},
+		{"x2", c, suv},
+		{"ix2", c, suv},
+		{"x3", d, suv},
+		{"ix3", d, suv},
+		{"x4", d, suv},
+		{"x5", e, suv},
+		{"x6", e, suv},
+		{"x7", f, suv},
+		{"ix", e, suv},
+		{"z4", d, cabrio},
+	},
+	"byd": {
+		{"atto 3", c, suv},
+		{"dolphin", c, hatch},
+		{"han", e, sedan},
+		{"seal", d, sedan},
+		{"seal u", d, suv},
+		{"sealion 7", d, suv},
+		{"tang", e, suv},
+	},
+	"citroen": {
+		{"ami", a, hatch},
+		{"c3", b, hatch},
+		{"c3 aircross", b, suv},
+		{"c4", c, hatch},
+		{"c4 x", c, sedan},
+		{"c5 aircross", c, suv},
+		{"berlingo", "", lcv},
+		{"jumper", "", lcv},
+		{"jumpy", "", lcv},
+		{"spacetourer", "", lcv},
+	},
+	"dacia": {
+		{"spring", a, hatch},
+		{"sandero", b, hatch},
+		{"logan", b, sedan},
+		{"duster", b, suv},
+		{"bigster", c, suv},
+		{"jogger", c, mpv},
+	},
+	"fiat": {
+		{"500e", a, hatch},
+		{"panda", a, hatch},
+		{"topolino", a, hatch},
+		{"grande panda", b, hatch},
+		{"600", b, suv},
+		{"egea", c, hatch},
+		{"egea sedan", c, sedan},
+		{"egea cross", c, suv},
+		{"doblo", "", lcv},
+		{"fiorino", "", lcv},
+		{"ducato", "", lcv},
+		{"scudo", "", lcv},
+	},
+	"ford": {
+		{"fiesta", b, hatch},
+		{"puma", b, suv},
+		{"focus", c, hatch},
+		{"kuga", c, suv},
+		{"mustang mach e", d, suv},
+		{"journey courier", b, mpv},
+		{"tourneo courier", b, mpv},
+		{"transit", "", lcv},
+		{"ranger", "", lcv},
+	},
+	"honda": {
+		{"jazz", b, hatch},
+		{"hr v", b, suv},
+		{"civic", c, hatch},
+		{"zr v", c, suv},
+		{"cr v", d, suv},
+		{"prelude", d, coupe},
+	},
+	"hyundai": {
+		{"i10", a, hatch},
+		{"inster", a, suv},
+		{"i20", b, hatch},
+		{"bayon", b, suv},
+		{"kona", b, suv},
+		{"i30", c, hatch},
+		{"elantra", c, sedan},
+		{"tucson", c, suv},
+		{"ioniq 5", c, suv},
+		{"ioniq 6", d, sedan},
+		{"santa fe", e, suv},
+		{"ioniq 9", e, suv},
+		{"staria", e, mpv},
+	},
+	"kia": {
+		{"picanto", a, hatch},
+		{"stonic", b, suv},
+		{"ev3", b, suv},
+		{"cee", c, hatch},
+		{"ceed", c, hatch},
+		{"xceed", c, suv},
+		{"niro", c, suv},
+		{"sportage", c, suv},
+		{"ev6", d, suv},
+		{"sorento", e, suv},
+		{"ev9", e, suv},
+	},
+	"mercedes": {
+		{"a", c, hatch},
+		{"cla", c, sedan},
+		{"gla", c, suv},
+		{"glb", c, suv},
+		{"eqa", c, suv},
+		{"eqb", c, suv},
+		{"c", d, sedan},
+		{"c estate", d, wagon},
+		{"c all terrain", d, wagon},
+		{"cle", d, coupe},
+		{"cle cabriolet", d, cabrio},
+		{"glc", d, suv},
+		{"e", e, sedan},
+		{"e estate", e, wagon},
+		{"eqe", e, sedan},
+		{"gle", e, suv},
+		{"s", f, sedan},
+		{"eqs", f, sedan},
+		{"gls", f, suv},
+		{"g", f, suv},
+		{"gt", f, coupe},
+		{"sl", f, cabrio},
+		{"vito", "", lcv},
+		{"sprinter", "", lcv},
+		{"v", e, mpv},
+	},
+	"nissan": {
+		{"micra", b, hatch},
+		{"juke", b, suv},
+		{"qashqai", c, suv},
+		{"x trail", d, suv},
+		{"townstar", "", lcv},
+		{"primastar", "", lcv},
+		{"navara", "", lcv},
+	},
+	"opel": {
+		{"corsa", b, hatch},
+		{"frontera", b, suv},
+		{"mokka", b, suv},
+		{"astra", c, hatch},
+		{"astra sports tourer", c, wagon},
+		{"grandland", c, suv},
+		{"combo", "", lcv},
+		{"vivaro", "", lcv},
+		{"movano", "", lcv},
+	},
+	"peugeot": {
+		{"208", b, hatch},
+		{"2008", b, suv},
+		{"308", c, hatch},
+		{"308 sw", c, wagon},
+		{"408", c, sedan},
+		{"3008", c, suv},
+		{"5008", d, suv},
+		{"508", d, sedan},
+		{"partner", "", lcv},
+		{"rifter", "", lcv},
+		{"expert", "", lcv},
+		{"boxer", "", lcv},
+	},
+	"renault": {
+		{"twingo", a, hatch},
+		{"clio", b, hatch},
+		{"r5", b, hatch},
+		{"taliant", b, sedan},
+		{"captur", b, suv},
+		{"duster", b, suv},
+		{"megane", c, hatch},
+		{"megane sedan", c, sedan},
+		{"megane e tech", c, suv},
+		{"scenic", c, suv},
+		{"austral", c, suv},
+		{"boreal", c, suv},
+		{"rafale", d, suv},
+		{"kangoo", "", lcv},
+		{"trafic", "", lcv},
+		{"master", "", lcv},
+	},
+	"seat": {
+		{"ibiza", b, hatch},
+		{"arona", b, suv},
+		{"leon", c, hatch},
+		{"leon sportstourer", c, wagon},
+		{"ateca", c, suv},
+	},
+	"skoda": {
+		{"fabia", b, hatch},
+		{"kamiq", b, suv},
+		{"scala", c, hatch},
+		{"octavia", c, sedan},
+		{"octavia combi", c, wagon},
+		{"karoq", c, suv},
+		{"elroq", c, suv},
+		{"kodiaq", d, suv},
+		{"enyaq", d, suv},
+		{"superb", d, sedan},
+		{"superb combi", d, wagon},
+	},
+	"toyota": {
+		{"aygo", a, hatch},
+		{"yaris", b, hatch},
+		{"yaris cross", b, suv},
+		{"corolla", c, sedan},
+		{"corolla hatchback", c, hatch},
+		{"corolla touring", c, wagon},
+		{"corolla cross", c, suv},
+		{"c hr", c, suv},
+		{"rav4", d, suv},
+		{"camry", d, sedan},
+		{"land cruiser", e, suv},
+		{"hilux", "", lcv},
+		{"proace", "", lcv},
+	},
+	"volkswagen": {
+		{"polo", b, hatch},
+		{"t cross", b, suv},
+		{"taigo", b, suv},
+		{"golf", c, hatch},
+		{"golf variant", c, wagon},
+		{"t roc", c, suv},
+		{"tiguan", c, suv},
+		{"id.3", c, hatch},
+		{"id.4", c, suv},
+		{"id.5", c, suv},
+		{"passat", d, wagon},
+		{"id.7", d, sedan},
+		{"tayron", d, suv},
+		{"touareg", e, suv},
+		{"caddy", "", lcv},
+		{"transporter", "", lcv},
+		{"caravelle", "", lcv},
+		{"crafter", "", lcv},
+		{"amarok", "", lcv},
+	},
+	"volvo": {
+		{"ex30", b, suv},
+		{"xc40", c, suv},
+		{"ex40", c, suv},
+		{"ec40", c, suv},
+		{"s60", d, sedan},
+		{"v60", d, wagon},
+		{"xc60", d, suv},
+		{"s90", e, sedan},
+		{"v90", e, wagon},
+		{"xc90", e, suv},
+		{"ex90", e, suv},
+	},
+}
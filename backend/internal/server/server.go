@@ -0,0 +1,190 @@
+// Package server wires repositories, handlers and middleware into the HTTP
+// router. main connects to MongoDB and serves it; tests build the same router
+// to check the registered routes against the OpenAPI spec.
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spehlivan/price-list/backend/config"
+	"github.com/spehlivan/price-list/backend/internal/cache"
+	"github.com/spehlivan/price-list/backend/internal/handlers"
+	"github.com/spehlivan/price-list/backend/internal/middleware"
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"github.com/spehlivan/price-list/backend/internal/openapi"
+	"github.com/spehlivan/price-list/backend/internal/ratelimit"
+	"github.com/spehlivan/price-list/backend/internal/repository"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// Rate limit cost per route, roughly proportional to the work behind it
+const (
+	costLight  = 1  // single-document lookups
+	costTrend  = 3  // unwinds recent snapshots of one brand
+	costIndex  = 5  // groups every snapshot
+	costLatest = 10 // returns the newest snapshot of every brand
+)
+
+type indexer interface {
+	EnsureIndexes(ctx context.Context) error
+}
+
+// EnsureIndexes creates the indexes of every collection the API uses. Failures
+// are logged and do not stop startup.
+func EnsureIndexes(ctx context.Context, cfg *config.Config, db *mongo.Database) {
+	repos := []struct {
+		name string
+		repo indexer
+	}{
+		{"vehicle", repository.NewVehicleRepository(db)},
+		{"stats", repository.NewStatsRepository(db)},
+		{"intel", repository.NewIntelRepository(db)},
+		{"API key", repository.NewAPIKeyRepository(db)},
+		{"quarantine", repository.NewQuarantineRepository(db)},
+		{"anomaly", repository.NewAnomalyRepository(db)},
+		{"collection health", repository.NewCollectionHealthRepository(db)},
+		{"error log", repository.NewErrorLogRepository(db)},
+		{"variant alias", repository.NewVariantRepository(db)},
+		{"vocabulary", repository.NewVocabularyRepository(db)},
+		{"campaign", repository.NewCampaignRepository(db)},
+		{"model year", repository.NewModelYearRepository(db)},
+	}
+	if cfg.RateLimitStore == "mongo" {
+		repos = append(repos, struct {
+			name string
+			repo indexer
+		}{"rate limit", repository.NewRateLimitRepository(db)})
+	}
+	for _, r := range repos {
+		if err := r.repo.EnsureIndexes(ctx); err != nil {
+			log.Printf("Warning: Failed to ensure %s indexes: %v", r.name, err)
+		}
+	}
+}
+
+// NewRouter registers every API route. The database is only queried once
+// requests are served.
+func NewRouter(cfg *config.Config, db *mongo.Database, spec *openapi.Document) (*gin.Engine, error) {
+	// Initialize repositories
+	vehicleRepo := repository.NewVehicleRepository(db)
+	statsRepo := repository.NewStatsRepository(db)
+	intelRepo := repository.NewIntelRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	quarantineRepo := repository.NewQuarantineRepository(db)
+	anomalyRepo := repository.NewAnomalyRepository(db)
+	collectionHealthRepo := repository.NewCollectionHealthRepository(db)
+	errorLogRepo := repository.NewErrorLogRepository(db)
+	variantRepo := repository.NewVariantRepository(db)
+	vocabularyRepo := repository.NewVocabularyRepository(db)
+	campaignRepo := repository.NewCampaignRepository(db)
+	modelYearRepo := repository.NewModelYearRepository(db)
+
+	// Initialize handlers
+	healthHandler := handlers.NewHealthHandler(collectionHealthRepo)
+	// Vehicle responses are memoized until a new snapshot is imported
+	vehicleCache := cache.New(vehicleRepo.DataVersion, 10*time.Second, 512)
+	vehicleHandler := handlers.NewVehicleHandler(vehicleRepo, variantRepo, vehicleCache)
+	statsHandler := handlers.NewStatsHandler(statsRepo, vocabularyRepo)
+	intelHandler := handlers.NewIntelHandler(intelRepo)
+	openapiHandler := handlers.NewOpenAPIHandler(spec)
+	adminHandler := handlers.NewAdminHandler(apiKeyRepo, quarantineRepo)
+	anomalyHandler := handlers.NewAnomalyHandler(anomalyRepo)
+	campaignHandler := handlers.NewCampaignHandler(campaignRepo)
+	modelYearHandler := handlers.NewModelYearHandler(modelYearRepo)
+	errorHandler := handlers.NewErrorHandler(errorLogRepo)
+	vocabularyHandler := handlers.NewVocabularyHandler(vocabularyRepo)
+	marketHandler := handlers.NewMarketHandler(vehicleRepo, vocabularyRepo, vehicleCache)
+	apiKeyAuth := middleware.NewAPIKeyAuth(apiKeyRepo, cfg.APIKeysRequired)
+
+	// Rate limiting
+	var rateStore ratelimit.Store
+	switch cfg.RateLimitStore {
+	case "off":
+	case "mongo":
+		rateStore = repository.NewRateLimitRepository(db)
+	default:
+		rateStore = ratelimit.NewMemoryStore()
+	}
+	limiter := middleware.NewRateLimiter(rateStore,
+		ratelimit.Limit{Burst: cfg.RateLimitBurst, PerSecond: cfg.RateLimitPerSecond},
+		ratelimit.Limit{Burst: cfg.RateLimitKeyBurst, PerSecond: cfg.RateLimitKeyPerSecond},
+		apiKeyAuth.CountUsage,
+	)
+
+	// Setup router
+	r := gin.Default()
+
+	// Only the configured proxies may set the client IP that anonymous
+	// requests are rate limited by
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("trusted proxies: %w", err)
+	}
+
+	// CORS middleware
+	r.Use(middleware.CORSMiddleware(cfg.CORSOrigins))
+
+	// API v1 routes
+	v1 := r.Group("/api/v1")
+	{
+		v1.GET("/health", healthHandler.Health)
+		v1.GET("/openapi.json", openapiHandler.GetSpec)
+
+		// Everything below is authenticated by API key when one is sent
+		v1.Use(apiKeyAuth.Authenticate())
+
+		vehicles := v1.Group("", apiKeyAuth.RequireScope(models.ScopeReadVehicles))
+		{
+			vehicles.GET("/index", limiter.Cost(costIndex), vehicleHandler.GetIndex)
+			vehicles.GET("/latest", limiter.Cost(costLatest), vehicleHandler.GetLatest)
+			vehicles.GET("/vehicles", limiter.Cost(costLight), vehicleHandler.GetVehicles)
+			vehicles.GET("/trend", limiter.Cost(costTrend), vehicleHandler.GetTrend)
+			vehicles.GET("/forecast", limiter.Cost(costTrend), vehicleHandler.GetForecast)
+			vehicles.GET("/stats", limiter.Cost(costLight), statsHandler.GetStats)
+			vehicles.GET("/coverage", limiter.Cost(costIndex), vehicleHandler.GetCoverage)
+			vehicles.GET("/coverage/staleness", limiter.Cost(costLatest), vehicleHandler.GetStaleness)
+			vehicles.GET("/rankings", limiter.Cost(costLatest), marketHandler.GetRankings)
+		}
+
+		intelRead := v1.Group("", apiKeyAuth.RequireScope(models.ScopeReadIntel))
+		{
+			// Intel routes
+			intel := intelRead.Group("/intel")
+			{
+				intel.GET("/events", limiter.Cost(costLight), intelHandler.GetEvents)
+				intel.GET("/architecture", limiter.Cost(costLight), intelHandler.GetArchitecture)
+				intel.GET("/architecture/:brand/:model", limiter.Cost(costTrend), marketHandler.GetModelArchitecture)
+				intel.GET("/gaps", limiter.Cost(costLight), intelHandler.GetGaps)
+				intel.GET("/gaps/analyze", limiter.Cost(costLatest), marketHandler.GetGapAnalysis)
+				intel.GET("/promos", limiter.Cost(costLight), intelHandler.GetPromos)
+				intel.GET("/lifecycle", limiter.Cost(costLight), intelHandler.GetLifecycle)
+				intel.GET("/lifecycle/transitions", limiter.Cost(costLight), modelYearHandler.GetModelYearChanges)
+				intel.GET("/reactions", limiter.Cost(costLatest), marketHandler.GetReactions)
+			}
+
+			intelRead.GET("/errors", limiter.Cost(costLight), errorHandler.GetErrors)
+			intelRead.GET("/anomalies", limiter.Cost(costLight), anomalyHandler.GetAnomalies)
+			intelRead.GET("/campaigns", limiter.Cost(costLight), campaignHandler.GetCampaigns)
+			intelRead.GET("/health/collection", limiter.Cost(costLight), healthHandler.CollectionHealth)
+			intelRead.GET("/insights", limiter.Cost(costLight), intelHandler.GetInsights)
+			intelRead.GET("/insights/score", limiter.Cost(costLatest), marketHandler.GetScore)
+		}
+
+		// Admin routes
+		admin := v1.Group("/admin", apiKeyAuth.RequireScope(models.ScopeAdmin))
+		{
+			admin.GET("/keys", limiter.Cost(costLight), adminHandler.ListKeys)
+			admin.GET("/usage", limiter.Cost(costLight), adminHandler.GetUsage)
+			admin.GET("/quarantine", limiter.Cost(costLight), adminHandler.GetQuarantine)
+			admin.GET("/vocabulary", limiter.Cost(costLight), vocabularyHandler.GetVocabulary)
+			admin.PUT("/vocabulary", limiter.Cost(costIndex), vocabularyHandler.PutVocabulary)
+			admin.DELETE("/vocabulary", limiter.Cost(costIndex), vocabularyHandler.DeleteVocabulary)
+			admin.POST("/vocabulary/restamp", limiter.Cost(costLatest), vocabularyHandler.RestampVocabulary)
+		}
+	}
+
+	return r, nil
+}
@@ -0,0 +1,199 @@
+// Package staleness finds price lists that are still collected but no longer
+// move. Stale lists skew trend and volatility figures: a source scraped
+// successfully every day can serve the same prices for months.
+package staleness
+
+import (
+	"sort"
+	"time"
+
+	"github.com/spehlivan/price-list/backend/internal/market"
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"github.com/spehlivan/price-list/backend/internal/segment"
+	"github.com/spehlivan/price-list/backend/internal/variant"
+)
+
+const (
+	// MinChangePercent is the smallest price move counted as a change;
+	// smaller moves are rounding in the source, not repricing
+	MinChangePercent = 0.1
+	// DefaultDays is the window without changes that makes a list stale
+	DefaultDays = 30
+	// MaxDays caps the window
+	MaxDays = 365
+	// HistoryDays is how far back the snapshots are read; it exceeds MaxDays so
+	// every window has a comparison snapshot, and caps DaysSinceChange
+	HistoryDays = MaxDays + 35
+)
+
+const dateLayout = "2006-01-02"
+
+// Analyze measures one brand's snapshots, sorted by date, against a window of
+// days ending at the newest snapshot
+func Analyze(snapshots []models.VehicleDocument, days int) models.BrandStaleness {
+	if len(snapshots) == 0 {
+		return models.BrandStaleness{Models: []models.ModelStaleness{}}
+	}
+	brandID := snapshots[0].BrandID
+	latest := snapshots[len(snapshots)-1]
+	res := models.BrandStaleness{
+		BrandID:    brandID,
+		Brand:      latest.Brand,
+		FirstDate:  snapshots[0].Date,
+		LatestDate: latest.Date,
+		Models:     []models.ModelStaleness{},
+	}
+	cutoff := shift(latest.Date, -days)
+
+	prices := make(map[string]float64)    // newest price per variant
+	lastChange := make(map[string]string) // per variant, a listing after the first snapshot included
+	var compare map[string]float64        // prices on CompareDate
+	var listed map[string]bool
+	for i, snap := range snapshots {
+		ids := make(map[string]bool, len(snap.Rows))
+		for _, row := range snap.Rows {
+			if row.PriceNumeric <= 0 {
+				continue
+			}
+			id := rowID(brandID, row)
+			if ids[id] {
+				continue
+			}
+			ids[id] = true
+			prev, seen := prices[id]
+			switch {
+			case seen && changed(prev, row.PriceNumeric):
+				lastChange[id] = snap.Date
+				res.LastChangeDate = max(res.LastChangeDate, snap.Date)
+			case !seen && i > 0:
+				// A newly launched variant is not frozen since firstDate
+				lastChange[id] = snap.Date
+			}
+			prices[id] = row.PriceNumeric
+		}
+		if i > 0 && !sameSet(listed, ids) {
+			res.LastListChangeDate = snap.Date
+		}
+		listed = ids
+		if snap.Date <= cutoff {
+			res.CompareDate = snap.Date
+			compare = make(map[string]float64, len(ids))
+			for id := range ids {
+				compare[id] = prices[id]
+			}
+		}
+	}
+	res.DaysSinceChange = since(res.LastChangeDate, res.FirstDate, res.LatestDate)
+
+	byModel := make(map[string]*models.ModelStaleness)
+	var order []string
+	for _, row := range latest.Rows {
+		id := rowID(brandID, row)
+		if row.PriceNumeric <= 0 || !listed[id] {
+			continue
+		}
+		delete(listed, id) // count duplicate rows once
+		m, ok := byModel[row.Model]
+		if !ok {
+			seg := segment.Classify(segment.FromRow(brandID, row))
+			m = &models.ModelStaleness{Model: row.Model, EntryPrice: row.PriceNumeric, Segment: &seg}
+			byModel[row.Model] = m
+			order = append(order, row.Model)
+		}
+		m.Variants++
+		m.EntryPrice = min(m.EntryPrice, row.PriceNumeric)
+		m.LastChangeDate = max(m.LastChangeDate, lastChange[id])
+		res.Rows++
+		if before, ok := compare[id]; ok {
+			res.ComparedRows++
+			if !changed(before, row.PriceNumeric) {
+				res.UnchangedRows++
+				m.UnchangedVariants++
+			}
+		}
+	}
+	res.UnchangedPercent = market.Round(percent(res.UnchangedRows, res.ComparedRows), 1)
+
+	// Without a snapshot a full window back nothing can be called stale yet
+	covered := res.CompareDate != ""
+	for _, name := range order {
+		m := byModel[name]
+		m.DaysSinceChange = since(m.LastChangeDate, res.FirstDate, res.LatestDate)
+		m.UnchangedPercent = market.Round(percent(m.UnchangedVariants, m.Variants), 1)
+		m.Stale = covered && m.DaysSinceChange >= days
+		if m.Stale {
+			res.StaleModels++
+		}
+		res.Models = append(res.Models, *m)
+	}
+	sort.SliceStable(res.Models, func(i, j int) bool {
+		if res.Models[i].DaysSinceChange != res.Models[j].DaysSinceChange {
+			return res.Models[i].DaysSinceChange > res.Models[j].DaysSinceChange
+		}
+		return res.Models[i].Model < res.Models[j].Model
+	})
+
+	listStill := res.LastListChangeDate == "" || res.LastListChangeDate <= cutoff
+	res.Frozen = covered && res.DaysSinceChange >= days && listStill
+	return res
+}
+
+// rowID identifies a row's variant the way the importer does
+func rowID(brandID string, row models.PriceListRow) string {
+	if row.VariantID != "" {
+		return row.VariantID
+	}
+	return variant.ID(brandID, variant.RowKey(row))
+}
+
+// changed reports whether a price moved by at least MinChangePercent
+func changed(before, after float64) bool {
+	if before <= 0 {
+		return after != before
+	}
+	delta := (after - before) / before * 100
+	return delta >= MinChangePercent || delta <= -MinChangePercent
+}
+
+func sameSet(a, b map[string]bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for id := range b {
+		if !a[id] {
+			return false
+		}
+	}
+	return true
+}
+
+func percent(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total) * 100
+}
+
+// since returns the days from the last change (the first snapshot without
+// one) to the latest snapshot
+func since(lastChange, first, latest string) int {
+	from := lastChange
+	if from == "" {
+		from = first
+	}
+	a, err1 := time.Parse(dateLayout, from)
+	b, err2 := time.Parse(dateLayout, latest)
+	if err1 != nil || err2 != nil {
+		return 0
+	}
+	return int(b.Sub(a).Hours() / 24)
+}
+
+// shift moves a date by a number of days
+func shift(date string, days int) string {
+	t, err := time.Parse(dateLayout, date)
+	if err != nil {
+		return ""
+	}
+	return t.AddDate(0, 0, days).Format(dateLayout)
+}
@@ -0,0 +1,108 @@
+package validation
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"github.com/spehlivan/price-list/backend/internal/variant"
+)
+
+// AnomalyThresholds bound the plausible day-over-day change of a brand snapshot.
+// Each value is a fraction (0.4 = 40%); zero disables the check.
+type AnomalyThresholds struct {
+	MaxRowCountDrop      float64 // growth is not checked; lineups expand legitimately
+	MaxMedianPriceChange float64
+	MaxVanishedShare     float64
+}
+
+// DefaultAnomalyThresholds catch PDF parse shifts and stale fallbacks without
+// tripping on regular price list updates
+var DefaultAnomalyThresholds = AnomalyThresholds{
+	MaxRowCountDrop:      0.4,
+	MaxMedianPriceChange: 0.3,
+	MaxVanishedShare:     0.4,
+}
+
+// CompareSnapshots computes day-over-day metrics of curr against the previous
+// accepted snapshot prev and lists the thresholds it exceeds
+func CompareSnapshots(prev, curr *models.VehicleDocument, t AnomalyThresholds) models.SnapshotComparison {
+	cmp := models.SnapshotComparison{
+		PreviousDate:     prev.Date,
+		PreviousRowCount: len(prev.Rows),
+		RowCount:         len(curr.Rows),
+		PreviousMedian:   medianPrice(prev.Rows),
+		Median:           medianPrice(curr.Rows),
+		Violations:       []string{},
+	}
+	cmp.RowCountChange = relativeChange(float64(cmp.PreviousRowCount), float64(cmp.RowCount))
+	cmp.MedianPriceChange = relativeChange(cmp.PreviousMedian, cmp.Median)
+
+	current := make(map[string]bool, len(curr.Rows))
+	for i := range curr.Rows {
+		current[rowKey(&curr.Rows[i])] = true
+	}
+	previous := make(map[string]bool, len(prev.Rows))
+	for i := range prev.Rows {
+		key := rowKey(&prev.Rows[i])
+		if previous[key] {
+			continue
+		}
+		previous[key] = true
+		if !current[key] {
+			cmp.VanishedVariants++
+		}
+	}
+	if len(previous) > 0 {
+		cmp.VanishedShare = float64(cmp.VanishedVariants) / float64(len(previous))
+	}
+
+	if t.MaxRowCountDrop > 0 && -cmp.RowCountChange > t.MaxRowCountDrop {
+		cmp.Violations = append(cmp.Violations, fmt.Sprintf("row count dropped %.0f%% (%d -> %d) since %s",
+			-cmp.RowCountChange*100, cmp.PreviousRowCount, cmp.RowCount, prev.Date))
+	}
+	if t.MaxMedianPriceChange > 0 && math.Abs(cmp.MedianPriceChange) > t.MaxMedianPriceChange {
+		cmp.Violations = append(cmp.Violations, fmt.Sprintf("median price changed %+.0f%% (%.0f -> %.0f) since %s",
+			cmp.MedianPriceChange*100, cmp.PreviousMedian, cmp.Median, prev.Date))
+	}
+	if t.MaxVanishedShare > 0 && cmp.VanishedShare > t.MaxVanishedShare {
+		cmp.Violations = append(cmp.Violations, fmt.Sprintf("%.0f%% of variants (%d of %d) vanished since %s",
+			cmp.VanishedShare*100, cmp.VanishedVariants, len(previous), prev.Date))
+	}
+	return cmp
+}
+
+// rowKey identifies a variant within a brand snapshot: the stamped
+// variantKey of imported rows, computed the same way for new ones
+func rowKey(row *models.PriceListRow) string {
+	if row.VariantKey != "" {
+		return row.VariantKey
+	}
+	return variant.RowKey(*row)
+}
+
+func medianPrice(rows []models.PriceListRow) float64 {
+	prices := make([]float64, 0, len(rows))
+	for _, row := range rows {
+		if row.PriceNumeric > 0 {
+			prices = append(prices, row.PriceNumeric)
+		}
+	}
+	if len(prices) == 0 {
+		return 0
+	}
+	sort.Float64s(prices)
+	mid := len(prices) / 2
+	if len(prices)%2 == 0 {
+		return (prices[mid-1] + prices[mid]) / 2
+	}
+	return prices[mid]
+}
+
+func relativeChange(from, to float64) float64 {
+	if from == 0 {
+		return 0
+	}
+	return (to - from) / from
+}
@@ -0,0 +1,77 @@
+// Package validation checks imported price list snapshots against the
+// models.VehicleDocument / models.PriceListRow contract
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spehlivan/price-list/backend/internal/models"
+)
+
+// maxRowReasons caps how many row-level problems are reported per snapshot
+const maxRowReasons = 20
+
+// Snapshot returns the reasons a brand snapshot violates the data contract,
+// or nil if it is valid. brandID and date come from the file's location.
+func Snapshot(doc *models.VehicleDocument, brandID, date string) []string {
+	var reasons []string
+
+	if doc.BrandID != "" && doc.BrandID != brandID {
+		reasons = append(reasons, fmt.Sprintf("brandId %q does not match directory %q", doc.BrandID, brandID))
+	}
+	if strings.TrimSpace(doc.Brand) == "" {
+		reasons = append(reasons, "brand is missing")
+	}
+	if doc.CollectedAt == "" {
+		reasons = append(reasons, "collectedAt is missing")
+	} else if _, err := time.Parse(time.RFC3339, doc.CollectedAt); err != nil {
+		reasons = append(reasons, fmt.Sprintf("collectedAt %q is not an RFC 3339 timestamp", doc.CollectedAt))
+	}
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		reasons = append(reasons, fmt.Sprintf("date %q is not a valid YYYY-MM-DD date", date))
+	}
+	if len(doc.Rows) == 0 {
+		reasons = append(reasons, "rows is empty")
+	}
+	if doc.RowCount != len(doc.Rows) {
+		reasons = append(reasons, fmt.Sprintf("rowCount %d does not match %d rows", doc.RowCount, len(doc.Rows)))
+	}
+
+	rowProblems := 0
+	for i, row := range doc.Rows {
+		for _, problem := range Row(&row) {
+			rowProblems++
+			if rowProblems <= maxRowReasons {
+				reasons = append(reasons, fmt.Sprintf("row %d (%s / %s): %s", i, row.Model, row.Trim, problem))
+			}
+		}
+	}
+	if rowProblems > maxRowReasons {
+		reasons = append(reasons, fmt.Sprintf("... and %d more row problems", rowProblems-maxRowReasons))
+	}
+
+	return reasons
+}
+
+// Row returns the contract violations of a single price list row
+func Row(row *models.PriceListRow) []string {
+	var problems []string
+	if strings.TrimSpace(row.Model) == "" {
+		problems = append(problems, "model is missing")
+	}
+	if strings.TrimSpace(row.Trim) == "" {
+		problems = append(problems, "trim is missing")
+	}
+	if row.PriceNumeric <= 0 {
+		problems = append(problems, fmt.Sprintf("priceNumeric must be positive, got %v", row.PriceNumeric))
+	}
+	if row.PriceListNumeric != nil && *row.PriceListNumeric < 0 {
+		problems = append(problems, fmt.Sprintf("priceListNumeric must not be negative, got %v", *row.PriceListNumeric))
+	}
+	if row.PriceCampaignNumeric != nil && *row.PriceCampaignNumeric < 0 {
+		problems = append(problems, fmt.Sprintf("priceCampaignNumeric must not be negative, got %v", *row.PriceCampaignNumeric))
+	}
+	return problems
+}
@@ -0,0 +1,117 @@
+// Package variant assigns stable identities to vehicle variants. The same car
+// shows up under shifting labels ("1.0 DIG-T 115PS" vs "1.0L DIG-T 115 PS",
+// "LEON" vs "Leon", footnote asterisks on trims), so rows are keyed by a
+// normalized model|trim|engine string. Renames normalization cannot bridge are
+// recorded as aliases from one key onto another; a Resolver follows them to a
+// canonical key whose hash is the variant id.
+package variant
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/spehlivan/price-list/backend/internal/models"
+)
+
+var (
+	// Turkish and other diacritics seen in brand price lists
+	foldReplacer = strings.NewReplacer(
+		"ı", "i", "İ", "i", "ş", "s", "Ş", "s", "ğ", "g", "Ğ", "g",
+		"ü", "u", "Ü", "u", "ö", "o", "Ö", "o", "ç", "c", "Ç", "c",
+		"š", "s", "Š", "s", "ë", "e", "é", "e", "è", "e", "â", "a", "ä", "a",
+	)
+	decimalComma = regexp.MustCompile(`(\d),(\d)`)
+	unitSpacing  = regexp.MustCompile(`(\d)\s*(ps|hp|bg|cv|kw|kwh|cc)\b`)
+	horsepower   = regexp.MustCompile(`(\d)(ps|bg|cv)\b`)
+	litres       = regexp.MustCompile(`(\d\.\d)\s*l\b`)
+)
+
+// Normalize folds a label to its comparable form: lower case without
+// diacritics, decimal points, units glued to their numbers, PS/BG/CV as hp,
+// no litre suffix on displacements and punctuation collapsed to single spaces
+func Normalize(s string) string {
+	s = strings.ToLower(foldReplacer.Replace(s))
+	s = decimalComma.ReplaceAllString(s, "$1.$2")
+	s = unitSpacing.ReplaceAllString(s, "$1$2")
+	s = horsepower.ReplaceAllString(s, "${1}hp")
+	s = litres.ReplaceAllString(s, "$1")
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '.' && r != '+'
+	})
+	for i, f := range fields {
+		fields[i] = strings.Trim(f, ".")
+	}
+	fields = slices.DeleteFunc(fields, func(f string) bool { return f == "" })
+	return strings.Join(fields, " ")
+}
+
+// Key returns the normalized model|trim|engine key. Model years are not part
+// of it, so a year rollover keeps the identity.
+func Key(model, trim, engine string) string {
+	return Normalize(model) + "|" + Normalize(trim) + "|" + Normalize(engine)
+}
+
+// RowKey returns the normalized key of a price list row
+func RowKey(row models.PriceListRow) string {
+	return Key(row.Model, row.Trim, row.Engine)
+}
+
+// ID hashes a brand and canonical key into a short stable variant id
+func ID(brandID, key string) string {
+	sum := sha256.Sum256([]byte(brandID + "|" + key))
+	return "v" + hex.EncodeToString(sum[:6])
+}
+
+// Resolver maps normalized keys onto canonical keys through the alias table
+type Resolver struct {
+	aliases map[string]map[string]string // brandId -> fromKey -> toKey
+}
+
+// NewResolver builds a resolver from the alias table
+func NewResolver(aliases []models.VariantAlias) *Resolver {
+	r := &Resolver{aliases: make(map[string]map[string]string)}
+	for _, a := range aliases {
+		if r.aliases[a.BrandID] == nil {
+			r.aliases[a.BrandID] = make(map[string]string)
+		}
+		r.aliases[a.BrandID][a.FromKey] = a.ToKey
+	}
+	return r
+}
+
+// Canonical follows alias chains from key to the key that identifies the
+// variant. Cycles stop at the first repeated key.
+func (r *Resolver) Canonical(brandID, key string) string {
+	seen := map[string]bool{key: true}
+	for {
+		next, ok := r.aliases[brandID][key]
+		if !ok || seen[next] {
+			return key
+		}
+		seen[next] = true
+		key = next
+	}
+}
+
+// ID returns the variant id of a normalized key
+func (r *Resolver) ID(brandID, key string) string {
+	return ID(brandID, r.Canonical(brandID, key))
+}
+
+// Lineage returns every key that resolves to the same variant as key: the
+// canonical key first, then its aliases in order
+func (r *Resolver) Lineage(brandID, key string) []string {
+	canonical := r.Canonical(brandID, key)
+	keys := []string{canonical}
+	for from := range r.aliases[brandID] {
+		if from != canonical && r.Canonical(brandID, from) == canonical {
+			keys = append(keys, from)
+		}
+	}
+	slices.Sort(keys[1:])
+	return keys
+}
@@ -0,0 +1,143 @@
+// Package vocab maps raw fuel, transmission and drive type strings onto the
+// canonical enums in models. Brands label the same thing differently
+// ("Hibrit" vs "Hybrid", "DSG" vs "Otomatik (DSG)"), so raw values are
+// normalized with variant.Normalize and looked up in a built-in table, which
+// admins extend or override through the vocabulary collection.
+package vocab
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/spehlivan/price-list/backend/internal/models"
+	"github.com/spehlivan/price-list/backend/internal/variant"
+)
+
+// Entry sources
+const (
+	SourceDefault  = "default"
+	SourceOverride = "override"
+)
+
+// defaults is the built-in table, keyed by normalized raw value
+var defaults = map[string]map[string]string{
+	models.VocabFuel: {
+		"benzin": models.FuelPetrol, "petrol": models.FuelPetrol, "gasoline": models.FuelPetrol,
+		"dizel": models.FuelDiesel, "diesel": models.FuelDiesel,
+		"lpg": models.FuelLPG, "benzin lpg": models.FuelLPG,
+		"hybrid": models.FuelHybrid, "hibrit": models.FuelHybrid, "full hybrid": models.FuelHybrid, "hev": models.FuelHybrid,
+		"elektrik benzin": models.FuelHybrid, "benzin elektrik": models.FuelHybrid,
+		"mild hybrid": models.FuelMildHybrid, "mild hibrit": models.FuelMildHybrid, "hafif hibrit": models.FuelMildHybrid, "mhev": models.FuelMildHybrid,
+		"plug in hybrid": models.FuelPlugInHybrid, "plug in hibrit": models.FuelPlugInHybrid, "phev": models.FuelPlugInHybrid,
+		"elektrik": models.FuelElectric, "elektrikli": models.FuelElectric, "electric": models.FuelElectric, "ev": models.FuelElectric,
+		"diger": models.FuelOther, "other": models.FuelOther,
+	},
+	models.VocabTransmission: {
+		"otomatik": models.TransmissionAutomatic, "otomatik vites": models.TransmissionAutomatic, "automatic": models.TransmissionAutomatic,
+		"auto": models.TransmissionAutomatic, "at": models.TransmissionAutomatic, "tiptronik": models.TransmissionAutomatic,
+		"eat6": models.TransmissionAutomatic, "eat8": models.TransmissionAutomatic,
+		"manuel": models.TransmissionManual, "manual": models.TransmissionManual, "duz": models.TransmissionManual, "duz vites": models.TransmissionManual,
+		"mt": models.TransmissionManual, "5mt": models.TransmissionManual, "6mt": models.TransmissionManual, "mt5": models.TransmissionManual, "mt6": models.TransmissionManual,
+		"dsg": models.TransmissionDualClutch, "otomatik dsg": models.TransmissionDualClutch, "dct": models.TransmissionDualClutch,
+		"edc": models.TransmissionDualClutch, "edcs6": models.TransmissionDualClutch, "s tronic": models.TransmissionDualClutch,
+		"cvt": models.TransmissionCVT, "e cvt": models.TransmissionCVT, "ecvt": models.TransmissionCVT, "xtronic": models.TransmissionCVT,
+	},
+	models.VocabDriveType: {
+		"fwd": models.DriveFWD, "onden cekis": models.DriveFWD, "onden cekisli": models.DriveFWD,
+		"rwd": models.DriveRWD, "arkadan itis": models.DriveRWD, "arkadan itisli": models.DriveRWD,
+		"awd": models.DriveAWD, "4wd": models.DriveAWD, "4x4": models.DriveAWD, "4motion": models.DriveAWD,
+		"xdrive": models.DriveAWD, "quattro": models.DriveAWD, "4matic": models.DriveAWD, "all grip": models.DriveAWD,
+	},
+}
+
+// Key normalizes a raw value to its lookup key
+func Key(raw string) string {
+	return variant.Normalize(raw)
+}
+
+// Valid reports whether canonical is an allowed value of field
+func Valid(field, canonical string) bool {
+	return slices.Contains(models.VocabularyValues[field], canonical)
+}
+
+// Table is the effective mapping: the defaults with overrides applied
+type Table struct {
+	entries map[string]map[string]models.VocabularyEntry
+}
+
+// New builds the effective table from the stored overrides
+func New(overrides []models.VocabularyEntry) *Table {
+	t := &Table{entries: make(map[string]map[string]models.VocabularyEntry)}
+	for field, values := range defaults {
+		t.entries[field] = make(map[string]models.VocabularyEntry, len(values))
+		for raw, canonical := range values {
+			t.entries[field][raw] = models.VocabularyEntry{Field: field, Raw: raw, Canonical: canonical, Source: SourceDefault}
+		}
+	}
+	for _, o := range overrides {
+		if t.entries[o.Field] == nil {
+			continue
+		}
+		o.Raw = Key(o.Raw)
+		o.Source = SourceOverride
+		t.entries[o.Field][o.Raw] = o
+	}
+	return t
+}
+
+// Lookup returns the canonical value of a raw value and whether the table has it
+func (t *Table) Lookup(field, raw string) (string, bool) {
+	e, ok := t.entries[field][Key(raw)]
+	return e.Canonical, ok
+}
+
+// Canonical returns the canonical value of a raw value: "" for an empty raw
+// value and models.VocabUnknown for values missing from the table
+func (t *Table) Canonical(field, raw string) string {
+	if Key(raw) == "" {
+		return ""
+	}
+	if canonical, ok := t.Lookup(field, raw); ok {
+		return canonical
+	}
+	return models.VocabUnknown
+}
+
+// Row returns the canonical attributes of a row. Rows without a transmission
+// fall back to the brand-specific transmissionType (e.g. Peugeot's EAT8).
+func (t *Table) Row(row models.PriceListRow) models.CanonicalAttributes {
+	attrs := models.CanonicalAttributes{
+		Fuel:         t.Canonical(models.VocabFuel, row.Fuel),
+		Transmission: t.Canonical(models.VocabTransmission, row.Transmission),
+	}
+	if attrs.Transmission == "" && row.TransmissionType != nil {
+		attrs.Transmission = t.Canonical(models.VocabTransmission, *row.TransmissionType)
+	}
+	if row.DriveType != nil {
+		attrs.DriveType = t.Canonical(models.VocabDriveType, *row.DriveType)
+	}
+	if attrs.Fuel == "" {
+		attrs.Fuel = models.VocabUnknown
+	}
+	if attrs.Transmission == "" {
+		attrs.Transmission = models.VocabUnknown
+	}
+	return attrs
+}
+
+// Entries returns the effective table ordered by field and raw value
+func (t *Table) Entries() []models.VocabularyEntry {
+	var entries []models.VocabularyEntry
+	for _, values := range t.entries {
+		for _, e := range values {
+			entries = append(entries, e)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Field != entries[j].Field {
+			return entries[i].Field < entries[j].Field
+		}
+		return entries[i].Raw < entries[j].Raw
+	})
+	return entries
+}
@@ -11,13 +11,15 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/spehlivan/price-list/backend/config"
-	"github.com/spehlivan/price-list/backend/internal/handlers"
-	"github.com/spehlivan/price-list/backend/internal/middleware"
-	"github.com/spehlivan/price-list/backend/internal/repository"
+	"github.com/spehlivan/price-list/backend/internal/openapi"
+	"github.com/spehlivan/price-list/backend/internal/server"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
+// version is set at build time via -ldflags "-X main.version=..."
+var version = "dev"
+
 func main() {
 	// Load configuration
 	cfg := config.Load()
@@ -43,56 +45,24 @@ func main() {
 
 	db := client.Database(cfg.Database)
 
-	// Initialize repositories
-	vehicleRepo := repository.NewVehicleRepository(db)
-	statsRepo := repository.NewStatsRepository(db)
-	intelRepo := repository.NewIntelRepository(db)
-
 	// Ensure indexes
-	if err := vehicleRepo.EnsureIndexes(context.Background()); err != nil {
-		log.Printf("Warning: Failed to ensure vehicle indexes: %v", err)
-	}
-	if err := statsRepo.EnsureIndexes(context.Background()); err != nil {
-		log.Printf("Warning: Failed to ensure stats indexes: %v", err)
-	}
-	if err := intelRepo.EnsureIndexes(context.Background()); err != nil {
-		log.Printf("Warning: Failed to ensure intel indexes: %v", err)
+	server.EnsureIndexes(context.Background(), cfg, db)
+
+	// Build the OpenAPI document from the documented routes and models
+	spec, err := openapi.Build(version)
+	if err != nil {
+		log.Fatalf("Failed to build OpenAPI spec: %v", err)
 	}
 
-	// Initialize handlers
-	healthHandler := handlers.NewHealthHandler()
-	vehicleHandler := handlers.NewVehicleHandler(vehicleRepo)
-	statsHandler := handlers.NewStatsHandler(statsRepo)
-	intelHandler := handlers.NewIntelHandler(intelRepo)
-
-	// Setup router
-	r := gin.Default()
-
-	// CORS middleware
-	r.Use(middleware.CORSMiddleware(cfg.CORSOrigins))
-
-	// API v1 routes
-	v1 := r.Group("/api/v1")
-	{
-		v1.GET("/health", healthHandler.Health)
-		v1.GET("/index", vehicleHandler.GetIndex)
-		v1.GET("/latest", vehicleHandler.GetLatest)
-		v1.GET("/vehicles", vehicleHandler.GetVehicles)
-		v1.GET("/trend", vehicleHandler.GetTrend)
-		v1.GET("/stats", statsHandler.GetStats)
-
-		// Intel routes
-		intel := v1.Group("/intel")
-		{
-			intel.GET("/events", intelHandler.GetEvents)
-			intel.GET("/architecture", intelHandler.GetArchitecture)
-			intel.GET("/gaps", intelHandler.GetGaps)
-			intel.GET("/promos", intelHandler.GetPromos)
-			intel.GET("/lifecycle", intelHandler.GetLifecycle)
-		}
+	r, err := server.NewRouter(cfg, db, spec)
+	if err != nil {
+		log.Fatalf("Failed to set up router: %v", err)
+	}
 
-		v1.GET("/errors", intelHandler.GetErrors)
-		v1.GET("/insights", intelHandler.GetInsights)
+	// Fail fast when the registered routes drift from the OpenAPI spec; the
+	// openapi package tests catch this before a build ships
+	if err := openapi.Verify(r.Routes()); err != nil {
+		log.Fatalf("OpenAPI spec out of sync with routes: %v", err)
 	}
 
 	// Create HTTP server